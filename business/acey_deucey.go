@@ -0,0 +1,109 @@
+package business
+
+import "fmt"
+
+// ============================================================================
+// Acey-Deucey
+// ============================================================================
+
+// AceyDeucey starts with every checker in hand. Checkers enter into the
+// opponent's home board as from the bar, but others may move while some are
+// still in hand, and none bear off until all are home. A fully played 1-2
+// earns four moves of a number of the player's choice and another roll.
+type AceyDeucey struct{}
+
+// Return the rule set's name
+func (AceyDeucey) Name() string {
+	return RuleSetAceyDeucey
+}
+
+// Return the starting position: an empty board and 15 checkers in each hand
+func (AceyDeucey) StartingPosition() Position {
+	return Position{Board: make([]int, 24), HandWhite: 15, HandBlack: 15}
+}
+
+// The board is laid out as in backgammon
+func (AceyDeucey) BoardPoint(point int, color Color) int {
+	return sidePoint(point, color)
+}
+
+// Return every distinct legal play for the unused dice
+func (a AceyDeucey) LegalPlays(pos Position, color Color, dice []int, diceUsed []bool) []Play {
+	return legalPlays(a, pos, color, dice, diceUsed)
+}
+
+// Return every legal move, entries from the hand and combined moves included
+func (a AceyDeucey) LegalMoves(pos Position, color Color, dice []int, diceUsed []bool) []LegalMove {
+	return sequenceMoves(turnSequences(a, pos, color, dice, diceUsed), dice, diceUsed)
+}
+
+// Check a move against the whole turn and split it into steps
+func (a AceyDeucey) PlayMove(pos Position, color Color, dice []int, diceUsed []bool, move LegalMove) ([]MoveStep, error) {
+	return sequenceMove(turnSequences(a, pos, color, dice, diceUsed), dice, move)
+}
+
+// Make one step. A step from point 0 enters from the bar while a checker is
+// there, and from the hand otherwise.
+func (AceyDeucey) ApplyStep(pos Position, step *MoveStep, color Color) (Position, error) {
+	if step.FromPoint == 0 && pos.BarCount(color) == 0 {
+		if pos.HandCount(color) == 0 {
+			return pos, fmt.Errorf("no checkers left to enter")
+		}
+		if color == ColorWhite {
+			pos.HandWhite--
+			pos.BarWhite++
+		} else {
+			pos.HandBlack--
+			pos.BarBlack++
+		}
+	}
+	return pos.ApplyStep(step, color)
+}
+
+// Return every step one die allows. Hit checkers must still enter before
+// anything else moves.
+func (AceyDeucey) stepMoves(pos Position, color Color, die int) []MoveStep {
+	if pos.BarCount(color) > 0 || pos.HandCount(color) == 0 {
+		return singleDieMoves(pos.Board, color, die, pos.BarCount(color))
+	}
+
+	moves := []MoveStep{}
+	if toPoint := entryPoint(die, color); IsPointOpen(pos.Board, toPoint, color) {
+		moves = append(moves, MoveStep{FromPoint: 0, ToPoint: toPoint, DieUsed: die})
+	}
+	for _, step := range singleDieMoves(pos.Board, color, die, 0) {
+		// Nothing bears off while checkers remain in hand
+		if step.ToPoint != 25 {
+			moves = append(moves, step)
+		}
+	}
+	return moves
+}
+
+// Check whether color has borne off every checker
+func (AceyDeucey) HasWon(pos Position, color Color) bool {
+	return CheckWinCondition(pos, color)
+}
+
+// Work out the result type; a loser with checkers still in hand is
+// backgammoned as if they were on the bar
+func (AceyDeucey) GameResult(pos Position, winner Color) ResultType {
+	loser := winner.Opponent()
+	if pos.BornedOff(loser) == 0 && pos.HandCount(loser) > 0 {
+		return ResultBackgammon
+	}
+	return GameResult(pos, winner)
+}
+
+// Report whether the doubling cube is used
+func (AceyDeucey) UsesCube() bool {
+	return true
+}
+
+// Check whether a roll earns the acey-deucey bonus: a 1-2 with both dice played
+func (AceyDeucey) EarnsBonus(dice []int, diceUsed []bool) bool {
+	if len(dice) != 2 || dice[0]+dice[1] != 3 || dice[0] == dice[1] {
+		return false
+	}
+	return AllDiceUsed(diceUsed)
+}
@@ -0,0 +1,105 @@
+package business
+
+import (
+	"testing"
+)
+
+func TestAceyDeuceyEnterFromHand(t *testing.T) {
+	rules := AceyDeucey{}
+	pos := rules.StartingPosition()
+	if pos.HandCount(ColorWhite) != 15 || pos.HandCount(ColorBlack) != 15 || pos.CheckersLeft(ColorWhite) != 15 {
+		t.Fatalf("got hands of %d and %d", pos.HandWhite, pos.HandBlack)
+	}
+
+	for _, tt := range []struct {
+		color Color
+		to    int
+	}{{ColorWhite, 22}, {ColorBlack, 3}} {
+		step := MoveStep{FromPoint: 0, ToPoint: tt.to, DieUsed: 3}
+		if !hasStep(rules.stepMoves(pos, tt.color, 3), step.FromPoint, step.ToPoint) {
+			t.Fatalf("%s cannot enter on %d with a 3", tt.color, tt.to)
+		}
+		next, err := rules.ApplyStep(pos, &step, tt.color)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next.HandCount(tt.color) != 14 || next.BarCount(tt.color) != 0 || CountCheckersOnPoint(next.Board, tt.to, tt.color) != 1 {
+			t.Fatalf("%s entered to hand %d, bar %d, board %v", tt.color, next.HandCount(tt.color), next.BarCount(tt.color), next.Board)
+		}
+	}
+}
+
+func TestAceyDeuceyStepMoves(t *testing.T) {
+	rules := AceyDeucey{}
+
+	// Checkers already entered may move while others wait in hand, but
+	// nothing bears off until the hand is empty
+	pos := Position{Board: testBoard(map[int]int{2: 1, 10: 1, 22: -2}), HandWhite: 13, HandBlack: 13}
+	steps := rules.stepMoves(pos, ColorWhite, 3)
+	if hasStep(steps, 0, 22) {
+		t.Fatal("entered onto a held point")
+	}
+	if !hasStep(steps, 10, 7) || hasStep(steps, 2, 25) {
+		t.Fatalf("got steps %v", steps)
+	}
+
+	// A hit checker enters before anything else moves
+	pos.BarWhite = 1
+	pos.HandWhite = 12
+	for _, step := range rules.stepMoves(pos, ColorWhite, 4) {
+		if step.FromPoint != 0 {
+			t.Fatalf("moved %d/%d with a checker on the bar", step.FromPoint, step.ToPoint)
+		}
+	}
+
+	// Entering from the hand needs a checker left in it
+	pos = Position{Board: testBoard(map[int]int{10: 15, 22: -15})}
+	step := MoveStep{FromPoint: 0, ToPoint: 21, DieUsed: 4}
+	if _, err := rules.ApplyStep(pos, &step, ColorWhite); err == nil {
+		t.Fatal("entered with an empty hand")
+	}
+}
+
+func TestAceyDeuceyEarnsBonus(t *testing.T) {
+	tests := []struct {
+		name     string
+		dice     []int
+		diceUsed []bool
+		want     bool
+	}{
+		{"1-2 played", []int{1, 2}, []bool{true, true}, true},
+		{"2-1 played", []int{2, 1}, []bool{true, true}, true},
+		{"1-2 half played", []int{1, 2}, []bool{true, false}, false},
+		{"other roll", []int{3, 4}, []bool{true, true}, false},
+		{"doubles", []int{1, 1, 1, 1}, []bool{true, true, true, true}, false},
+	}
+
+	for _, tt := range tests {
+		if got := (AceyDeucey{}).EarnsBonus(tt.dice, tt.diceUsed); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAceyDeuceyGameResult(t *testing.T) {
+	// A loser with checkers still in hand is backgammoned
+	pos := Position{Board: testBoard(map[int]int{12: -10}), HandBlack: 5, BornedOffWhite: 15}
+	if got := (AceyDeucey{}).GameResult(pos, ColorWhite); got != ResultBackgammon {
+		t.Fatalf("got %s, want a backgammon", got)
+	}
+
+	pos = Position{Board: testBoard(map[int]int{12: -15}), BornedOffWhite: 15}
+	if got := (AceyDeucey{}).GameResult(pos, ColorWhite); got != ResultGammon {
+		t.Fatalf("got %s, want a gammon", got)
+	}
+}
+
+func TestBearoffKeyWithCheckersInHand(t *testing.T) {
+	pos := Position{Board: testBoard(map[int]int{3: 2, 22: -2}), HandWhite: 1, BornedOffWhite: 12, BornedOffBlack: 13}
+	if _, ok := bearoffKeyFor(pos, ColorWhite); ok {
+		t.Fatal("bear-off position found with a checker in hand")
+	}
+	if _, ok := bearoffKeyFor(pos, ColorBlack); !ok {
+		t.Fatal("no bear-off position for the side with an empty hand")
+	}
+}
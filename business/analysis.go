@@ -0,0 +1,165 @@
+package business
+
+import (
+	"fmt"
+)
+
+// ============================================================================
+// Game Analysis
+// ============================================================================
+
+// Judgement grades a play by the equity it gave up
+type Judgement string
+
+const (
+	JudgementNone     Judgement = ""
+	JudgementDoubtful Judgement = "doubtful"
+	JudgementError    Judgement = "error"
+	JudgementBlunder  Judgement = "blunder"
+)
+
+// Equity losses at which a play becomes doubtful, an error and a blunder
+const (
+	doubtfulThreshold = 0.04
+	errorThreshold    = 0.08
+	blunderThreshold  = 0.16
+)
+
+// Scale from average equity loss per decision to an error rate, as in the
+// performance rating used by analysis programs
+const errorRateScale = 500
+
+// Return the judgement for a play that lost the given equity
+func JudgePlay(equityLoss float64) Judgement {
+	switch {
+	case equityLoss >= blunderThreshold:
+		return JudgementBlunder
+	case equityLoss >= errorThreshold:
+		return JudgementError
+	case equityLoss >= doubtfulThreshold:
+		return JudgementDoubtful
+	default:
+		return JudgementNone
+	}
+}
+
+// TurnRecord is one turn as it was played
+type TurnRecord struct {
+	Color Color
+	Dice  []int      // The roll, nil when it is not known
+	Moves []MoveStep // The single-die moves made, in order
+}
+
+// TurnAnalysis compares a played turn with the best play for its roll
+type TurnAnalysis struct {
+	Turn       int        `json:"turn"` // Index of the turn in the game, from 1
+	Color      Color      `json:"color"`
+	Dice       []int      `json:"dice"`
+	Moves      []MoveStep `json:"moves"`
+	BestMoves  []MoveStep `json:"bestMoves"`
+	Equity     float64    `json:"equity"`     // Equity after the play made
+	BestEquity float64    `json:"bestEquity"` // Equity after the best play
+	EquityLoss float64    `json:"equityLoss"`
+	Forced     bool       `json:"forced"` // Only one play was possible, so there was no decision
+	Judgement  Judgement  `json:"judgement"`
+}
+
+// PlayerAnalysis sums up one side's checker play over a game
+type PlayerAnalysis struct {
+	Decisions  int     `json:"decisions"` // Turns with more than one possible play
+	Doubtful   int     `json:"doubtful"`
+	Errors     int     `json:"errors"`
+	Blunders   int     `json:"blunders"`
+	EquityLoss float64 `json:"equityLoss"` // Total equity given up
+	ErrorRate  float64 `json:"errorRate"`  // Average loss per decision, scaled by 500
+}
+
+// GameAnalysis is the turn-by-turn review of a game's checker play
+type GameAnalysis struct {
+	Turns   []TurnAnalysis           `json:"turns"`
+	Players map[Color]PlayerAnalysis `json:"players"`
+}
+
+// Replay a game's turns from its starting position and compare every play
+// with the best play the evaluator finds for the same roll. Turns whose roll
+// is unknown are replayed without being judged.
+func AnalyzeGame(evaluator Evaluator, start Position, turns []TurnRecord) (*GameAnalysis, error) {
+	analysis := &GameAnalysis{
+		Turns: []TurnAnalysis{},
+		Players: map[Color]PlayerAnalysis{
+			ColorWhite: {},
+			ColorBlack: {},
+		},
+	}
+
+	pos := start.Clone()
+	for i, turn := range turns {
+		before := pos
+		after, err := replayTurn(pos, turn)
+		if err != nil {
+			return nil, fmt.Errorf("turn %d: %w", i+1, err)
+		}
+		pos = after
+
+		if turn.Dice == nil {
+			continue
+		}
+
+		ranked := RankPlays(evaluator, before, turn.Color, turn.Dice, make([]bool, len(turn.Dice)))
+		if len(ranked) == 0 {
+			continue
+		}
+
+		played := evaluator.Evaluate(after, turn.Color).Equity()
+		best := ranked[0]
+		result := TurnAnalysis{
+			Turn:       i + 1,
+			Color:      turn.Color,
+			Dice:       turn.Dice,
+			Moves:      turn.Moves,
+			BestMoves:  best.Play.Moves,
+			Equity:     played,
+			BestEquity: best.Equity,
+			EquityLoss: max(best.Equity-played, 0),
+			Forced:     len(ranked) == 1,
+		}
+
+		player := analysis.Players[turn.Color]
+		if !result.Forced {
+			result.Judgement = JudgePlay(result.EquityLoss)
+			player.Decisions++
+			player.EquityLoss += result.EquityLoss
+			switch result.Judgement {
+			case JudgementDoubtful:
+				player.Doubtful++
+			case JudgementError:
+				player.Errors++
+			case JudgementBlunder:
+				player.Blunders++
+			}
+		}
+		analysis.Players[turn.Color] = player
+		analysis.Turns = append(analysis.Turns, result)
+	}
+
+	for color, player := range analysis.Players {
+		if player.Decisions > 0 {
+			player.ErrorRate = player.EquityLoss / float64(player.Decisions) * errorRateScale
+		}
+		analysis.Players[color] = player
+	}
+
+	return analysis, nil
+}
+
+// Apply a played turn's moves to a position
+func replayTurn(pos Position, turn TurnRecord) (Position, error) {
+	for _, move := range turn.Moves {
+		next, err := pos.ApplyStep(&move, turn.Color)
+		if err != nil {
+			return pos, fmt.Errorf("cannot replay %d/%d: %w", move.FromPoint, move.ToPoint, err)
+		}
+		pos = next
+	}
+	return pos, nil
+}
@@ -268,8 +268,15 @@ func ExecuteMove(board []int, fromPoint, toPoint int, color Color) (*MoveResult,
 // Legal Moves Generation
 // ============================================================================
 
-// Return all legal moves for the current position
+// Return all legal moves for the current position. Moves that would make it
+// impossible to use the maximum number of dice this turn are excluded.
 func GetLegalMoves(board []int, color Color, dice []int, diceUsed []bool, barCount, bornedOff int) []LegalMove {
+	moves := candidateMoves(board, color, dice, diceUsed, barCount)
+	return filterMaximalMoves(board, color, dice, diceUsed, barCount, moves)
+}
+
+// Return every move that is valid on its own, ignoring whole-turn dice usage
+func candidateMoves(board []int, color Color, dice []int, diceUsed []bool, barCount int) []LegalMove {
 	legalMoves := []LegalMove{}
 
 	// Get available dice with their indices
@@ -363,27 +370,24 @@ func GetLegalMoves(board []int, color Color, dice []int, diceUsed []bool, barCou
 					}
 				} else {
 					// Combined move: validate sequence of moves
-					if trySequentialMove(board, point, combo, color, barCount, canBear) {
-						finalPoint := CalculateToPoint(point, totalValue, color)
+					finalPoint := CalculateToPoint(point, totalValue, color)
+					if finalPoint <= 0 || finalPoint >= 25 {
+						finalPoint = 25
+					}
 
-						// Determine final destination
-						if canBear && (finalPoint <= 0 || finalPoint >= 25) {
-							legalMoves = append(legalMoves, LegalMove{
-								FromPoint:      point,
-								ToPoint:        25,
-								DieUsed:        totalValue,
-								DiceIndices:    indices,
-								IsCombinedMove: true,
-							})
-						} else if finalPoint >= 1 && finalPoint <= 24 {
-							legalMoves = append(legalMoves, LegalMove{
-								FromPoint:      point,
-								ToPoint:        finalPoint,
-								DieUsed:        totalValue,
-								DiceIndices:    indices,
-								IsCombinedMove: true,
-							})
-						}
+					// Combined move: some ordering of the dice must land on open points
+					values := []int{}
+					for _, die := range combo {
+						values = append(values, die.value)
+					}
+					if _, err := ExpandMove(board, color, barCount, point, finalPoint, values); err == nil {
+						legalMoves = append(legalMoves, LegalMove{
+							FromPoint:      point,
+							ToPoint:        finalPoint,
+							DieUsed:        totalValue,
+							DiceIndices:    indices,
+							IsCombinedMove: true,
+						})
 					}
 				}
 			}
@@ -421,48 +425,6 @@ func generateCombinations(dice []indexedDie, n int) [][]indexedDie {
 	return result
 }
 
-// Validate a sequence of moves using multiple dice
-func trySequentialMove(board []int, fromPoint int, dice []indexedDie, color Color, barCount int, canBear bool) bool {
-	currentBoard := make([]int, len(board))
-	copy(currentBoard, board)
-	currentPoint := fromPoint
-
-	// Try each die in sequence
-	for i, die := range dice {
-		toPoint := CalculateToPoint(currentPoint, die.value, color)
-
-		// Last die can bear off
-		if i == len(dice)-1 && canBear && (toPoint <= 0 || toPoint >= 25) {
-			err := ValidateMove(currentBoard, currentPoint, 25, die.value, color, barCount)
-			if err != nil {
-				return false
-			}
-			return true
-		}
-
-		// Regular move must land on valid point
-		if toPoint < 1 || toPoint > 24 {
-			return false
-		}
-
-		err := ValidateMove(currentBoard, currentPoint, toPoint, die.value, color, barCount)
-		if err != nil {
-			return false
-		}
-
-		// Execute the move to update board state for next iteration
-		result, err := ExecuteMove(currentBoard, currentPoint, toPoint, color)
-		if err != nil {
-			return false
-		}
-
-		currentBoard = result.NewBoard
-		currentPoint = toPoint
-	}
-
-	return true
-}
-
 // Check if there are any legal moves available
 func HasLegalMoves(board []int, color Color, dice []int, diceUsed []bool, barCount int) bool {
 	moves := GetLegalMoves(board, color, dice, diceUsed, barCount, 0)
@@ -487,7 +449,8 @@ func AllDiceUsed(diceUsed []bool) bool {
 // Win Condition
 // ============================================================================
 
-// Check if a player has won (all 15 checkers borne off)
-func CheckWinCondition(bornedOff int) bool {
-	return bornedOff >= 15
+// Check if a player has won by bearing off every checker, however many the
+// variant started with
+func CheckWinCondition(pos Position, color Color) bool {
+	return pos.BornedOff(color) > 0 && pos.CheckersLeft(color) == 0
 }
@@ -0,0 +1,373 @@
+package business
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+)
+
+// ============================================================================
+// Bear-off Database
+// ============================================================================
+
+// Most checkers a side has, all of which the full database covers on the
+// home board
+const BearoffCheckers = 15
+
+// Checker counts on a side's home board points, from its ace point
+type bearoffKey [6]uint8
+
+// BearoffDatabase holds exact one-sided bear-off odds for every home board
+// position of up to Checkers checkers, with best play to bear off in fewest rolls
+type BearoffDatabase struct {
+	index map[bearoffKey]int
+	// Checkers is the most checkers a position in the database has
+	Checkers int
+	// Finish[i][n] is the chance position i bears off its last checker on roll n
+	Finish [][]float64
+	// FirstOff[i][n] is the chance position i bears off its first checker on
+	// roll n, playing to do so as soon as possible. Only set for positions
+	// with all Checkers checkers on the board; when that is all 15, a gammon
+	// is still possible.
+	FirstOff [][]float64
+}
+
+// Return every home board position of up to maxCheckers checkers in a fixed order
+func bearoffPositions(maxCheckers int) []bearoffKey {
+	keys := []bearoffKey{}
+	var fill func(key bearoffKey, point, left int)
+	fill = func(key bearoffKey, point, left int) {
+		if point == len(key) {
+			keys = append(keys, key)
+			return
+		}
+		for count := 0; count <= left; count++ {
+			key[point] = uint8(count)
+			fill(key, point+1, left-count)
+		}
+	}
+	fill(bearoffKey{}, 0, maxCheckers)
+	return keys
+}
+
+// Return the number of checkers and the pip count of a home board position
+func (k bearoffKey) size() (checkers, pips int) {
+	for i, count := range k {
+		checkers += int(count)
+		pips += int(count) * (i + 1)
+	}
+	return checkers, pips
+}
+
+// Return a white position with the home board checkers and the rest borne off
+func (k bearoffKey) position() Position {
+	pos := Position{Board: make([]int, 24), BornedOffBlack: BearoffCheckers}
+	checkers, _ := k.size()
+	for i, count := range k {
+		pos.Board[i] = int(count)
+	}
+	pos.BornedOffWhite = BearoffCheckers - checkers
+	return pos
+}
+
+// Return a color's home board position, or false when it has checkers on
+// the bar, in hand or outside its home board
+func bearoffKeyFor(pos Position, color Color) (bearoffKey, bool) {
+	var key bearoffKey
+	if pos.BarCount(color) > 0 || pos.HandCount(color) > 0 {
+		return key, false
+	}
+	for point := 1; point <= 24; point++ {
+		count := CountCheckersOnPoint(pos.Board, point, color)
+		if count == 0 {
+			continue
+		}
+		ownPoint := sidePoint(point, color)
+		if ownPoint > len(key) {
+			return key, false
+		}
+		key[ownPoint-1] = uint8(count)
+	}
+	return key, true
+}
+
+// Work out the database of positions of up to maxCheckers checkers by
+// retrograde analysis: every roll of every position is played to the
+// successor needing the fewest rolls, and successors always have fewer pips
+// so are solved first. Progress, when set, is called as positions are solved.
+func GenerateBearoffDatabase(maxCheckers int, progress func(done, total int)) *BearoffDatabase {
+	keys := bearoffPositions(maxCheckers)
+	sort.SliceStable(keys, func(i, j int) bool {
+		_, pi := keys[i].size()
+		_, pj := keys[j].size()
+		return pi < pj
+	})
+
+	finish := map[bearoffKey][]float64{}
+	firstOff := map[bearoffKey][]float64{}
+	for done, key := range keys {
+		checkers, _ := key.size()
+		if checkers == 0 {
+			finish[key] = []float64{1}
+		} else {
+			finish[key] = solveBearoff(key, finish, false)
+			if checkers == maxCheckers {
+				firstOff[key] = solveBearoff(key, firstOff, true)
+			}
+		}
+		if progress != nil {
+			progress(done+1, len(keys))
+		}
+	}
+
+	db := &BearoffDatabase{Checkers: maxCheckers}
+	for _, key := range bearoffPositions(maxCheckers) {
+		db.Finish = append(db.Finish, finish[key])
+		db.FirstOff = append(db.FirstOff, firstOff[key])
+	}
+	db.buildIndex()
+	return db
+}
+
+// Return the distribution of rolls a position needs, given the solved
+// distributions of every smaller position. With firstOff, a roll that bears
+// off any checker finishes.
+func solveBearoff(key bearoffKey, solved map[bearoffKey][]float64, firstOff bool) []float64 {
+	pos := key.position()
+	dist := []float64{0}
+	for die1 := 1; die1 <= 6; die1++ {
+		for die2 := die1; die2 <= 6; die2++ {
+			weight := 2.0 / 36
+			if die1 == die2 {
+				weight = 1.0 / 36
+			}
+
+			dice := TurnDice(die1, die2)
+			var best []float64
+			bestRolls := -1.0
+			for _, play := range GetLegalPlays(pos, ColorWhite, dice, make([]bool, len(dice))) {
+				if firstOff && play.Result.BornedOffWhite > pos.BornedOffWhite {
+					best = []float64{1}
+					break
+				}
+				next, _ := bearoffKeyFor(play.Result, ColorWhite)
+				rolls := expectedRolls(solved[next])
+				if bestRolls < 0 || rolls < bestRolls {
+					best = solved[next]
+					bestRolls = rolls
+				}
+			}
+
+			// This roll takes one turn, then the successor's rolls follow
+			for n, p := range best {
+				for len(dist) <= n+1 {
+					dist = append(dist, 0)
+				}
+				dist[n+1] += weight * p
+			}
+		}
+	}
+	return dist
+}
+
+// Return the mean of a distribution of rolls
+func expectedRolls(dist []float64) float64 {
+	mean := 0.0
+	for n, p := range dist {
+		mean += float64(n) * p
+	}
+	return mean
+}
+
+// Return the chance of needing at least n rolls
+func rollsAtLeast(dist []float64, n int) float64 {
+	total := 0.0
+	for i := max(n, 0); i < len(dist); i++ {
+		total += dist[i]
+	}
+	return total
+}
+
+// Check the expected rolls of every position of up to maxCheckers checkers
+// against a brute-force search of every roll and play
+func (db *BearoffDatabase) Verify(maxCheckers int) error {
+	memo := map[bearoffKey]float64{}
+	var search func(key bearoffKey) float64
+	search = func(key bearoffKey) float64 {
+		if checkers, _ := key.size(); checkers == 0 {
+			return 0
+		}
+		if rolls, ok := memo[key]; ok {
+			return rolls
+		}
+
+		pos := key.position()
+		rolls := 1.0
+		for die1 := 1; die1 <= 6; die1++ {
+			for die2 := 1; die2 <= 6; die2++ {
+				dice := TurnDice(die1, die2)
+				best := -1.0
+				for _, play := range GetLegalPlays(pos, ColorWhite, dice, make([]bool, len(dice))) {
+					next, _ := bearoffKeyFor(play.Result, ColorWhite)
+					if r := search(next); best < 0 || r < best {
+						best = r
+					}
+				}
+				rolls += best / 36
+			}
+		}
+		memo[key] = rolls
+		return rolls
+	}
+
+	for i, key := range bearoffPositions(db.Checkers) {
+		if checkers, _ := key.size(); checkers > maxCheckers {
+			continue
+		}
+		want := search(key)
+		if got := expectedRolls(db.Finish[i]); math.Abs(got-want) > 1e-9 {
+			return fmt.Errorf("position %v: database expects %.12f rolls, search finds %.12f", key, got, want)
+		}
+	}
+	return nil
+}
+
+// Map each position to its place in the tables
+func (db *BearoffDatabase) buildIndex() {
+	db.index = map[bearoffKey]int{}
+	for i, key := range bearoffPositions(db.Checkers) {
+		db.index[key] = i
+	}
+}
+
+// Load a database written by Save
+func LoadBearoffDatabase(path string) (*BearoffDatabase, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bear-off database: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bear-off database: %w", err)
+	}
+	defer reader.Close()
+
+	var db BearoffDatabase
+	if err := gob.NewDecoder(reader).Decode(&db); err != nil {
+		return nil, fmt.Errorf("failed to decode bear-off database: %w", err)
+	}
+
+	if db.Checkers < 1 || db.Checkers > BearoffCheckers {
+		return nil, fmt.Errorf("bear-off database covers %d checkers, expected 1 to %d", db.Checkers, BearoffCheckers)
+	}
+	positions := len(bearoffPositions(db.Checkers))
+	if len(db.Finish) != positions || len(db.FirstOff) != positions {
+		return nil, fmt.Errorf("bear-off database has %d positions, expected %d", len(db.Finish), positions)
+	}
+	db.buildIndex()
+	return &db, nil
+}
+
+// Write the database to a gzipped file
+func (db *BearoffDatabase) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create bear-off database: %w", err)
+	}
+	defer file.Close()
+
+	writer := gzip.NewWriter(file)
+	if err := gob.NewEncoder(writer).Encode(db); err != nil {
+		return fmt.Errorf("failed to encode bear-off database: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write bear-off database: %w", err)
+	}
+	return file.Close()
+}
+
+// Return a color's place in the tables, or false when it is not bearing off
+// or has more checkers than the database covers
+func (db *BearoffDatabase) lookup(pos Position, color Color) (int, bool) {
+	key, ok := bearoffKeyFor(pos, color)
+	if !ok {
+		return 0, false
+	}
+	i, ok := db.index[key]
+	return i, ok
+}
+
+// Return the chances of a color bearing off its first checker on each roll,
+// or nil when it has already borne one off and so cannot be gammoned
+func (db *BearoffDatabase) firstOff(pos Position, color Color, i int) []float64 {
+	if pos.BornedOff(color) > 0 {
+		return nil
+	}
+	return db.FirstOff[i]
+}
+
+// Return the expected rolls color needs to bear off, or false when it is
+// not bearing off
+func (db *BearoffDatabase) ExpectedRolls(pos Position, color Color) (float64, bool) {
+	i, ok := db.lookup(pos, color)
+	if !ok {
+		return 0, false
+	}
+	return expectedRolls(db.Finish[i]), true
+}
+
+// Return the exact probabilities for color, with the opponent on roll, when
+// both sides are bearing off
+func (db *BearoffDatabase) Evaluate(pos Position, color Color) (Probabilities, bool) {
+	own, ok := db.lookup(pos, color)
+	if !ok {
+		return Probabilities{}, false
+	}
+	opp, ok := db.lookup(pos, color.Opponent())
+	if !ok {
+		return Probabilities{}, false
+	}
+	ownFirstOff := db.firstOff(pos, color, own)
+	oppFirstOff := db.firstOff(pos, color.Opponent(), opp)
+
+	// The opponent, on roll, wins by finishing on roll n before color's nth
+	var p Probabilities
+	oppWins := 0.0
+	for n, chance := range db.Finish[opp] {
+		oppWins += chance * rollsAtLeast(db.Finish[own], n)
+		if len(ownFirstOff) > 0 {
+			p.LoseGammon += chance * rollsAtLeast(ownFirstOff, n)
+		}
+	}
+	p.Win = min(max(1-oppWins, 0), 1)
+
+	// Color wins by finishing on roll n before the opponent's (n+1)th
+	if len(oppFirstOff) > 0 {
+		for n, chance := range db.Finish[own] {
+			p.WinGammon += chance * rollsAtLeast(oppFirstOff, n+1)
+		}
+	}
+	return p, true
+}
+
+// BearoffEvaluator judges positions where both sides are bearing off exactly
+// and leaves every other position to its fallback
+type BearoffEvaluator struct {
+	Database *BearoffDatabase
+	Fallback Evaluator
+}
+
+// Evaluate a position for color with the opponent on roll
+func (e BearoffEvaluator) Evaluate(pos Position, color Color) Probabilities {
+	if !gameOver(Backgammon{}, pos) {
+		if p, ok := e.Database.Evaluate(pos, color); ok {
+			return p
+		}
+	}
+	return e.Fallback.Evaluate(pos, color)
+}
@@ -0,0 +1,253 @@
+package business
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Most checkers the test databases cover; the full 15-checker database is too
+// slow to build on every test run
+const testBearoffCheckers = 4
+
+// Return the chance, found by trying every roll and play, that a position
+// bears off all its checkers in a single roll
+func bruteForceOneRoll(key bearoffKey) float64 {
+	pos := key.position()
+	chance := 0.0
+	for die1 := 1; die1 <= 6; die1++ {
+		for die2 := 1; die2 <= 6; die2++ {
+			dice := TurnDice(die1, die2)
+			for _, play := range GetLegalPlays(pos, ColorWhite, dice, make([]bool, len(dice))) {
+				if play.Result.BornedOffWhite == BearoffCheckers {
+					chance += 1.0 / 36
+					break
+				}
+			}
+		}
+	}
+	return chance
+}
+
+// Return the expected rolls, found by trying every roll and play, a position
+// needs to bear off its first checker
+func bruteForceFirstOff(key bearoffKey, memo map[bearoffKey]float64) float64 {
+	if rolls, ok := memo[key]; ok {
+		return rolls
+	}
+
+	pos := key.position()
+	rolls := 1.0
+	for die1 := 1; die1 <= 6; die1++ {
+		for die2 := 1; die2 <= 6; die2++ {
+			dice := TurnDice(die1, die2)
+			best := -1.0
+			for _, play := range GetLegalPlays(pos, ColorWhite, dice, make([]bool, len(dice))) {
+				r := 0.0
+				if play.Result.BornedOffWhite == pos.BornedOffWhite {
+					next, _ := bearoffKeyFor(play.Result, ColorWhite)
+					r = bruteForceFirstOff(next, memo)
+				}
+				if best < 0 || r < best {
+					best = r
+				}
+			}
+			rolls += best / 36
+		}
+	}
+	memo[key] = rolls
+	return rolls
+}
+
+// Return the sum of a distribution
+func totalChance(dist []float64) float64 {
+	total := 0.0
+	for _, p := range dist {
+		total += p
+	}
+	return total
+}
+
+func TestGenerateBearoffDatabaseMatchesBruteForce(t *testing.T) {
+	db := GenerateBearoffDatabase(testBearoffCheckers, nil)
+	if err := db.Verify(testBearoffCheckers); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGenerateBearoffDatabaseFinish(t *testing.T) {
+	db := GenerateBearoffDatabase(testBearoffCheckers, nil)
+	for i, key := range bearoffPositions(testBearoffCheckers) {
+		if checkers, _ := key.size(); checkers == 0 {
+			continue
+		}
+
+		dist := db.Finish[i]
+		if math.Abs(totalChance(dist)-1) > 1e-9 {
+			t.Errorf("position %v: finish chances sum to %.12f", key, totalChance(dist))
+		}
+		if dist[0] != 0 {
+			t.Errorf("position %v: finishes in zero rolls with chance %.12f", key, dist[0])
+		}
+		if want := bruteForceOneRoll(key); math.Abs(dist[1]-want) > 1e-9 {
+			t.Errorf("position %v: finishes in one roll with chance %.12f, brute force finds %.12f", key, dist[1], want)
+		}
+	}
+
+	tests := []struct {
+		name string
+		key  bearoffKey
+		want []float64
+	}{
+		{"one checker on the ace point", bearoffKey{1}, []float64{0, 1}},
+		{"two checkers on the ace point", bearoffKey{2}, []float64{0, 1}},
+		// 1-2, 1-3, 1-4, 2-3 and 1-1 leave the checker short of home
+		{"one checker on the six point", bearoffKey{0, 0, 0, 0, 0, 1}, []float64{0, 27.0 / 36, 9.0 / 36}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := db.Finish[db.index[tt.key]]
+			if len(got) != len(tt.want) {
+				t.Fatalf("got distribution %v, want %v", got, tt.want)
+			}
+			for n := range got {
+				if math.Abs(got[n]-tt.want[n]) > 1e-9 {
+					t.Fatalf("got distribution %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateBearoffDatabaseFirstOff(t *testing.T) {
+	db := GenerateBearoffDatabase(testBearoffCheckers, nil)
+	memo := map[bearoffKey]float64{}
+	for i, key := range bearoffPositions(testBearoffCheckers) {
+		checkers, _ := key.size()
+		if checkers < testBearoffCheckers {
+			if db.FirstOff[i] != nil {
+				t.Errorf("position %v: first-off chances set without every checker on the board", key)
+			}
+			continue
+		}
+
+		dist := db.FirstOff[i]
+		if math.Abs(totalChance(dist)-1) > 1e-9 {
+			t.Errorf("position %v: first-off chances sum to %.12f", key, totalChance(dist))
+		}
+		want := bruteForceFirstOff(key, memo)
+		if got := expectedRolls(dist); math.Abs(got-want) > 1e-9 {
+			t.Errorf("position %v: database expects first checker off in %.12f rolls, search finds %.12f", key, got, want)
+		}
+	}
+}
+
+func TestBearoffDatabaseGammons(t *testing.T) {
+	// With one checker a side, bearing off the first checker ends the game,
+	// so every win is a gammon while nothing has been borne off
+	db := GenerateBearoffDatabase(1, nil)
+	pos := Position{Board: make([]int, 24)}
+	pos.Board[5] = 1
+	pos.Board[20] = -1
+
+	p, ok := db.Evaluate(pos, ColorWhite)
+	if !ok {
+		t.Fatal("position not evaluated")
+	}
+	if p.Win <= 0 || p.Win >= 1 {
+		t.Fatalf("win chance %.6f out of range", p.Win)
+	}
+	if math.Abs(p.WinGammon-p.Win) > 1e-9 || math.Abs(p.LoseGammon-(1-p.Win)) > 1e-9 {
+		t.Fatalf("got %+v, want every result a gammon", p)
+	}
+
+	// Once each side has borne a checker off, no gammon is possible
+	pos.BornedOffWhite, pos.BornedOffBlack = 14, 14
+	if p, _ := db.Evaluate(pos, ColorWhite); p.WinGammon != 0 || p.LoseGammon != 0 {
+		t.Fatalf("got %+v, want no gammons", p)
+	}
+}
+
+func TestBearoffDatabaseSaveAndLoad(t *testing.T) {
+	db := GenerateBearoffDatabase(2, nil)
+	path := filepath.Join(t.TempDir(), "bearoff.db")
+	if err := db.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadBearoffDatabase(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, db) {
+		t.Fatal("loaded database differs from the saved one")
+	}
+}
+
+// Evaluator returning fixed probabilities and counting its calls
+type countingEvaluator struct {
+	calls int
+}
+
+func (e *countingEvaluator) Evaluate(Position, Color) Probabilities {
+	e.calls++
+	return Probabilities{Win: -1}
+}
+
+func TestBearoffEvaluatorOnlyJudgesBearoffs(t *testing.T) {
+	db := GenerateBearoffDatabase(testBearoffCheckers, nil)
+
+	// White bears off from its points 1-6, black from board points 19-24
+	race := func() Position {
+		pos := Position{Board: make([]int, 24), BornedOffWhite: 12, BornedOffBlack: 12}
+		pos.Board[0], pos.Board[2], pos.Board[5] = 1, 1, 1
+		pos.Board[23], pos.Board[21], pos.Board[18] = -1, -1, -1
+		return pos
+	}
+
+	tests := []struct {
+		name  string
+		setup func(pos *Position)
+		exact bool
+	}{
+		{"both sides bearing off", func(*Position) {}, true},
+		{"white checker outside home", func(pos *Position) {
+			pos.Board[5]--
+			pos.Board[6]++
+		}, false},
+		{"black checker outside home", func(pos *Position) {
+			pos.Board[18]++
+			pos.Board[17]--
+		}, false},
+		{"white checker on the bar", func(pos *Position) {
+			pos.Board[5]--
+			pos.BarWhite++
+		}, false},
+		{"more checkers than the database covers", func(pos *Position) {
+			pos.Board[0] += 2
+			pos.BornedOffWhite -= 2
+		}, false},
+		{"game over", func(pos *Position) {
+			pos.Board[0], pos.Board[2], pos.Board[5] = 0, 0, 0
+			pos.BornedOffWhite = 15
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos := race()
+			tt.setup(&pos)
+			fallback := &countingEvaluator{}
+			p := BearoffEvaluator{Database: db, Fallback: fallback}.Evaluate(pos, ColorWhite)
+
+			if exact := fallback.calls == 0; exact != tt.exact {
+				t.Fatalf("exact evaluation used = %v, want %v", exact, tt.exact)
+			}
+			if tt.exact && (p.Win < 0 || p.Win > 1) {
+				t.Fatalf("exact win chance %.6f out of range", p.Win)
+			}
+		})
+	}
+}
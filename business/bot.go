@@ -0,0 +1,150 @@
+package business
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// ============================================================================
+// Computer Player
+// ============================================================================
+
+// BotLevel is the difficulty of a computer player
+type BotLevel string
+
+const (
+	BotEasy   BotLevel = "easy"
+	BotMedium BotLevel = "medium"
+	BotHard   BotLevel = "hard"
+)
+
+// Return the bot level with the given name
+func ParseBotLevel(name string) (BotLevel, error) {
+	switch level := BotLevel(name); level {
+	case BotEasy, BotMedium, BotHard:
+		return level, nil
+	}
+	return "", fmt.Errorf("unknown bot level: %s", name)
+}
+
+// How far a bot's judgement strays from its evaluator at each level
+type botStyle struct {
+	noise        float64 // Spread of the random error added to each play's equity
+	randomChance float64 // Chance of ignoring the scores and playing at random
+	doubleAt     float64 // Winning chance needed to offer a double, 0 never doubles
+	takeAt       float64 // Winning chance needed to take a double
+}
+
+var botStyles = map[BotLevel]botStyle{
+	BotEasy:   {noise: 0.3, randomChance: 0.25, doubleAt: 0, takeAt: 0.1},
+	BotMedium: {noise: 0.08, doubleAt: 0.75, takeAt: 0.2},
+	BotHard:   {doubleAt: 0.7, takeAt: 0.25},
+}
+
+// Bot chooses plays and cube actions for a computer player. It scores whole
+// plays rather than single moves from GetLegalMoves, since only the position
+// after the last die shows what a roll achieved; the plays come from the same
+// move rules, and each checker is then moved through the player's pipeline.
+type Bot struct {
+	Level     BotLevel
+	Evaluator Evaluator
+	Rules     RuleSet // Rules of the game being played, backgammon when nil
+	rng       *rand.Rand
+}
+
+// Create a bot of the given level using the given evaluator
+func NewBot(level BotLevel, evaluator Evaluator, rng *rand.Rand) *Bot {
+	if rng == nil {
+		rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return &Bot{Level: level, Evaluator: evaluator, rng: rng}
+}
+
+// Return the bot's style, falling back to the hardest level
+func (b *Bot) style() botStyle {
+	if style, ok := botStyles[b.Level]; ok {
+		return style
+	}
+	return botStyles[BotHard]
+}
+
+// Return the rules the bot plays by
+func (b *Bot) rules() RuleSet {
+	if b.Rules == nil {
+		return Backgammon{}
+	}
+	return b.Rules
+}
+
+// Choose a play for the unused dice. Returns false when no play is legal.
+func (b *Bot) ChoosePlay(pos Position, color Color, dice []int, diceUsed []bool) (Play, bool) {
+	plays := b.rules().LegalPlays(pos, color, dice, diceUsed)
+	if len(plays) == 0 {
+		return Play{}, false
+	}
+
+	style := b.style()
+	if style.randomChance > 0 && b.rng.Float64() < style.randomChance {
+		return plays[b.rng.IntN(len(plays))], true
+	}
+
+	best := 0
+	bestScore := 0.0
+	for i, play := range plays {
+		score := b.Evaluator.Evaluate(play.Result, color).Equity()
+		if style.noise > 0 {
+			score += b.rng.NormFloat64() * style.noise
+		}
+		if i == 0 || score > bestScore {
+			best = i
+			bestScore = score
+		}
+	}
+	return plays[best], true
+}
+
+// Choose the number to play four times for an Acey-Deucey bonus: the one
+// whose best play leaves the best position. The bonus doubles are followed by
+// another roll, so positions are judged with the bot still on roll, as for a
+// cube decision, and doubles with no legal play leave the position as it is.
+func (b *Bot) ChooseDoubles(pos Position, color Color) int {
+	if style := b.style(); style.randomChance > 0 && b.rng.Float64() < style.randomChance {
+		return b.rng.IntN(6) + 1
+	}
+
+	onRoll := func(pos Position) float64 {
+		return b.Evaluator.Evaluate(pos, color.Opponent()).Flip().Equity()
+	}
+	stayingPut := onRoll(pos)
+
+	best := 0
+	bestScore := 0.0
+	for die := 1; die <= 6; die++ {
+		dice := TurnDice(die, die)
+		score := stayingPut
+		for i, play := range b.rules().LegalPlays(pos, color, dice, make([]bool, len(dice))) {
+			if equity := onRoll(play.Result); i == 0 || equity > score {
+				score = equity
+			}
+		}
+		if best == 0 || score > bestScore {
+			best = die
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// Decide whether to double before rolling, with color on roll
+func (b *Bot) ShouldDouble(pos Position, color Color) bool {
+	style := b.style()
+	if style.doubleAt == 0 {
+		return false
+	}
+	return b.Evaluator.Evaluate(pos, color.Opponent()).Flip().Win >= style.doubleAt
+}
+
+// Decide whether to take a double offered by the opponent, who is on roll
+func (b *Bot) ShouldTake(pos Position, color Color) bool {
+	return b.Evaluator.Evaluate(pos, color).Win >= b.style().takeAt
+}
@@ -0,0 +1,129 @@
+package business
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+// Evaluator judging positions by the race alone, so sending a checker back
+// or moving further is always better
+type raceEvaluator struct{}
+
+func (raceEvaluator) Evaluate(pos Position, color Color) Probabilities {
+	own, opp := countSides(Backgammon{}, pos, color)
+	return Probabilities{Win: 0.5 + float64(opp.pips()-own.pips())/1000}
+}
+
+// Evaluator returning the same probabilities for every position
+type fixedEvaluator Probabilities
+
+func (e fixedEvaluator) Evaluate(Position, Color) Probabilities {
+	return Probabilities(e)
+}
+
+// Return a bot with a fixed random source
+func testBot(level BotLevel, evaluator Evaluator) *Bot {
+	return NewBot(level, evaluator, rand.New(rand.NewPCG(1, 2)))
+}
+
+func TestParseBotLevel(t *testing.T) {
+	for _, name := range []string{"easy", "medium", "hard"} {
+		if level, err := ParseBotLevel(name); err != nil || string(level) != name {
+			t.Errorf("ParseBotLevel(%q) = %q, %v", name, level, err)
+		}
+	}
+	if _, err := ParseBotLevel("expert"); err == nil {
+		t.Error("unknown level accepted")
+	}
+}
+
+func TestBotChoosePlay(t *testing.T) {
+	// 24/18* hits the black blot, sending it 20 pips back
+	pos := Position{Board: testBoard(map[int]int{24: 1, 13: 2, 18: -1, 1: -2}), BornedOffWhite: 12, BornedOffBlack: 12}
+
+	play, ok := testBot(BotHard, raceEvaluator{}).ChoosePlay(pos, ColorWhite, []int{6, 5}, []bool{false, false})
+	if !ok {
+		t.Fatal("no play chosen")
+	}
+	if play.Result.BarBlack != 1 {
+		t.Fatalf("chose %v, want a play hitting the blot", play.Moves)
+	}
+}
+
+func TestBotChoosesLegalPlays(t *testing.T) {
+	pos := StartingPosition()
+	dice := []int{6, 5}
+	legal := map[string]bool{}
+	for _, play := range GetLegalPlays(pos, ColorWhite, dice, []bool{false, false}) {
+		legal[play.Result.Key()] = true
+	}
+
+	for _, level := range []BotLevel{BotEasy, BotMedium, BotHard} {
+		bot := testBot(level, HeuristicEvaluator{})
+		for range 20 {
+			play, ok := bot.ChoosePlay(pos, ColorWhite, dice, []bool{false, false})
+			if !ok || !legal[play.Result.Key()] {
+				t.Fatalf("%s bot chose %v, not a legal play", level, play.Moves)
+			}
+		}
+	}
+}
+
+func TestBotChoosePlayWithNoLegalPlay(t *testing.T) {
+	pos := Position{Board: testBoard(map[int]int{13: 1, 8: -2, 7: -2}), BornedOffWhite: 14, BornedOffBlack: 11}
+	if _, ok := testBot(BotHard, raceEvaluator{}).ChoosePlay(pos, ColorWhite, []int{6, 5}, []bool{false, false}); ok {
+		t.Fatal("chose a play with none legal")
+	}
+}
+
+func TestBotChooseDoubles(t *testing.T) {
+	tests := []struct {
+		name  string
+		board map[int]int
+		want  int
+	}{
+		{"sixes go furthest", map[int]int{24: 1, 1: -2}, 6},
+		{"blocked sixes", map[int]int{24: 1, 18: -2}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos := Position{Board: testBoard(tt.board), BornedOffWhite: 14, BornedOffBlack: 13}
+			if got := testBot(BotHard, raceEvaluator{}).ChooseDoubles(pos, ColorWhite); got != tt.want {
+				t.Fatalf("chose %ds, want %ds", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBotCubeDecisions(t *testing.T) {
+	tests := []struct {
+		name       string
+		level      BotLevel
+		onRollWins float64 // Bot's winning chance when on roll
+		double     bool
+		take       bool
+	}{
+		{"hard doubles a strong position", BotHard, 0.8, true, true},
+		{"hard holds a fair position", BotHard, 0.6, false, true},
+		{"hard passes a hopeless position", BotHard, 0.1, false, false},
+		{"easy never doubles", BotEasy, 0.95, false, true},
+		{"medium waits for a stronger position", BotMedium, 0.72, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The evaluator sees the position from the side not on roll
+			bot := testBot(tt.level, fixedEvaluator{Win: 1 - tt.onRollWins})
+			if got := bot.ShouldDouble(StartingPosition(), ColorWhite); got != tt.double {
+				t.Errorf("doubles = %v, want %v", got, tt.double)
+			}
+
+			// Taking is judged with the doubler on roll
+			bot = testBot(tt.level, fixedEvaluator{Win: tt.onRollWins})
+			if got := bot.ShouldTake(StartingPosition(), ColorWhite); got != tt.take {
+				t.Errorf("takes = %v, want %v", got, tt.take)
+			}
+		})
+	}
+}
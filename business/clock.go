@@ -0,0 +1,107 @@
+package business
+
+import (
+	"time"
+)
+
+// ============================================================================
+// Game Clocks
+// ============================================================================
+
+// TimeControl is a Bronstein-style time control: each turn may use up to Delay
+// without cost, and only time beyond it is taken from the player's Reserve.
+type TimeControl struct {
+	Delay   time.Duration
+	Reserve time.Duration
+}
+
+// Check whether the time control limits the players at all
+func (tc TimeControl) Timed() bool {
+	return tc.Delay > 0 || tc.Reserve > 0
+}
+
+// Clock tracks both players' reserves. Players are identified by ID and 0
+// means no clock is running.
+type Clock struct {
+	Player1ID      int
+	Player2ID      int
+	Player1Reserve time.Duration // Reserve at the start of the running turn
+	Player2Reserve time.Duration
+	Running        int       // Player whose clock is running
+	StartedAt      time.Time // When the running player's clock started
+}
+
+// Start a clock with a full reserve for both players and no clock running
+func NewClock(tc TimeControl, player1ID, player2ID int) Clock {
+	return Clock{
+		Player1ID:      player1ID,
+		Player2ID:      player2ID,
+		Player1Reserve: tc.Reserve,
+		Player2Reserve: tc.Reserve,
+	}
+}
+
+// Return the reserve time charged to the running player so far
+func (c Clock) charged(tc TimeControl, now time.Time) time.Duration {
+	if c.Running == 0 {
+		return 0
+	}
+	elapsed := now.Sub(c.StartedAt) - tc.Delay
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed
+}
+
+// Return a player's reserve left at the given time (negative once expired)
+func (c Clock) Remaining(tc TimeControl, playerID int, now time.Time) time.Duration {
+	reserve := c.Player1Reserve
+	if playerID == c.Player2ID {
+		reserve = c.Player2Reserve
+	}
+	if playerID == c.Running {
+		reserve -= c.charged(tc, now)
+	}
+	return reserve
+}
+
+// Return the delay left in the running player's turn
+func (c Clock) DelayRemaining(tc TimeControl, now time.Time) time.Duration {
+	if c.Running == 0 {
+		return 0
+	}
+	left := tc.Delay - now.Sub(c.StartedAt)
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// Check whether the running player has used up their delay and reserve
+func (c Clock) Expired(tc TimeControl, now time.Time) bool {
+	return c.Running != 0 && c.Remaining(tc, c.Running, now) < 0
+}
+
+// Stop the running player's clock, charging the time used beyond the delay,
+// and start playerID's clock (0 leaves both stopped). Switching to the
+// player whose clock is already running leaves the clock unchanged.
+func (c Clock) Switch(tc TimeControl, playerID int, now time.Time) Clock {
+	if playerID != 0 && playerID == c.Running {
+		return c
+	}
+
+	charged := c.charged(tc, now)
+	switch c.Running {
+	case c.Player1ID:
+		c.Player1Reserve = max(c.Player1Reserve-charged, 0)
+	case c.Player2ID:
+		c.Player2Reserve = max(c.Player2Reserve-charged, 0)
+	}
+
+	c.Running = playerID
+	c.StartedAt = now
+	if playerID == 0 {
+		c.StartedAt = time.Time{}
+	}
+	return c
+}
@@ -0,0 +1,111 @@
+package business
+
+import (
+	"fmt"
+)
+
+// ============================================================================
+// Doubling Cube
+// ============================================================================
+
+// CubeState describes the doubling cube. Players are identified by ID and 0
+// means unset (a centered cube, or no pending offer).
+type CubeState struct {
+	Value     int  // Current stake multiplier
+	Owner     int  // Player who may double next, 0 when centered
+	OfferedBy int  // Player whose double awaits a response
+	Beavered  bool // The last action was a beaver; the original doubler may raccoon
+}
+
+// Check that a player may offer a double. Doubles are only allowed on the
+// player's own turn before rolling, with the cube centered or owned by them.
+func CanOfferDouble(cube CubeState, playerID int, isPlayersTurn, diceRolled, cubeEnabled bool) error {
+	if !cubeEnabled {
+		return fmt.Errorf("the cube is not in play for this game")
+	}
+	if !isPlayersTurn {
+		return fmt.Errorf("can only double on your own turn")
+	}
+	if diceRolled {
+		return fmt.Errorf("must double before rolling")
+	}
+	if cube.OfferedBy != 0 {
+		return fmt.Errorf("a double is already pending")
+	}
+	if cube.Owner != 0 && cube.Owner != playerID {
+		return fmt.Errorf("your opponent owns the cube")
+	}
+	return nil
+}
+
+// Offer a double to the opponent
+func OfferDouble(cube CubeState, playerID int, isPlayersTurn, diceRolled, cubeEnabled bool) (CubeState, error) {
+	if err := CanOfferDouble(cube, playerID, isPlayersTurn, diceRolled, cubeEnabled); err != nil {
+		return cube, err
+	}
+
+	cube.OfferedBy = playerID
+	cube.Beavered = false
+	return cube, nil
+}
+
+// Check that a player may respond to the pending double
+func checkDoubleResponse(cube CubeState, playerID int) error {
+	if cube.OfferedBy == 0 {
+		return fmt.Errorf("no double has been offered")
+	}
+	if cube.OfferedBy == playerID {
+		return fmt.Errorf("cannot respond to your own double")
+	}
+	return nil
+}
+
+// Take the pending double: the stake doubles and the taker owns the cube
+func TakeDouble(cube CubeState, playerID int) (CubeState, error) {
+	if err := checkDoubleResponse(cube, playerID); err != nil {
+		return cube, err
+	}
+
+	return CubeState{Value: cube.Value * 2, Owner: playerID}, nil
+}
+
+// Drop the pending double, conceding the game. Returns the stake the game is
+// lost at, which is the cube value before the double.
+func DropDouble(cube CubeState, playerID int) (int, error) {
+	if err := checkDoubleResponse(cube, playerID); err != nil {
+		return 0, err
+	}
+
+	return cube.Value, nil
+}
+
+// Beaver the pending double: take it and immediately redouble, keeping the cube
+func BeaverDouble(cube CubeState, playerID int, allowBeavers bool) (CubeState, error) {
+	if !allowBeavers {
+		return cube, fmt.Errorf("beavers are not allowed in this game")
+	}
+	if err := checkDoubleResponse(cube, playerID); err != nil {
+		return cube, err
+	}
+
+	return CubeState{Value: cube.Value * 4, Owner: playerID, Beavered: true}, nil
+}
+
+// Raccoon a beaver: the original doubler redoubles again before rolling and
+// takes the cube back
+func RaccoonDouble(cube CubeState, playerID int, allowBeavers, isPlayersTurn, diceRolled bool) (CubeState, error) {
+	if !allowBeavers {
+		return cube, fmt.Errorf("raccoons are not allowed in this game")
+	}
+	if !cube.Beavered {
+		return cube, fmt.Errorf("there is no beaver to raccoon")
+	}
+	if !isPlayersTurn || cube.Owner == playerID {
+		return cube, fmt.Errorf("only the original doubler may raccoon")
+	}
+	if diceRolled {
+		return cube, fmt.Errorf("must raccoon before rolling")
+	}
+
+	return CubeState{Value: cube.Value * 2, Owner: playerID}, nil
+}
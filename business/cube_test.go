@@ -0,0 +1,132 @@
+package business
+
+import (
+	"testing"
+)
+
+const (
+	testPlayer1 = 1
+	testPlayer2 = 2
+)
+
+func TestCanOfferDouble(t *testing.T) {
+	tests := []struct {
+		name          string
+		cube          CubeState
+		isPlayersTurn bool
+		diceRolled    bool
+		cubeEnabled   bool
+		wantErr       bool
+	}{
+		{"centered cube before rolling", CubeState{Value: 1}, true, false, true, false},
+		{"owned cube before rolling", CubeState{Value: 2, Owner: testPlayer1}, true, false, true, false},
+		{"cube not in play", CubeState{Value: 1}, true, false, false, true},
+		{"opponent's turn", CubeState{Value: 1}, false, false, true, true},
+		{"after rolling", CubeState{Value: 1}, true, true, true, true},
+		{"opponent owns the cube", CubeState{Value: 2, Owner: testPlayer2}, true, false, true, true},
+		{"double already pending", CubeState{Value: 1, OfferedBy: testPlayer2}, true, false, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CanOfferDouble(tt.cube, testPlayer1, tt.isPlayersTurn, tt.diceRolled, tt.cubeEnabled)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTakeAndDropScoring(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  int
+		result ResultType
+		take   int // Points the game is then won at
+		drop   int // Points the doubler wins on a drop
+	}{
+		{"initial double, single", 1, ResultSingle, 2, 1},
+		{"initial double, gammon", 1, ResultGammon, 4, 1},
+		{"redouble, single", 2, ResultSingle, 4, 2},
+		{"redouble, backgammon", 2, ResultBackgammon, 12, 2},
+		{"third double, gammon", 4, ResultGammon, 16, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offered, err := OfferDouble(CubeState{Value: tt.value}, testPlayer1, true, false, true)
+			if err != nil {
+				t.Fatalf("failed to offer double: %v", err)
+			}
+
+			taken, err := TakeDouble(offered, testPlayer2)
+			if err != nil {
+				t.Fatalf("failed to take double: %v", err)
+			}
+			if taken.Owner != testPlayer2 || taken.OfferedBy != 0 {
+				t.Fatalf("after take got %+v, want player 2 owning the cube with no offer", taken)
+			}
+			if got := PointsWon(tt.result, taken.Value); got != tt.take {
+				t.Fatalf("taken game won for %d points, want %d", got, tt.take)
+			}
+
+			stake, err := DropDouble(offered, testPlayer2)
+			if err != nil {
+				t.Fatalf("failed to drop double: %v", err)
+			}
+			if got := PointsWon(ResultSingle, stake); got != tt.drop {
+				t.Fatalf("dropped game won for %d points, want %d", got, tt.drop)
+			}
+		})
+	}
+}
+
+func TestDoubleResponses(t *testing.T) {
+	offered := CubeState{Value: 2, Owner: testPlayer1, OfferedBy: testPlayer1}
+
+	if _, err := TakeDouble(offered, testPlayer1); err == nil {
+		t.Error("doubler took their own double")
+	}
+	if _, err := DropDouble(offered, testPlayer1); err == nil {
+		t.Error("doubler dropped their own double")
+	}
+	if _, err := TakeDouble(CubeState{Value: 2}, testPlayer2); err == nil {
+		t.Error("took a double that was never offered")
+	}
+	if _, err := BeaverDouble(offered, testPlayer2, false); err == nil {
+		t.Error("beavered with beavers not allowed")
+	}
+}
+
+func TestBeaverAndRaccoon(t *testing.T) {
+	offered := CubeState{Value: 2, Owner: testPlayer1, OfferedBy: testPlayer1}
+
+	beavered, err := BeaverDouble(offered, testPlayer2, true)
+	if err != nil {
+		t.Fatalf("failed to beaver: %v", err)
+	}
+	if beavered != (CubeState{Value: 8, Owner: testPlayer2, Beavered: true}) {
+		t.Fatalf("after beaver got %+v", beavered)
+	}
+	if got := PointsWon(ResultGammon, beavered.Value); got != 16 {
+		t.Fatalf("beavered gammon won for %d points, want 16", got)
+	}
+
+	if _, err := RaccoonDouble(beavered, testPlayer2, true, true, false); err == nil {
+		t.Error("beaverer raccooned their own beaver")
+	}
+	if _, err := RaccoonDouble(beavered, testPlayer1, true, true, true); err == nil {
+		t.Error("raccooned after rolling")
+	}
+
+	raccooned, err := RaccoonDouble(beavered, testPlayer1, true, true, false)
+	if err != nil {
+		t.Fatalf("failed to raccoon: %v", err)
+	}
+	if raccooned != (CubeState{Value: 16, Owner: testPlayer1}) {
+		t.Fatalf("after raccoon got %+v", raccooned)
+	}
+	if _, err := RaccoonDouble(raccooned, testPlayer2, true, true, false); err == nil {
+		t.Error("raccooned without a beaver")
+	}
+}
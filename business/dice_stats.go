@@ -0,0 +1,82 @@
+package business
+
+import "math"
+
+// ============================================================================
+// Dice Fairness Statistics
+// ============================================================================
+
+// ChiSquareResult is the outcome of a chi-square goodness-of-fit test
+type ChiSquareResult struct {
+	Statistic        float64 `json:"statistic"`
+	DegreesOfFreedom int     `json:"degreesOfFreedom"`
+	PValue           float64 `json:"pValue"` // Chance of a deviation at least this large from fair dice
+}
+
+// Test observed counts against the counts expected of each category. With
+// nothing observed the test is vacuous and its p-value is 1.
+func ChiSquareTest(observed []int, expected []float64) ChiSquareResult {
+	result := ChiSquareResult{DegreesOfFreedom: len(observed) - 1, PValue: 1}
+	for i, count := range observed {
+		if expected[i] > 0 {
+			diff := float64(count) - expected[i]
+			result.Statistic += diff * diff / expected[i]
+		}
+	}
+	if result.DegreesOfFreedom > 0 && result.Statistic > 0 {
+		result.PValue = upperGammaQ(float64(result.DegreesOfFreedom)/2, result.Statistic/2)
+	}
+	return result
+}
+
+// Return the regularized upper incomplete gamma function Q(a, x), the chance
+// a chi-square variable with 2a degrees of freedom exceeds 2x
+func upperGammaQ(a, x float64) float64 {
+	const (
+		epsilon    = 1e-14
+		iterations = 500
+	)
+
+	lgamma, _ := math.Lgamma(a)
+	scale := math.Exp(a*math.Log(x) - x - lgamma)
+
+	// The series for P(a, x) converges quickly below a+1
+	if x < a+1 {
+		term := 1 / a
+		sum := term
+		for n := 1; n < iterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+		return max(0, 1-sum*scale)
+	}
+
+	// The continued fraction for Q(a, x), by the modified Lentz method
+	const tiny = 1e-300
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < iterations; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return min(1, h*scale)
+}
@@ -0,0 +1,49 @@
+package business
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUpperGammaQ(t *testing.T) {
+	// Tabled chi-square critical values and their upper tail chances
+	tests := []struct {
+		df        int
+		statistic float64
+		want      float64
+	}{
+		{1, 3.8415, 0.05},
+		{2, 2, math.Exp(-1)},
+		{5, 1.6103, 0.90}, // Below a+1, so from the series
+		{5, 11.0705, 0.05},
+		{5, 15.0863, 0.01},
+		{10, 18.3070, 0.05},
+		{35, 49.8018, 0.05},
+	}
+
+	for _, tt := range tests {
+		got := upperGammaQ(float64(tt.df)/2, tt.statistic/2)
+		if math.Abs(got-tt.want) > 1e-4 {
+			t.Errorf("df %d, statistic %.4f: got p = %.6f, want %.6f", tt.df, tt.statistic, got, tt.want)
+		}
+	}
+}
+
+func TestChiSquareTest(t *testing.T) {
+	// (10-20)²/20 + 0 + (30-20)²/20 = 10 on 2 degrees of freedom
+	result := ChiSquareTest([]int{10, 20, 30}, []float64{20, 20, 20})
+	if result.Statistic != 10 || result.DegreesOfFreedom != 2 {
+		t.Fatalf("got %+v", result)
+	}
+	if math.Abs(result.PValue-math.Exp(-5)) > 1e-9 {
+		t.Fatalf("got p = %.9f, want %.9f", result.PValue, math.Exp(-5))
+	}
+
+	// Counts matching expectation, or no counts at all, never look unfair
+	if result := ChiSquareTest([]int{20, 20, 20}, []float64{20, 20, 20}); result.PValue != 1 {
+		t.Errorf("expected counts: got p = %v, want 1", result.PValue)
+	}
+	if result := ChiSquareTest([]int{0, 0, 0}, []float64{0, 0, 0}); result.PValue != 1 {
+		t.Errorf("nothing observed: got p = %v, want 1", result.PValue)
+	}
+}
@@ -0,0 +1,289 @@
+package business
+
+import (
+	"math"
+)
+
+// ============================================================================
+// Position Evaluation
+// ============================================================================
+
+// Evaluator estimates the outcome of a position for a color when the opponent
+// is on roll, i.e. the position just after color finished a play.
+type Evaluator interface {
+	Evaluate(pos Position, color Color) Probabilities
+}
+
+// Probabilities are the chances of each game result for one side. Gammon
+// chances include backgammons.
+type Probabilities struct {
+	Win            float64 `json:"win"`
+	WinGammon      float64 `json:"winGammon"`
+	WinBackgammon  float64 `json:"winBackgammon"`
+	LoseGammon     float64 `json:"loseGammon"`
+	LoseBackgammon float64 `json:"loseBackgammon"`
+}
+
+// Return the cubeless equity: the points expected to be won per point staked
+func (p Probabilities) Equity() float64 {
+	return 2*p.Win - 1 + p.WinGammon - p.LoseGammon + p.WinBackgammon - p.LoseBackgammon
+}
+
+// Return the same probabilities seen from the opponent's side
+func (p Probabilities) Flip() Probabilities {
+	return Probabilities{
+		Win:            1 - p.Win,
+		WinGammon:      p.LoseGammon,
+		WinBackgammon:  p.LoseBackgammon,
+		LoseGammon:     p.WinGammon,
+		LoseBackgammon: p.WinBackgammon,
+	}
+}
+
+// Return the probabilities of a finished game for the given color
+func resultProbabilities(rules RuleSet, pos Position, color Color) Probabilities {
+	winner := color
+	if !rules.HasWon(pos, color) {
+		winner = color.Opponent()
+	}
+
+	var p Probabilities
+	switch rules.GameResult(pos, winner) {
+	case ResultBackgammon:
+		p = Probabilities{Win: 1, WinGammon: 1, WinBackgammon: 1}
+	case ResultGammon:
+		p = Probabilities{Win: 1, WinGammon: 1}
+	default:
+		p = Probabilities{Win: 1}
+	}
+
+	if winner != color {
+		return p.Flip()
+	}
+	return p
+}
+
+// Check whether either side has won
+func gameOver(rules RuleSet, pos Position) bool {
+	return rules.HasWon(pos, ColorWhite) || rules.HasWon(pos, ColorBlack)
+}
+
+// Count of each color's checkers on points numbered from its own side: index
+// 1-24 is the distance from bearing off and index 25 is the bar
+type sideCounts [26]int
+
+// Return both colors' checker counts, each numbered from its own side. Pinned
+// checkers count on the point they are pinned on.
+func countSides(rules RuleSet, pos Position, color Color) (own, opp sideCounts) {
+	opponent := color.Opponent()
+	for point := 1; point <= 24; point++ {
+		own[rules.BoardPoint(point, color)] = CountCheckersOnPoint(pos.Board, point, color)
+		opp[rules.BoardPoint(point, opponent)] = CountCheckersOnPoint(pos.Board, point, opponent)
+	}
+	pinned, pinnedOpp := countPinned(rules, pos, color)
+	for i := range own {
+		own[i] += pinned[i]
+		opp[i] += pinnedOpp[i]
+	}
+	// Checkers still in hand have as far to go as those on the bar
+	own[25] = pos.BarCount(color) + pos.HandCount(color)
+	opp[25] = pos.BarCount(opponent) + pos.HandCount(opponent)
+	return own, opp
+}
+
+// Return both colors' pinned checkers, each numbered from its own side
+func countPinned(rules RuleSet, pos Position, color Color) (own, opp sideCounts) {
+	if pos.Pinned == nil {
+		return own, opp
+	}
+	opponent := color.Opponent()
+	for point := 1; point <= 24; point++ {
+		own[rules.BoardPoint(point, color)] = CountCheckersOnPoint(pos.Pinned, point, color)
+		opp[rules.BoardPoint(point, opponent)] = CountCheckersOnPoint(pos.Pinned, point, opponent)
+	}
+	return own, opp
+}
+
+// Return where a color's own point lies when numbered from the other side
+func otherSidePoint(rules RuleSet, point int, color Color) int {
+	return rules.BoardPoint(rules.BoardPoint(point, color), color.Opponent())
+}
+
+// Check whether the sides have passed each other: no checker of either has
+// an opponent checker left ahead of it. Checkers on the bar or in hand have
+// every point ahead.
+func sidesPassed(rules RuleSet, color Color, own, opp sideCounts) bool {
+	if own[25] > 0 || opp[25] > 0 {
+		return false
+	}
+	for i := 1; i <= 24; i++ {
+		if own[i] == 0 {
+			continue
+		}
+		for j := 1; j <= 24; j++ {
+			if opp[j] == 0 {
+				continue
+			}
+			if otherSidePoint(rules, j, color.Opponent()) < i || otherSidePoint(rules, i, color) < j {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Check whether a color's own point lies in the opponent's home board
+func inOpponentHome(rules RuleSet, point int, color Color) bool {
+	return otherSidePoint(rules, point, color) <= 6
+}
+
+// Convert a board point to its distance from bearing off for a color
+func sidePoint(point int, color Color) int {
+	if color == ColorWhite {
+		return point
+	}
+	return 25 - point
+}
+
+// Return the total pips a side needs to bear off
+func (s sideCounts) pips() int {
+	total := 0
+	for i := 1; i <= 25; i++ {
+		total += i * s[i]
+	}
+	return total
+}
+
+// Return the side's rearmost checker, 0 when all are borne off
+func (s sideCounts) rearmost() int {
+	for i := 25; i >= 1; i-- {
+		if s[i] > 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// Chance of a single roll hitting a blot at each distance (1-24) when nothing
+// blocks the way, counted in 36ths
+var shotRolls = [25]int{0, 11, 12, 14, 15, 15, 17, 6, 6, 5, 3, 2, 3, 0, 0, 1, 1, 0, 1, 0, 1, 0, 0, 0, 1}
+
+// HeuristicEvaluator scores a position from hand-tuned features: the race,
+// exposed blots, made points, primes, anchors and checkers on the bar.
+type HeuristicEvaluator struct {
+	Rules RuleSet // Rules of the game being judged, backgammon when nil
+}
+
+// Return the rules positions are judged by
+func (h HeuristicEvaluator) rules() RuleSet {
+	if h.Rules == nil {
+		return Backgammon{}
+	}
+	return h.Rules
+}
+
+// Evaluate a position for color with the opponent on roll. Only the winning
+// chance is estimated; gammons are left at zero.
+func (h HeuristicEvaluator) Evaluate(pos Position, color Color) Probabilities {
+	if gameOver(h.rules(), pos) {
+		return resultProbabilities(h.rules(), pos, color)
+	}
+	return Probabilities{Win: h.winChance(pos, color)}
+}
+
+// Estimate the winning chance of a position still in play
+func (h HeuristicEvaluator) winChance(pos Position, color Color) float64 {
+	rules := h.rules()
+	own, opp := countSides(rules, pos, color)
+	ownPips := float64(own.pips())
+	oppPips := float64(opp.pips())
+
+	// Once the sides have passed each other only the race matters. The
+	// opponent is on roll, which is worth about 4 pips.
+	if sidesPassed(rules, color, own, opp) {
+		lead := (oppPips - ownPips - 4) / math.Max(ownPips, 1)
+		return logistic(lead * 12)
+	}
+
+	score := (oppPips - ownPips) / 12
+	score += pointScore(rules, color, own, opp) - pointScore(rules, color.Opponent(), opp, own)
+	score -= blotRisk(rules, color, own, opp)
+	score += 0.4 * float64(opp[25]-own[25])
+	score += 0.08 * float64(pos.BornedOff(color)-pos.BornedOff(color.Opponent()))
+
+	return logistic(score * 0.6)
+}
+
+// Value the points a side holds: home board points shut an opponent on the
+// bar out, consecutive points form a prime and anchors ease the back game
+func pointScore(rules RuleSet, color Color, side, other sideCounts) float64 {
+	score := 0.0
+	prime := 0
+	longest := 0
+	for i := 1; i <= 24; i++ {
+		if side[i] < 2 {
+			prime = 0
+			continue
+		}
+
+		prime++
+		longest = max(longest, prime)
+		switch {
+		case i <= 6:
+			score += 0.25 + 0.05*float64(i)
+			if other[25] > 0 {
+				score += 0.2
+			}
+		case i <= 9:
+			score += 0.35
+		case inOpponentHome(rules, i, color):
+			// An anchor in the opponent's home board
+			score += 0.3
+		default:
+			score += 0.1
+		}
+	}
+
+	if longest >= 3 {
+		score += 0.3 * float64(longest-2)
+	}
+	return score
+}
+
+// Estimate what a side's blots may cost, weighting each by the chance of
+// being hit and the pips a hit would lose. Fevga has no hitting.
+func blotRisk(rules RuleSet, color Color, side, other sideCounts) float64 {
+	if _, ok := rules.(Fevga); ok {
+		return 0
+	}
+
+	risk := 0.0
+	for i := 1; i <= 24; i++ {
+		if side[i] != 1 {
+			continue
+		}
+
+		// The blot lies on the opponent's point m, and an opponent checker
+		// at j hits it from j-m pips away; from the bar (j=25) it reaches all
+		m := otherSidePoint(rules, i, color)
+		rolls := 0
+		for j := 1; j <= 25; j++ {
+			if other[j] == 0 {
+				continue
+			}
+			distance := j - m
+			if distance >= 1 && distance <= 24 {
+				rolls += shotRolls[distance]
+			}
+		}
+
+		chance := math.Min(float64(rolls), 36) / 36
+		risk += chance * (0.3 + float64(25-i)/40)
+	}
+	return risk
+}
+
+// Map a score to a probability
+func logistic(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
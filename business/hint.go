@@ -0,0 +1,94 @@
+package business
+
+import (
+	"sort"
+)
+
+// ============================================================================
+// Hints
+// ============================================================================
+
+// RankedPlay is a legal play with its evaluation for the player making it
+type RankedPlay struct {
+	Play          Play          `json:"play"`
+	Probabilities Probabilities `json:"probabilities"`
+	Equity        float64       `json:"equity"`     // Cubeless equity after the play
+	EquityLoss    float64       `json:"equityLoss"` // Equity given up against the best play
+}
+
+// Return every legal play for the unused dice, best first
+func RankPlays(evaluator Evaluator, pos Position, color Color, dice []int, diceUsed []bool) []RankedPlay {
+	plays := GetLegalPlays(pos, color, dice, diceUsed)
+
+	ranked := make([]RankedPlay, 0, len(plays))
+	for _, play := range plays {
+		probabilities := evaluator.Evaluate(play.Result, color)
+		ranked = append(ranked, RankedPlay{
+			Play:          play,
+			Probabilities: probabilities,
+			Equity:        probabilities.Equity(),
+		})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Equity > ranked[j].Equity
+	})
+	for i := range ranked {
+		ranked[i].EquityLoss = ranked[0].Equity - ranked[i].Equity
+	}
+	return ranked
+}
+
+// CubeAction is the recommended cube decision
+type CubeAction string
+
+const (
+	CubeNoDouble   CubeAction = "no_double"   // Keep playing at the current stake
+	CubeDoubleTake CubeAction = "double_take" // Double; the opponent should take
+	CubeDoublePass CubeAction = "double_pass" // Double; the opponent should pass
+	CubeTooGood    CubeAction = "too_good"    // Play on for a gammon rather than cash
+)
+
+// CubeHint compares the equities of the cube decisions for the player on
+// roll, each in units of the current stake
+type CubeHint struct {
+	Action           CubeAction    `json:"action"`
+	Probabilities    Probabilities `json:"probabilities"`
+	NoDoubleEquity   float64       `json:"noDoubleEquity"`
+	DoubleTakeEquity float64       `json:"doubleTakeEquity"`
+	DoublePassEquity float64       `json:"doublePassEquity"`
+}
+
+// Work out the cube decision for color, who is on roll. Equities are
+// cubeless, so the value of owning the cube is not counted.
+func CubeDecision(evaluator Evaluator, pos Position, color Color) CubeHint {
+	// The evaluator judges positions with the opponent on roll
+	probabilities := evaluator.Evaluate(pos, color.Opponent()).Flip()
+	equity := probabilities.Equity()
+
+	hint := CubeHint{
+		Probabilities:    probabilities,
+		NoDoubleEquity:   equity,
+		DoubleTakeEquity: 2 * equity,
+		DoublePassEquity: 1,
+	}
+
+	// The opponent answers a double with whichever costs them less
+	doubled := min(hint.DoubleTakeEquity, hint.DoublePassEquity)
+	switch {
+	case hint.NoDoubleEquity > hint.DoublePassEquity:
+		hint.Action = CubeTooGood
+	case doubled <= hint.NoDoubleEquity:
+		hint.Action = CubeNoDouble
+	case hint.ShouldTake():
+		hint.Action = CubeDoubleTake
+	default:
+		hint.Action = CubeDoublePass
+	}
+	return hint
+}
+
+// Check whether the doubler's opponent loses less by taking than by passing
+func (h CubeHint) ShouldTake() bool {
+	return h.DoubleTakeEquity <= h.DoublePassEquity
+}
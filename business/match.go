@@ -0,0 +1,68 @@
+package business
+
+import (
+	"fmt"
+)
+
+// ============================================================================
+// Match Scoring
+// ============================================================================
+
+// MatchScore is the running score of a match played to Length points. Players
+// are identified by ID.
+type MatchScore struct {
+	Length       int
+	Player1ID    int
+	Player2ID    int
+	Player1Score int
+	Player2Score int
+	Crawford     bool // The next game is the Crawford game
+	PostCrawford bool // The Crawford game has been played
+}
+
+// Start a match score at 0-0
+func NewMatchScore(length, player1ID, player2ID int) MatchScore {
+	return MatchScore{Length: length, Player1ID: player1ID, Player2ID: player2ID}
+}
+
+// Record a finished game. The first time a player reaches match point the
+// next game is the Crawford game, in which the cube may not be used; every
+// game after it is post-Crawford.
+func (m MatchScore) AddGame(winnerID, points int) (MatchScore, error) {
+	if m.Winner() != 0 {
+		return m, fmt.Errorf("match is already over")
+	}
+
+	switch winnerID {
+	case m.Player1ID:
+		m.Player1Score += points
+	case m.Player2ID:
+		m.Player2Score += points
+	default:
+		return m, fmt.Errorf("winner must be a player in this match")
+	}
+
+	if m.Winner() != 0 {
+		return m, nil
+	}
+
+	if m.Crawford {
+		m.Crawford = false
+		m.PostCrawford = true
+	} else if !m.PostCrawford && (m.Player1Score == m.Length-1 || m.Player2Score == m.Length-1) {
+		m.Crawford = true
+	}
+
+	return m, nil
+}
+
+// Return the player who has won the match, or 0 while it is still being played
+func (m MatchScore) Winner() int {
+	if m.Player1Score >= m.Length {
+		return m.Player1ID
+	}
+	if m.Player2Score >= m.Length {
+		return m.Player2ID
+	}
+	return 0
+}
@@ -0,0 +1,200 @@
+package business
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// ============================================================================
+// GNU Backgammon Match ID
+// ============================================================================
+
+// Length of a Match ID: the base64 of the 72-bit key
+const matchIDLength = 12
+
+// Largest match length or score a Match ID can hold
+const maxMatchIDScore = 1<<15 - 1
+
+// MatchGameState is the state of the current game recorded in a Match ID
+type MatchGameState int
+
+const (
+	MatchNoGame   MatchGameState = 0 // No game started
+	MatchPlaying  MatchGameState = 1
+	MatchGameOver MatchGameState = 2
+	MatchResigned MatchGameState = 3
+	MatchDropped  MatchGameState = 4 // Ended by a dropped double
+)
+
+// MatchState is the cube, dice and score context a Match ID records.
+// GNU Backgammon's player 0 is white and player 1 is black.
+type MatchState struct {
+	CubeValue     int
+	CubeOwner     Color // Empty when the cube is centered
+	OnRoll        Color // Player whose turn it is to roll or move
+	Turn          Color // Player who must make the next decision, the opponent when answering a double
+	Crawford      bool
+	GameState     MatchGameState
+	DoubleOffered bool
+	Resignation   ResultType // Resignation on offer, empty when none
+	Dice          [2]int     // Zero when the dice have not been rolled
+	MatchLength   int        // Zero for a money game
+	WhiteScore    int
+	BlackScore    int
+}
+
+// Return the GNU Backgammon player number of a color
+func matchPlayer(color Color) uint64 {
+	if color == ColorBlack {
+		return 1
+	}
+	return 0
+}
+
+// Return the color of a GNU Backgammon player number
+func matchColor(player uint64) Color {
+	if player == 1 {
+		return ColorBlack
+	}
+	return ColorWhite
+}
+
+// Resignation values, indexed as stored in a Match ID
+var matchResignations = []ResultType{"", ResultSingle, ResultGammon, ResultBackgammon}
+
+// Fields of a Match ID key as bit offset and width
+var (
+	matchCubeField        = [2]int{0, 4}
+	matchCubeOwnerField   = [2]int{4, 2}
+	matchOnRollField      = [2]int{6, 1}
+	matchCrawfordField    = [2]int{7, 1}
+	matchGameStateField   = [2]int{8, 3}
+	matchTurnField        = [2]int{11, 1}
+	matchDoubledField     = [2]int{12, 1}
+	matchResignationField = [2]int{13, 2}
+	matchDie1Field        = [2]int{15, 3}
+	matchDie2Field        = [2]int{18, 3}
+	matchLengthField      = [2]int{21, 15}
+	matchWhiteScoreField  = [2]int{36, 15}
+	matchBlackScoreField  = [2]int{51, 15}
+)
+
+// Return the GNU Backgammon Match ID of a match state
+func EncodeMatchID(m MatchState) (string, error) {
+	cubeLog := 0
+	for 1<<cubeLog < m.CubeValue {
+		cubeLog++
+	}
+	if m.CubeValue < 1 || 1<<cubeLog != m.CubeValue || cubeLog > 15 {
+		return "", fmt.Errorf("cube value %d is not a power of two", m.CubeValue)
+	}
+	if m.MatchLength < 0 || m.MatchLength > maxMatchIDScore || m.WhiteScore < 0 || m.WhiteScore > maxMatchIDScore || m.BlackScore < 0 || m.BlackScore > maxMatchIDScore {
+		return "", fmt.Errorf("match length and scores must be between 0 and %d", maxMatchIDScore)
+	}
+	if m.Dice[0] < 0 || m.Dice[0] > 6 || m.Dice[1] < 0 || m.Dice[1] > 6 {
+		return "", fmt.Errorf("dice must be between 0 and 6")
+	}
+	if m.GameState < MatchNoGame || m.GameState > MatchDropped {
+		return "", fmt.Errorf("unknown game state %d", m.GameState)
+	}
+
+	resignation := -1
+	for i, result := range matchResignations {
+		if result == m.Resignation {
+			resignation = i
+		}
+	}
+	if resignation < 0 {
+		return "", fmt.Errorf("unknown resignation %q", m.Resignation)
+	}
+
+	owner := uint64(3)
+	if m.CubeOwner != "" {
+		owner = matchPlayer(m.CubeOwner)
+	}
+
+	var key [9]byte
+	putMatchField(key[:], matchCubeField, uint64(cubeLog))
+	putMatchField(key[:], matchCubeOwnerField, owner)
+	putMatchField(key[:], matchOnRollField, matchPlayer(m.OnRoll))
+	putMatchField(key[:], matchCrawfordField, boolBit(m.Crawford))
+	putMatchField(key[:], matchGameStateField, uint64(m.GameState))
+	putMatchField(key[:], matchTurnField, matchPlayer(m.Turn))
+	putMatchField(key[:], matchDoubledField, boolBit(m.DoubleOffered))
+	putMatchField(key[:], matchResignationField, uint64(resignation))
+	putMatchField(key[:], matchDie1Field, uint64(m.Dice[0]))
+	putMatchField(key[:], matchDie2Field, uint64(m.Dice[1]))
+	putMatchField(key[:], matchLengthField, uint64(m.MatchLength))
+	putMatchField(key[:], matchWhiteScoreField, uint64(m.WhiteScore))
+	putMatchField(key[:], matchBlackScoreField, uint64(m.BlackScore))
+	return base64.StdEncoding.EncodeToString(key[:]), nil
+}
+
+// Decode a GNU Backgammon Match ID
+func DecodeMatchID(id string) (MatchState, error) {
+	if len(id) != matchIDLength {
+		return MatchState{}, fmt.Errorf("match ID must be %d characters", matchIDLength)
+	}
+	key, err := base64.StdEncoding.DecodeString(id)
+	if err != nil {
+		return MatchState{}, fmt.Errorf("invalid match ID: %w", err)
+	}
+
+	m := MatchState{
+		CubeValue:     1 << matchField(key, matchCubeField),
+		OnRoll:        matchColor(matchField(key, matchOnRollField)),
+		Crawford:      matchField(key, matchCrawfordField) == 1,
+		GameState:     MatchGameState(matchField(key, matchGameStateField)),
+		Turn:          matchColor(matchField(key, matchTurnField)),
+		DoubleOffered: matchField(key, matchDoubledField) == 1,
+		Resignation:   matchResignations[matchField(key, matchResignationField)],
+		Dice:          [2]int{int(matchField(key, matchDie1Field)), int(matchField(key, matchDie2Field))},
+		MatchLength:   int(matchField(key, matchLengthField)),
+		WhiteScore:    int(matchField(key, matchWhiteScoreField)),
+		BlackScore:    int(matchField(key, matchBlackScoreField)),
+	}
+
+	switch owner := matchField(key, matchCubeOwnerField); owner {
+	case 0, 1:
+		m.CubeOwner = matchColor(owner)
+	case 2:
+		return MatchState{}, fmt.Errorf("invalid match ID: unknown cube owner")
+	}
+	if m.GameState > MatchDropped {
+		return MatchState{}, fmt.Errorf("invalid match ID: unknown game state")
+	}
+	if m.Dice[0] > 6 || m.Dice[1] > 6 || (m.Dice[0] == 0) != (m.Dice[1] == 0) {
+		return MatchState{}, fmt.Errorf("invalid match ID: dice out of range")
+	}
+	return m, nil
+}
+
+// Write a value into a field of a key, least significant bit first
+func putMatchField(key []byte, field [2]int, value uint64) {
+	for i := range field[1] {
+		if value&(1<<i) != 0 {
+			bit := field[0] + i
+			key[bit/8] |= 1 << (bit % 8)
+		}
+	}
+}
+
+// Read a field of a key, least significant bit first
+func matchField(key []byte, field [2]int) uint64 {
+	var value uint64
+	for i := range field[1] {
+		bit := field[0] + i
+		if key[bit/8]&(1<<(bit%8)) != 0 {
+			value |= 1 << i
+		}
+	}
+	return value
+}
+
+// Return 1 for true and 0 for false
+func boolBit(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,206 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+)
+
+// ============================================================================
+// Neural Network Evaluator
+// ============================================================================
+
+// Size of the network input: for each side, four units per point, the bar
+// and the borne-off checkers
+const NetworkInputs = 2 * (24*4 + 2)
+
+// Number of network outputs, one per field of Probabilities
+const networkOutputs = 5
+
+// Network is a feed-forward network with one sigmoid hidden layer and
+// sigmoid outputs for win, gammon and backgammon chances, in the style of
+// TD-Gammon. It is safe for concurrent evaluation but not while training.
+type Network struct {
+	Hidden        int         `json:"hidden"`
+	HiddenWeights [][]float64 `json:"hiddenWeights"` // Hidden x NetworkInputs
+	HiddenBias    []float64   `json:"hiddenBias"`
+	OutputWeights [][]float64 `json:"outputWeights"` // networkOutputs x Hidden
+	OutputBias    []float64   `json:"outputBias"`
+}
+
+// Create a network with small random weights
+func NewNetwork(hidden int, rng *rand.Rand) *Network {
+	n := &Network{
+		Hidden:        hidden,
+		HiddenWeights: make([][]float64, hidden),
+		HiddenBias:    make([]float64, hidden),
+		OutputWeights: make([][]float64, networkOutputs),
+		OutputBias:    make([]float64, networkOutputs),
+	}
+	for h := range n.HiddenWeights {
+		n.HiddenWeights[h] = randomWeights(NetworkInputs, rng)
+	}
+	for o := range n.OutputWeights {
+		n.OutputWeights[o] = randomWeights(hidden, rng)
+	}
+	return n
+}
+
+// Return count weights drawn uniformly from a range scaled to the fan-in
+func randomWeights(count int, rng *rand.Rand) []float64 {
+	scale := 1 / math.Sqrt(float64(count))
+	weights := make([]float64, count)
+	for i := range weights {
+		weights[i] = (rng.Float64()*2 - 1) * scale
+	}
+	return weights
+}
+
+// Load a network from a weights file written by Save
+func LoadNetwork(path string) (*Network, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read weights file: %w", err)
+	}
+
+	var n Network
+	if err := json.Unmarshal(data, &n); err != nil {
+		return nil, fmt.Errorf("failed to parse weights file: %w", err)
+	}
+	if err := n.validate(); err != nil {
+		return nil, fmt.Errorf("invalid weights file: %w", err)
+	}
+
+	return &n, nil
+}
+
+// Write the network's weights to a file
+func (n *Network) Save(path string) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("failed to encode weights: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write weights file: %w", err)
+	}
+	return nil
+}
+
+// Check that the weight matrices have the shapes the network expects
+func (n *Network) validate() error {
+	if n.Hidden < 1 || len(n.HiddenWeights) != n.Hidden || len(n.HiddenBias) != n.Hidden {
+		return fmt.Errorf("hidden layer does not have %d units", n.Hidden)
+	}
+	for _, weights := range n.HiddenWeights {
+		if len(weights) != NetworkInputs {
+			return fmt.Errorf("hidden unit does not have %d inputs", NetworkInputs)
+		}
+	}
+	if len(n.OutputWeights) != networkOutputs || len(n.OutputBias) != networkOutputs {
+		return fmt.Errorf("output layer does not have %d units", networkOutputs)
+	}
+	for _, weights := range n.OutputWeights {
+		if len(weights) != n.Hidden {
+			return fmt.Errorf("output unit does not have %d inputs", n.Hidden)
+		}
+	}
+	return nil
+}
+
+// Encode a position from color's side under a game's rules: its own checkers
+// first, then the opponent's, each numbered from that side's home
+func EncodePosition(rules RuleSet, pos Position, color Color) []float64 {
+	own, opp := countSides(rules, pos, color)
+	inputs := make([]float64, 0, NetworkInputs)
+	inputs = encodeSide(inputs, own, pos.BornedOff(color))
+	inputs = encodeSide(inputs, opp, pos.BornedOff(color.Opponent()))
+	return inputs
+}
+
+// Append one side's units: 1, 2 and 3 checkers on a point switch on one unit
+// each and the fourth counts the checkers beyond three
+func encodeSide(inputs []float64, side sideCounts, bornedOff int) []float64 {
+	for i := 1; i <= 24; i++ {
+		n := side[i]
+		units := [4]float64{}
+		if n >= 1 {
+			units[0] = 1
+		}
+		if n >= 2 {
+			units[1] = 1
+		}
+		if n >= 3 {
+			units[2] = 1
+		}
+		if n > 3 {
+			units[3] = float64(n-3) / 2
+		}
+		inputs = append(inputs, units[:]...)
+	}
+	return append(inputs, float64(side[25])/2, float64(bornedOff)/15)
+}
+
+// Run the network on encoded inputs, returning the hidden activations and
+// the outputs
+func (n *Network) forward(inputs []float64) ([]float64, [networkOutputs]float64) {
+	hidden := make([]float64, n.Hidden)
+	for h, weights := range n.HiddenWeights {
+		sum := n.HiddenBias[h]
+		for i, x := range inputs {
+			if x != 0 {
+				sum += weights[i] * x
+			}
+		}
+		hidden[h] = logistic(sum)
+	}
+
+	var outputs [networkOutputs]float64
+	for o, weights := range n.OutputWeights {
+		sum := n.OutputBias[o]
+		for h, x := range hidden {
+			sum += weights[h] * x
+		}
+		outputs[o] = logistic(sum)
+	}
+	return hidden, outputs
+}
+
+// Evaluate a position for color with the opponent on roll. Networks are
+// trained on backgammon, so positions are read on its board.
+func (n *Network) Evaluate(pos Position, color Color) Probabilities {
+	if gameOver(Backgammon{}, pos) {
+		return resultProbabilities(Backgammon{}, pos, color)
+	}
+
+	_, outputs := n.forward(EncodePosition(Backgammon{}, pos, color))
+	return consistentProbabilities(outputs)
+}
+
+// Convert raw outputs to probabilities, keeping backgammons within gammons
+// and gammons within wins
+func consistentProbabilities(outputs [networkOutputs]float64) Probabilities {
+	p := probabilitiesFromOutputs(outputs)
+	p.WinGammon = min(p.WinGammon, p.Win)
+	p.WinBackgammon = min(p.WinBackgammon, p.WinGammon)
+	p.LoseGammon = min(p.LoseGammon, 1-p.Win)
+	p.LoseBackgammon = min(p.LoseBackgammon, p.LoseGammon)
+	return p
+}
+
+// Map network outputs to probabilities
+func probabilitiesFromOutputs(outputs [networkOutputs]float64) Probabilities {
+	return Probabilities{
+		Win:            outputs[0],
+		WinGammon:      outputs[1],
+		WinBackgammon:  outputs[2],
+		LoseGammon:     outputs[3],
+		LoseBackgammon: outputs[4],
+	}
+}
+
+// Map probabilities to network outputs
+func outputsFromProbabilities(p Probabilities) [networkOutputs]float64 {
+	return [networkOutputs]float64{p.Win, p.WinGammon, p.WinBackgammon, p.LoseGammon, p.LoseBackgammon}
+}
@@ -0,0 +1,130 @@
+package business
+
+import (
+	"math"
+	"math/rand/v2"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Return a small network with fixed starting weights
+func testNetwork() *Network {
+	return NewNetwork(8, rand.New(rand.NewPCG(1, 2)))
+}
+
+// Return the squared distance of outputs from target
+func outputError(outputs, target [networkOutputs]float64) float64 {
+	total := 0.0
+	for o := range outputs {
+		total += (target[o] - outputs[o]) * (target[o] - outputs[o])
+	}
+	return total
+}
+
+func TestEncodePosition(t *testing.T) {
+	inputs := EncodePosition(Backgammon{}, StartingPosition(), ColorWhite)
+	if len(inputs) != NetworkInputs {
+		t.Fatalf("got %d inputs, want %d", len(inputs), NetworkInputs)
+	}
+
+	// Both sides start with the same checkers
+	half := NetworkInputs / 2
+	if !reflect.DeepEqual(inputs[:half], inputs[half:]) {
+		t.Fatal("the sides of the starting position encode differently")
+	}
+	if !reflect.DeepEqual(inputs, EncodePosition(Backgammon{}, StartingPosition(), ColorBlack)) {
+		t.Fatal("the starting position encodes differently for each color")
+	}
+}
+
+func TestNetworkTrainStep(t *testing.T) {
+	n := testNetwork()
+	before := testNetwork()
+	inputs := EncodePosition(Backgammon{}, StartingPosition(), ColorWhite)
+	target := outputsFromProbabilities(Probabilities{Win: 1, WinGammon: 1})
+
+	_, outputs := n.forward(inputs)
+	startError := outputError(outputs, target)
+
+	n.train(inputs, target, 0.1)
+	if reflect.DeepEqual(n, before) {
+		t.Fatal("training step left the weights unchanged")
+	}
+
+	_, outputs = n.forward(inputs)
+	if err := outputError(outputs, target); err >= startError {
+		t.Fatalf("error grew from %.6f to %.6f", startError, err)
+	}
+}
+
+func TestTrainSelfPlay(t *testing.T) {
+	n := testNetwork()
+	before := testNetwork()
+	reports := []int{}
+	opts := TrainingOptions{
+		Games:        2,
+		LearningRate: 0.1,
+		Exploration:  0.1,
+		ReportEvery:  1,
+		Progress:     func(games int) { reports = append(reports, games) },
+	}
+
+	TrainSelfPlay(n, opts, rand.New(rand.NewPCG(3, 4)))
+	if reflect.DeepEqual(n, before) {
+		t.Fatal("self-play left the weights unchanged")
+	}
+	if !reflect.DeepEqual(reports, []int{1, 2}) {
+		t.Fatalf("got progress reports %v, want [1 2]", reports)
+	}
+	if err := n.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same seed trains the same weights
+	again := testNetwork()
+	opts.Progress = nil
+	TrainSelfPlay(again, opts, rand.New(rand.NewPCG(3, 4)))
+	if !reflect.DeepEqual(n, again) {
+		t.Fatal("training with the same seed gave different weights")
+	}
+}
+
+func TestNetworkEvaluate(t *testing.T) {
+	n := testNetwork()
+	p := n.Evaluate(StartingPosition(), ColorWhite)
+	if p.WinGammon > p.Win || p.WinBackgammon > p.WinGammon || p.LoseGammon > 1-p.Win || p.LoseBackgammon > p.LoseGammon {
+		t.Fatalf("inconsistent probabilities %+v", p)
+	}
+
+	// Finished games are scored by their result, not the network
+	pos := Position{Board: testBoard(map[int]int{1: -15}), BornedOffWhite: 15}
+	if p := n.Evaluate(pos, ColorWhite); p != (Probabilities{Win: 1, WinGammon: 1, WinBackgammon: 1}) {
+		t.Fatalf("got %+v for a backgammon win", p)
+	}
+}
+
+func TestNetworkSaveAndLoad(t *testing.T) {
+	n := testNetwork()
+	path := filepath.Join(t.TempDir(), "weights.json")
+	if err := n.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadNetwork(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for h := range n.HiddenWeights {
+		for i := range n.HiddenWeights[h] {
+			if math.Abs(loaded.HiddenWeights[h][i]-n.HiddenWeights[h][i]) > 1e-12 {
+				t.Fatal("loaded weights differ from the saved ones")
+			}
+		}
+	}
+
+	loaded.OutputBias = loaded.OutputBias[:1]
+	if err := loaded.validate(); err == nil {
+		t.Fatal("network with missing output units accepted")
+	}
+}
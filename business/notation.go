@@ -0,0 +1,225 @@
+package business
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Standard Move Notation
+// ============================================================================
+
+// Points of a move from the mover's side: 25 is the bar and 0 is off
+const (
+	notationBar = 25
+	notationOff = 0
+)
+
+// One checker's path through a turn, from the mover's side
+type notationChain struct {
+	points []int
+	hits   []bool // Whether the checker hit at each point after the first
+}
+
+// Return a move's from and to points from the mover's side
+func notationPoints(rules RuleSet, step MoveStep, color Color) (from, to int) {
+	from, to = notationBar, notationOff
+	if step.FromPoint != 0 {
+		from = rules.BoardPoint(step.FromPoint, color)
+	}
+	if step.ToPoint != 25 {
+		to = rules.BoardPoint(step.ToPoint, color)
+	}
+	return from, to
+}
+
+// Return the name of a point from the mover's side
+func notationPointName(point int) string {
+	switch point {
+	case notationBar:
+		return "bar"
+	case notationOff:
+		return "off"
+	}
+	return strconv.Itoa(point)
+}
+
+// Write a turn's single-die moves in standard notation from the mover's side,
+// such as "8/5 6/5", "bar/22*", "6/off" or "13/7(2)". Hops of one checker are
+// joined, keeping the points where it hit.
+func FormatPlay(rules RuleSet, moves []MoveStep, color Color) string {
+	chains := []notationChain{}
+	for _, step := range moves {
+		from, to := notationPoints(rules, step, color)
+
+		joined := false
+		for i := range chains {
+			chain := &chains[i]
+			if chain.points[len(chain.points)-1] == from {
+				chain.points = append(chain.points, to)
+				chain.hits = append(chain.hits, step.HitOpponent)
+				joined = true
+				break
+			}
+		}
+		if !joined {
+			chains = append(chains, notationChain{points: []int{from, to}, hits: []bool{step.HitOpponent}})
+		}
+	}
+
+	// Highest starting point first, as the notation is usually read
+	sort.SliceStable(chains, func(i, j int) bool {
+		a, b := chains[i].points, chains[j].points
+		if a[0] != b[0] {
+			return a[0] > b[0]
+		}
+		return a[len(a)-1] > b[len(b)-1]
+	})
+
+	parts := []string{}
+	counts := map[string]int{}
+	for _, chain := range chains {
+		var part strings.Builder
+		part.WriteString(notationPointName(chain.points[0]))
+		last := len(chain.points) - 1
+		for i := 1; i <= last; i++ {
+			if i < last && !chain.hits[i-1] {
+				continue
+			}
+			part.WriteString("/" + notationPointName(chain.points[i]))
+			if chain.hits[i-1] {
+				part.WriteString("*")
+			}
+		}
+
+		if counts[part.String()] == 0 {
+			parts = append(parts, part.String())
+		}
+		counts[part.String()]++
+	}
+
+	for i, part := range parts {
+		if counts[part] > 1 {
+			parts[i] = fmt.Sprintf("%s(%d)", part, counts[part])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Read a play written in standard notation from the mover's side and return
+// the legal play for the unused dice it describes. Points passed through need
+// not be written, and hits need only be marked to tell apart plays that end
+// with the same checkers.
+func ParsePlay(rules RuleSet, notation string, pos Position, color Color, dice []int, diceUsed []bool) (Play, error) {
+	fields := strings.Fields(strings.ToLower(strings.ReplaceAll(notation, ",", " ")))
+	if len(fields) == 0 {
+		return Play{}, fmt.Errorf("notation is empty")
+	}
+
+	own, _ := countSides(rules, pos, color)
+	own[notationOff] = pos.BornedOff(color)
+	marked := map[int]bool{}
+	for _, field := range fields {
+		points, hits, count, err := parseNotationMove(field)
+		if err != nil {
+			return Play{}, err
+		}
+		for i, hit := range hits {
+			if hit {
+				marked[points[i+1]] = true
+			}
+		}
+		own[points[0]] -= count
+		own[points[len(points)-1]] += count
+	}
+
+	// Find the legal plays that leave the mover's checkers where the notation does
+	candidates := []Play{}
+	for _, play := range rules.LegalPlays(pos, color, dice, diceUsed) {
+		result, _ := countSides(rules, play.Result, color)
+		result[notationOff] = play.Result.BornedOff(color)
+		if result == own {
+			candidates = append(candidates, play)
+		}
+	}
+	if len(candidates) == 0 {
+		return Play{}, fmt.Errorf("%q is not a legal play", notation)
+	}
+
+	for _, play := range candidates {
+		if maps.Equal(playHitPoints(rules, play, color), marked) {
+			return play, nil
+		}
+	}
+	if len(candidates) == 1 && len(marked) == 0 {
+		return candidates[0], nil
+	}
+	return Play{}, fmt.Errorf("%q does not mark the hits of a legal play", notation)
+}
+
+// Parse one move of a play such as "bar/22*", "13/10*/7" or "8/5(2)" into
+// its points from the mover's side, the hits marked after the first point
+// and the number of checkers moved
+func parseNotationMove(field string) (points []int, hits []bool, count int, err error) {
+	count = 1
+	if open := strings.Index(field, "("); open >= 0 {
+		if !strings.HasSuffix(field, ")") {
+			return nil, nil, 0, fmt.Errorf("invalid move %q", field)
+		}
+		count, err = strconv.Atoi(field[open+1 : len(field)-1])
+		if err != nil || count < 1 || count > 4 {
+			return nil, nil, 0, fmt.Errorf("invalid checker count in %q", field)
+		}
+		field = field[:open]
+	}
+
+	parts := strings.Split(field, "/")
+	if len(parts) < 2 {
+		return nil, nil, 0, fmt.Errorf("invalid move %q", field)
+	}
+	for i, part := range parts {
+		hit := strings.HasSuffix(part, "*")
+		part = strings.TrimSuffix(part, "*")
+
+		var point int
+		switch {
+		case part == "bar" && i == 0:
+			point = notationBar
+		case part == "off" && i == len(parts)-1:
+			point = notationOff
+		default:
+			point, err = strconv.Atoi(part)
+			if err != nil || point < 1 || point > 24 {
+				return nil, nil, 0, fmt.Errorf("invalid point %q in %q", part, field)
+			}
+		}
+
+		if i == 0 {
+			if hit {
+				return nil, nil, 0, fmt.Errorf("a hit cannot be marked on the starting point in %q", field)
+			}
+		} else {
+			if point >= points[i-1] {
+				return nil, nil, 0, fmt.Errorf("checkers must move forward in %q", field)
+			}
+			hits = append(hits, hit)
+		}
+		points = append(points, point)
+	}
+	return points, hits, count, nil
+}
+
+// Return the points a play hits on from the mover's side
+func playHitPoints(rules RuleSet, play Play, color Color) map[int]bool {
+	hits := map[int]bool{}
+	for _, step := range play.Moves {
+		if step.HitOpponent {
+			_, to := notationPoints(rules, step, color)
+			hits[to] = true
+		}
+	}
+	return hits
+}
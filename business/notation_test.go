@@ -0,0 +1,84 @@
+package business
+
+import (
+	"testing"
+)
+
+func TestFormatPlay(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules RuleSet
+		pos   Position
+		color Color
+		dice  []int
+		want  string
+	}{
+		{"opening 3-1", Backgammon{}, StartingPosition(), ColorWhite, []int{3, 1}, "8/5 6/5"},
+		{"black's opening 3-1", Backgammon{}, StartingPosition(), ColorBlack, []int{3, 1}, "8/5 6/5"},
+		{"hit from the bar", Backgammon{}, Position{Board: testBoard(map[int]int{2: 5, 3: 1}), BarBlack: 1, BornedOffWhite: 9, BornedOffBlack: 14}, ColorBlack, []int{3, 3, 3, 3}, "bar/22*/13"},
+		{"bearing off doubles", Backgammon{}, Position{Board: testBoard(map[int]int{6: 2, 20: -15}), BornedOffWhite: 13}, ColorWhite, []int{6, 6, 6, 6}, "6/off(2)"},
+		{"Fevga black runs one checker", Fevga{}, Fevga{}.StartingPosition(), ColorBlack, []int{6, 5}, "24/13"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plays := tt.rules.LegalPlays(tt.pos, tt.color, tt.dice, make([]bool, len(tt.dice)))
+			for _, play := range plays {
+				if FormatPlay(tt.rules, play.Moves, tt.color) == tt.want {
+					return
+				}
+			}
+			t.Fatalf("no legal play is written %q", tt.want)
+		})
+	}
+}
+
+func TestNotationRoundTrip(t *testing.T) {
+	hitting := Position{Board: testBoard(map[int]int{24: 2, 13: 5, 8: 3, 6: 5, 19: -2, 10: -1, 12: -5, 1: -1, 5: -1}), BornedOffBlack: 5}
+	pinning := Position{Board: testBoard(map[int]int{24: 13, 10: 2, 7: -1, 1: -14})}
+	tests := []struct {
+		name  string
+		rules RuleSet
+		pos   Position
+	}{
+		{"backgammon start", Backgammon{}, StartingPosition()},
+		{"backgammon hits", Backgammon{}, hitting},
+		{"Plakoto start", Plakoto{}, Plakoto{}.StartingPosition()},
+		{"Plakoto pins", Plakoto{}, pinning},
+		{"Fevga start", Fevga{}, Fevga{}.StartingPosition()},
+	}
+
+	rolls := [][]int{{3, 1}, {6, 5}, {4, 2}, {2, 2}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, color := range []Color{ColorWhite, ColorBlack} {
+				for _, dice := range rolls {
+					if dice[0] == dice[1] {
+						dice = []int{dice[0], dice[0], dice[0], dice[0]}
+					}
+					diceUsed := make([]bool, len(dice))
+					for _, play := range tt.rules.LegalPlays(tt.pos, color, dice, diceUsed) {
+						notation := FormatPlay(tt.rules, play.Moves, color)
+						parsed, err := ParsePlay(tt.rules, notation, tt.pos, color, dice, diceUsed)
+						if err != nil {
+							t.Fatalf("%s %v: %q: %v", color, dice, notation, err)
+						}
+						if parsed.Result.Key() != play.Result.Key() {
+							t.Fatalf("%s %v: %q read back as %q", color, dice, notation, FormatPlay(tt.rules, parsed.Moves, color))
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParsePlayErrors(t *testing.T) {
+	pos := StartingPosition()
+	dice := []int{3, 1}
+	for _, notation := range []string{"", "8/5", "8/9 6/5", "25/22", "8/5 6/5(5)", "8*/5 6/5"} {
+		if _, err := ParsePlay(Backgammon{}, notation, pos, ColorWhite, dice, []bool{false, false}); err == nil {
+			t.Errorf("%q accepted", notation)
+		}
+	}
+}
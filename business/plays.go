@@ -0,0 +1,211 @@
+package business
+
+import (
+	"fmt"
+)
+
+// ============================================================================
+// Position Helpers
+// ============================================================================
+
+// Return the position a standard game starts from
+func StartingPosition() Position {
+	pos, _ := VariantStartingPosition(VariantStandard)
+	return pos
+}
+
+// Return the dice a turn moves with, four for a double
+func TurnDice(die1, die2 int) []int {
+	if die1 == die2 {
+		return []int{die1, die1, die1, die1}
+	}
+	return []int{die1, die2}
+}
+
+// Return a deep copy of the position
+func (p Position) Clone() Position {
+	board := make([]int, len(p.Board))
+	copy(board, p.Board)
+	p.Board = board
+	if p.Pinned != nil {
+		p.Pinned = append([]int(nil), p.Pinned...)
+	}
+	return p
+}
+
+// Return the number of checkers a color has on the bar
+func (p Position) BarCount(color Color) int {
+	if color == ColorWhite {
+		return p.BarWhite
+	}
+	return p.BarBlack
+}
+
+// Return the number of checkers a color has still to enter in Acey-Deucey
+func (p Position) HandCount(color Color) int {
+	if color == ColorWhite {
+		return p.HandWhite
+	}
+	return p.HandBlack
+}
+
+// Return the number of checkers a color has borne off
+func (p Position) BornedOff(color Color) int {
+	if color == ColorWhite {
+		return p.BornedOffWhite
+	}
+	return p.BornedOffBlack
+}
+
+// Return the number of checkers a color still has to bear off, on the
+// points, pinned under the opponent's, on the bar or in hand
+func (p Position) CheckersLeft(color Color) int {
+	left := p.BarCount(color) + p.HandCount(color)
+	for point := 1; point <= 24; point++ {
+		left += CountCheckersOnPoint(p.Board, point, color)
+		if p.Pinned != nil {
+			left += CountCheckersOnPoint(p.Pinned, point, color)
+		}
+	}
+	return left
+}
+
+// Return a key that is equal for identical positions
+func (p Position) Key() string {
+	return fmt.Sprintf("%v|%d|%d|%d|%d|%d|%d|%v", p.Board, p.BarWhite, p.BarBlack, p.BornedOffWhite, p.BornedOffBlack, p.HandWhite, p.HandBlack, p.Pinned)
+}
+
+// Apply a single step for a color and return the resulting position
+func (p Position) ApplyStep(step *MoveStep, color Color) (Position, error) {
+	result, err := ExecuteMove(p.Board, step.FromPoint, step.ToPoint, color)
+	if err != nil {
+		return p, err
+	}
+
+	next := p
+	next.Board = result.NewBoard
+	step.HitOpponent = result.HitOpponent
+
+	if step.FromPoint == 0 {
+		if color == ColorWhite {
+			next.BarWhite--
+		} else {
+			next.BarBlack--
+		}
+	}
+
+	if step.ToPoint == 25 {
+		if color == ColorWhite {
+			next.BornedOffWhite++
+		} else {
+			next.BornedOffBlack++
+		}
+	}
+
+	if result.HitOpponent {
+		if color == ColorWhite {
+			next.BarBlack++
+		} else {
+			next.BarWhite++
+		}
+	}
+
+	return next, nil
+}
+
+// ============================================================================
+// Whole-Turn Play Generation
+// ============================================================================
+
+// Return every distinct legal play for the unused dice. Each play uses the
+// maximum number of dice the position allows (and the larger die when only one
+// of two can be used). Plays that lead to the same position are reported once.
+func GetLegalPlays(pos Position, color Color, dice []int, diceUsed []bool) []Play {
+	return legalPlays(Backgammon{}, pos, color, dice, diceUsed)
+}
+
+// Return every distinct legal play under a ruleset's single-die moves
+func legalPlays(rules stepRules, pos Position, color Color, dice []int, diceUsed []bool) []Play {
+	plays := []Play{}
+	seen := map[string]bool{}
+	for _, play := range turnSequences(rules, pos, color, dice, diceUsed) {
+		key := play.Result.Key()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		plays = append(plays, play)
+	}
+
+	return plays
+}
+
+// Return every order of single-die moves that makes a legal play, including
+// orders that lead to the same position
+func turnSequences(rules stepRules, pos Position, color Color, dice []int, diceUsed []bool) []Play {
+	available := unusedDiceValues(dice, diceUsed)
+	if len(available) == 0 {
+		return []Play{}
+	}
+
+	sequences := []Play{}
+	maxUsed := 0
+
+	var search func(current Position, remaining []int, moves []MoveStep)
+	search = func(current Position, remaining []int, moves []MoveStep) {
+		extended := false
+		tried := map[int]bool{}
+
+		for i, die := range remaining {
+			if tried[die] {
+				continue
+			}
+			tried[die] = true
+
+			for _, step := range rules.stepMoves(current, color, die) {
+				next, err := rules.ApplyStep(current, &step, color)
+				if err != nil {
+					continue
+				}
+				extended = true
+
+				nextMoves := make([]MoveStep, len(moves), len(moves)+1)
+				copy(nextMoves, moves)
+				search(next, removeDieAt(remaining, i), append(nextMoves, step))
+			}
+		}
+
+		// A play is complete once no remaining die can be used
+		if !extended && len(moves) > 0 {
+			if len(moves) > maxUsed {
+				maxUsed = len(moves)
+			}
+			sequences = append(sequences, Play{Moves: moves, Result: current})
+		}
+	}
+
+	search(pos.Clone(), available, []MoveStep{})
+
+	// Only one of two different dice can be played: the larger one is required
+	requiredDie := 0
+	if maxUsed == 1 && len(available) == 2 && available[0] != available[1] {
+		for _, die := range available {
+			if die > requiredDie && len(rules.stepMoves(pos, color, die)) > 0 {
+				requiredDie = die
+			}
+		}
+	}
+
+	maximal := []Play{}
+	for _, play := range sequences {
+		if len(play.Moves) != maxUsed {
+			continue
+		}
+		if requiredDie != 0 && play.Moves[0].DieUsed != requiredDie {
+			continue
+		}
+		maximal = append(maximal, play)
+	}
+
+	return maximal
+}
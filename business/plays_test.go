@@ -0,0 +1,120 @@
+package business
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Return the steps of each play as from, to and die
+func playSteps(plays []Play) [][][3]int {
+	steps := [][][3]int{}
+	for _, play := range plays {
+		moves := [][3]int{}
+		for _, move := range play.Moves {
+			moves = append(moves, [3]int{move.FromPoint, move.ToPoint, move.DieUsed})
+		}
+		steps = append(steps, moves)
+	}
+	return steps
+}
+
+func TestGetLegalPlaysDiceUsage(t *testing.T) {
+	tests := []struct {
+		name  string
+		board map[int]int
+		dice  []int
+		want  [][][3]int
+	}{
+		{
+			name:  "only one die playable uses the larger",
+			board: map[int]int{13: 1, 2: -2},
+			dice:  []int{5, 6},
+			want:  [][][3]int{{{13, 7, 6}}},
+		},
+		{
+			name:  "larger die blocked plays the smaller",
+			board: map[int]int{13: 1, 7: -2, 2: -2},
+			dice:  []int{6, 5},
+			want:  [][][3]int{{{13, 8, 5}}},
+		},
+		{
+			name:  "play that wastes a die is excluded",
+			board: map[int]int{13: 1, 2: 1, 7: -2},
+			dice:  []int{6, 1},
+			want:  [][][3]int{{{13, 12, 1}, {12, 6, 6}}},
+		},
+		{
+			name:  "no die playable",
+			board: map[int]int{13: 1, 8: -2, 7: -2},
+			dice:  []int{6, 5},
+			want:  [][][3]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos := Position{Board: testBoard(tt.board), BornedOffWhite: 15 - len(tt.board), BornedOffBlack: 13}
+			plays := GetLegalPlays(pos, ColorWhite, tt.dice, make([]bool, len(tt.dice)))
+			if got := playSteps(plays); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got plays %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLegalPlaysFromStart(t *testing.T) {
+	tests := []struct {
+		name     string
+		dice     []int
+		diceUsed []bool
+		moves    int
+	}{
+		{"six-five", []int{6, 5}, []bool{false, false}, 2},
+		{"two-one", []int{2, 1}, []bool{false, false}, 2},
+		{"double ones", []int{1, 1, 1, 1}, []bool{false, false, false, false}, 4},
+		{"double sixes with one used", []int{6, 6, 6, 6}, []bool{true, false, false, false}, 3},
+		{"one die left", []int{6, 5}, []bool{true, false}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plays := GetLegalPlays(StartingPosition(), ColorWhite, tt.dice, tt.diceUsed)
+			if len(plays) == 0 {
+				t.Fatal("no plays found")
+			}
+
+			seen := map[string]bool{}
+			for _, play := range plays {
+				if len(play.Moves) != tt.moves {
+					t.Fatalf("play %v uses %d dice, want %d", play.Moves, len(play.Moves), tt.moves)
+				}
+				key := play.Result.Key()
+				if seen[key] {
+					t.Fatalf("play %v repeats an earlier position", play.Moves)
+				}
+				seen[key] = true
+			}
+		})
+	}
+}
+
+func TestGetLegalPlaysCountsDistinctPositions(t *testing.T) {
+	tests := []struct {
+		name string
+		dice []int
+		want int
+	}{
+		// 24/13, 24/18 13/8, 24/18 8/3, 13/7 13/8, 13/7 8/3, 13/2 and 8/2 8/3
+		{"six-five", []int{6, 5}, 7},
+		{"double ones", []int{1, 1, 1, 1}, 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plays := GetLegalPlays(StartingPosition(), ColorWhite, tt.dice, make([]bool, len(tt.dice)))
+			if len(plays) != tt.want {
+				t.Fatalf("got %d plays, want %d", len(plays), tt.want)
+			}
+		})
+	}
+}
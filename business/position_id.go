@@ -0,0 +1,98 @@
+package business
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// GNU Backgammon Position ID
+// ============================================================================
+
+// Length of a Position ID: the base64 of the 80-bit key without padding
+const positionIDLength = 14
+
+// Return the GNU Backgammon Position ID of a position with color on roll.
+// The key lists the checkers of the player on roll and then the opponent's,
+// each from their own ace point to the bar, as a run of ones per checker
+// closed by a zero.
+func EncodePositionID(pos Position, onRoll Color) string {
+	own, opp := countSides(Backgammon{}, pos, onRoll)
+
+	var key [10]byte
+	bit := 0
+	for _, side := range []sideCounts{own, opp} {
+		for point := 1; point <= 25; point++ {
+			for range side[point] {
+				key[bit/8] |= 1 << (bit % 8)
+				bit++
+			}
+			bit++
+		}
+	}
+	return strings.TrimRight(base64.StdEncoding.EncodeToString(key[:]), "=")
+}
+
+// Decode a GNU Backgammon Position ID into a position with color on roll.
+// Checkers missing from the key are counted as borne off.
+func DecodePositionID(id string, onRoll Color) (Position, error) {
+	if len(id) != positionIDLength {
+		return Position{}, fmt.Errorf("position ID must be %d characters", positionIDLength)
+	}
+	key, err := base64.StdEncoding.DecodeString(id + "==")
+	if err != nil {
+		return Position{}, fmt.Errorf("invalid position ID: %w", err)
+	}
+
+	var sides [2]sideCounts
+	bit := 0
+	for s := range sides {
+		total := 0
+		for point := 1; point <= 25; point++ {
+			for bit < 80 && key[bit/8]&(1<<(bit%8)) != 0 {
+				sides[s][point]++
+				bit++
+			}
+			if bit >= 80 {
+				return Position{}, fmt.Errorf("invalid position ID: key is truncated")
+			}
+			bit++
+			total += sides[s][point]
+		}
+		if total > 15 {
+			return Position{}, fmt.Errorf("invalid position ID: more than 15 checkers")
+		}
+	}
+
+	pos := Position{Board: make([]int, 24)}
+	colors := [2]Color{onRoll, onRoll.Opponent()}
+	for s, color := range colors {
+		sign := 1
+		if color == ColorBlack {
+			sign = -1
+		}
+		total := 0
+		for point := 1; point <= 24; point++ {
+			count := sides[s][point]
+			if count == 0 {
+				continue
+			}
+			index := sidePoint(point, color) - 1
+			if pos.Board[index] != 0 {
+				return Position{}, fmt.Errorf("invalid position ID: both sides on point %d", index+1)
+			}
+			pos.Board[index] = sign * count
+			total += count
+		}
+		total += sides[s][25]
+		if color == ColorWhite {
+			pos.BarWhite = sides[s][25]
+			pos.BornedOffWhite = 15 - total
+		} else {
+			pos.BarBlack = sides[s][25]
+			pos.BornedOffBlack = 15 - total
+		}
+	}
+	return pos, nil
+}
@@ -0,0 +1,60 @@
+package business
+
+// ============================================================================
+// Position Statistics
+// ============================================================================
+
+// SideStats summarizes one color's checkers
+type SideStats struct {
+	PipCount     int `json:"pipCount"`     // Pips needed to bear off every checker
+	Blots        int `json:"blots"`        // Points held by a single checker
+	PointsMade   int `json:"pointsMade"`   // Points held by two or more checkers
+	LongestPrime int `json:"longestPrime"` // Most consecutive made points
+	CheckersBack int `json:"checkersBack"` // Checkers on the bar or in the opponent's home board
+}
+
+// PositionStats summarizes both sides of a position
+type PositionStats struct {
+	White SideStats `json:"white"`
+	Black SideStats `json:"black"`
+	Race  bool      `json:"race"` // The sides have passed each other, so no checker can be hit again
+}
+
+// Return the statistics of a position under a game's rules
+func GetPositionStats(rules RuleSet, pos Position) PositionStats {
+	white, black := countSides(rules, pos, ColorWhite)
+	pinnedWhite, pinnedBlack := countPinned(rules, pos, ColorWhite)
+	return PositionStats{
+		White: sideStats(rules, ColorWhite, white, pinnedWhite),
+		Black: sideStats(rules, ColorBlack, black, pinnedBlack),
+		Race:  sidesPassed(rules, ColorWhite, white, black),
+	}
+}
+
+// Return the statistics of one side's checkers. Pinned checkers count toward
+// the pip count but hold no point.
+func sideStats(rules RuleSet, color Color, side, pinned sideCounts) SideStats {
+	stats := SideStats{PipCount: side.pips(), CheckersBack: side[25]}
+	prime := 0
+	for i := 1; i <= 24; i++ {
+		held := side[i] - pinned[i]
+		switch {
+		case held == 1:
+			stats.Blots++
+		case held >= 2:
+			stats.PointsMade++
+		}
+
+		if held >= 2 {
+			prime++
+			stats.LongestPrime = max(stats.LongestPrime, prime)
+		} else {
+			prime = 0
+		}
+
+		if inOpponentHome(rules, i, color) {
+			stats.CheckersBack += side[i]
+		}
+	}
+	return stats
+}
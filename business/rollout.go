@@ -0,0 +1,292 @@
+package business
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// ============================================================================
+// Rollouts
+// ============================================================================
+
+// z-score of a two-sided 95% confidence interval
+const confidenceZ = 1.96
+
+// Most plies a trial plays when no other limit is given, so games that never
+// end cannot stall a rollout
+const defaultRolloutMaxPlies = 1000
+
+// RolloutOptions controls how a position is played out
+type RolloutOptions struct {
+	Trials            int      // Number of games played out
+	Truncation        int      // Plies played before the evaluator judges the position, 0 plays to the end
+	MaxPlies          int      // Plies after which an unfinished trial is judged anyway, 0 uses the default
+	Level             BotLevel // Policy both sides play with
+	VarianceReduction bool     // Subtract the luck of every roll, as judged by the evaluator
+	Workers           int      // Goroutines playing trials, 0 uses every CPU
+	Seed              uint64   // Trial i always rolls the same dice for the same seed
+	Progress          func(trials int)
+}
+
+// RolloutResult is the average outcome of the trials for the side judged
+type RolloutResult struct {
+	Trials         int           `json:"trials"`
+	Probabilities  Probabilities `json:"probabilities"`
+	Equity         float64       `json:"equity"`
+	StdError       float64       `json:"stdError"`      // Standard error of the equity
+	ConfidenceLow  float64       `json:"confidenceLow"` // Bounds of the 95% confidence interval for the equity
+	ConfidenceHigh float64       `json:"confidenceHigh"`
+}
+
+// Play a position out many times for color with the opponent on roll, as
+// the Evaluator interface judges positions. Trials are seeded by their
+// index, so results do not depend on the number of workers.
+func Rollout(ctx context.Context, evaluator Evaluator, pos Position, color Color, opts RolloutOptions) (*RolloutResult, error) {
+	if opts.Trials < 1 {
+		return nil, fmt.Errorf("rollout needs at least one trial")
+	}
+	if opts.Truncation < 0 {
+		return nil, fmt.Errorf("truncation cannot be negative")
+	}
+	if opts.MaxPlies < 0 {
+		return nil, fmt.Errorf("max plies cannot be negative")
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+
+	outcomes := make([]Probabilities, opts.Trials)
+	trials := make(chan int)
+	finished := make(chan struct{}, workers)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for trial := range trials {
+				outcomes[trial] = rolloutTrial(evaluator, pos, color, opts, trial)
+				finished <- struct{}{}
+			}
+		}()
+	}
+
+	// Hand out trials until they run out or the rollout is cancelled
+	go func() {
+		defer close(trials)
+		for trial := range opts.Trials {
+			select {
+			case trials <- trial:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	completed := 0
+	for range finished {
+		completed++
+		if opts.Progress != nil {
+			opts.Progress(completed)
+		}
+	}
+	if completed < opts.Trials {
+		return nil, ctx.Err()
+	}
+
+	return summarizeRollout(outcomes), nil
+}
+
+// Play one trial and return its outcome for color
+func rolloutTrial(evaluator Evaluator, pos Position, color Color, opts RolloutOptions, trial int) Probabilities {
+	rng := rand.New(rand.NewPCG(opts.Seed, uint64(trial)))
+	bot := NewBot(opts.Level, evaluator, rng)
+
+	cutoff := opts.MaxPlies
+	if cutoff == 0 {
+		cutoff = defaultRolloutMaxPlies
+	}
+	if opts.Truncation > 0 {
+		cutoff = min(cutoff, opts.Truncation)
+	}
+
+	var luck Probabilities
+	mover := color.Opponent()
+	for ply := 0; !gameOver(Backgammon{}, pos); ply++ {
+		if ply == cutoff {
+			// The side that just moved is the one the evaluator judges
+			moved := mover.Opponent()
+			return seenBy(evaluator.Evaluate(pos, moved), moved, color).sub(luck)
+		}
+
+		dice := randomRoll(rng)
+		if opts.VarianceReduction {
+			luck = luck.add(rollLuck(evaluator, pos, mover, dice, color))
+		}
+		if play, ok := bot.ChoosePlay(pos, mover, dice, make([]bool, len(dice))); ok {
+			pos = play.Result
+		}
+		mover = mover.Opponent()
+	}
+	return resultProbabilities(Backgammon{}, pos, color).sub(luck)
+}
+
+// Return how much better the roll left the mover than an average roll
+// would have, by the best play for each, seen by color
+func rollLuck(evaluator Evaluator, pos Position, mover Color, dice []int, color Color) Probabilities {
+	var average, actual Probabilities
+	for die1 := 1; die1 <= 6; die1++ {
+		for die2 := die1; die2 <= 6; die2++ {
+			weight := 2.0 / 36
+			if die1 == die2 {
+				weight = 1.0 / 36
+			}
+			outcome := bestOutcome(evaluator, pos, mover, TurnDice(die1, die2))
+			average = average.add(outcome.scale(weight))
+			if min(dice[0], dice[1]) == die1 && max(dice[0], dice[1]) == die2 {
+				actual = outcome
+			}
+		}
+	}
+	return seenBy(actual, mover, color).sub(seenBy(average, mover, color))
+}
+
+// Return the evaluation after the mover's best play with the dice
+func bestOutcome(evaluator Evaluator, pos Position, mover Color, dice []int) Probabilities {
+	ranked := RankPlays(evaluator, pos, mover, dice, make([]bool, len(dice)))
+	if len(ranked) == 0 {
+		return evaluator.Evaluate(pos, mover)
+	}
+	return ranked[0].Probabilities
+}
+
+// Average the trials' outcomes and measure the spread of their equities
+func summarizeRollout(outcomes []Probabilities) *RolloutResult {
+	n := float64(len(outcomes))
+
+	var total Probabilities
+	for _, outcome := range outcomes {
+		total = total.add(outcome)
+	}
+	mean := total.scale(1 / n)
+	equity := mean.Equity()
+
+	variance := 0.0
+	for _, outcome := range outcomes {
+		d := outcome.Equity() - equity
+		variance += d * d
+	}
+	if len(outcomes) > 1 {
+		variance /= n - 1
+	}
+	stdError := math.Sqrt(variance / n)
+
+	return &RolloutResult{
+		Trials:         len(outcomes),
+		Probabilities:  mean,
+		Equity:         equity,
+		StdError:       stdError,
+		ConfidenceLow:  equity - confidenceZ*stdError,
+		ConfidenceHigh: equity + confidenceZ*stdError,
+	}
+}
+
+// Roll out a position for color, who is on roll and has not rolled yet
+func RolloutBeforeRoll(ctx context.Context, evaluator Evaluator, pos Position, color Color, opts RolloutOptions) (*RolloutResult, error) {
+	result, err := Rollout(ctx, evaluator, pos, color.Opponent(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return &RolloutResult{
+		Trials:         result.Trials,
+		Probabilities:  result.Probabilities.Flip(),
+		Equity:         -result.Equity,
+		StdError:       result.StdError,
+		ConfidenceLow:  -result.ConfidenceHigh,
+		ConfidenceHigh: -result.ConfidenceLow,
+	}, nil
+}
+
+// RolledOutPlay is a candidate play with the rollout of the position it leads to
+type RolledOutPlay struct {
+	Play       Play           `json:"play"`
+	Result     *RolloutResult `json:"result"`
+	EquityLoss float64        `json:"equityLoss"` // Rollout equity given up against the best candidate
+}
+
+// Roll out the best candidates for the unused dice, as ranked by the
+// evaluator, and return them best first. Every candidate is rolled out with
+// the same seed so they face the same dice.
+func RolloutPlays(ctx context.Context, evaluator Evaluator, pos Position, color Color, dice []int, diceUsed []bool, candidates int, opts RolloutOptions) ([]RolledOutPlay, error) {
+	ranked := RankPlays(evaluator, pos, color, dice, diceUsed)
+	if len(ranked) > candidates {
+		ranked = ranked[:candidates]
+	}
+
+	progress := opts.Progress
+	results := make([]RolledOutPlay, 0, len(ranked))
+	for i, candidate := range ranked {
+		if progress != nil {
+			done := i * opts.Trials
+			opts.Progress = func(trials int) { progress(done + trials) }
+		}
+		result, err := Rollout(ctx, evaluator, candidate.Play.Result, color, opts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, RolledOutPlay{Play: candidate.Play, Result: result})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Result.Equity > results[j].Result.Equity
+	})
+	for i := range results {
+		results[i].EquityLoss = results[0].Result.Equity - results[i].Result.Equity
+	}
+	return results, nil
+}
+
+// Return probabilities held for one side as seen by color
+func seenBy(p Probabilities, side, color Color) Probabilities {
+	if side != color {
+		return p.Flip()
+	}
+	return p
+}
+
+// Return the sum of two sets of probabilities
+func (p Probabilities) add(q Probabilities) Probabilities {
+	return Probabilities{
+		Win:            p.Win + q.Win,
+		WinGammon:      p.WinGammon + q.WinGammon,
+		WinBackgammon:  p.WinBackgammon + q.WinBackgammon,
+		LoseGammon:     p.LoseGammon + q.LoseGammon,
+		LoseBackgammon: p.LoseBackgammon + q.LoseBackgammon,
+	}
+}
+
+// Return the difference of two sets of probabilities
+func (p Probabilities) sub(q Probabilities) Probabilities {
+	return p.add(q.scale(-1))
+}
+
+// Return the probabilities multiplied by a factor
+func (p Probabilities) scale(factor float64) Probabilities {
+	return Probabilities{
+		Win:            p.Win * factor,
+		WinGammon:      p.WinGammon * factor,
+		WinBackgammon:  p.WinBackgammon * factor,
+		LoseGammon:     p.LoseGammon * factor,
+		LoseBackgammon: p.LoseBackgammon * factor,
+	}
+}
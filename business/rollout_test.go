@@ -0,0 +1,34 @@
+package business
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestRolloutMaxPlies(t *testing.T) {
+	// No game ends within two plies, so every trial is judged by the evaluator
+	opts := RolloutOptions{Trials: 8, Level: BotHard, MaxPlies: 2, Seed: 1}
+	result, err := Rollout(context.Background(), fixedEvaluator{Win: 0.7}, StartingPosition(), ColorWhite, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Probabilities.Win-0.7) > 1e-9 || result.StdError > 1e-9 {
+		t.Fatalf("got %+v, want every trial judged at 0.7", result)
+	}
+
+	// Truncation cuts trials shorter still
+	opts.MaxPlies, opts.Truncation = 4, 1
+	result, err = Rollout(context.Background(), fixedEvaluator{Win: 0.7}, StartingPosition(), ColorWhite, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Probabilities.Win-0.3) > 1e-9 {
+		t.Fatalf("got %+v, want every trial judged after black's play", result)
+	}
+
+	opts.MaxPlies = -1
+	if _, err := Rollout(context.Background(), fixedEvaluator{}, StartingPosition(), ColorWhite, opts); err == nil {
+		t.Fatal("negative max plies accepted")
+	}
+}
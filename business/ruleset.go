@@ -0,0 +1,257 @@
+package business
+
+import (
+	"fmt"
+	"maps"
+)
+
+// ============================================================================
+// Rule Sets
+// ============================================================================
+
+// Names of the rule sets a game can be played under
+const (
+	RuleSetBackgammon = "backgammon"
+	RuleSetPortes     = "portes"     // Backgammon as played in Tavli: no backgammons and no cube
+	RuleSetPlakoto    = "plakoto"    // Blots are pinned instead of hit
+	RuleSetFevga      = "fevga"      // No hitting, and both sides move the same way round
+	RuleSetTavli      = "tavli"      // A match playing Portes, Plakoto and Fevga in turn
+	RuleSetAceyDeucey = "aceydeucey" // Every checker starts in hand, and a 1-2 earns doubles of choice
+)
+
+// RuleSet is the rules of one game of the backgammon family: where the
+// checkers start, which moves are legal, what a move does and who has won
+type RuleSet interface {
+	Name() string
+	StartingPosition() Position
+	// Return the board point a color's own point lies on, and the reverse
+	BoardPoint(point int, color Color) int
+	// Every distinct legal play for the unused dice
+	LegalPlays(pos Position, color Color, dice []int, diceUsed []bool) []Play
+	// Every move, single or combined, that begins a legal play
+	LegalMoves(pos Position, color Color, dice []int, diceUsed []bool) []LegalMove
+	// Check a move for the unused dice and split it into its single-die steps
+	PlayMove(pos Position, color Color, dice []int, diceUsed []bool, move LegalMove) ([]MoveStep, error)
+	// Make one single-die step, filling in whether it hit
+	ApplyStep(pos Position, step *MoveStep, color Color) (Position, error)
+	HasWon(pos Position, color Color) bool
+	GameResult(pos Position, winner Color) ResultType
+	UsesCube() bool
+}
+
+// stepRules generates and makes the single-die moves of a rule set, which is
+// all the whole-turn play search needs
+type stepRules interface {
+	stepMoves(pos Position, color Color, die int) []MoveStep
+	ApplyStep(pos Position, step *MoveStep, color Color) (Position, error)
+}
+
+// Check a rule set name, defaulting to backgammon. Tavli is a match format,
+// not the rules of a single game.
+func ParseRuleSet(name string) (string, error) {
+	switch name {
+	case "":
+		return RuleSetBackgammon, nil
+	case RuleSetBackgammon, RuleSetPortes, RuleSetPlakoto, RuleSetFevga, RuleSetTavli, RuleSetAceyDeucey:
+		return name, nil
+	}
+	return "", fmt.Errorf("unknown rule set: %s", name)
+}
+
+// Return the rules of a game. The games of a Tavli match play Portes, Plakoto
+// and Fevga in turn; unknown names play backgammon.
+func GameRuleSet(name string, gameNumber int) RuleSet {
+	switch name {
+	case RuleSetPortes:
+		return Backgammon{Portes: true}
+	case RuleSetPlakoto:
+		return Plakoto{}
+	case RuleSetFevga:
+		return Fevga{}
+	case RuleSetAceyDeucey:
+		return AceyDeucey{}
+	case RuleSetTavli:
+		tavli := []string{RuleSetPortes, RuleSetPlakoto, RuleSetFevga}
+		return GameRuleSet(tavli[(max(gameNumber, 1)-1)%len(tavli)], 1)
+	}
+	return Backgammon{}
+}
+
+// ============================================================================
+// Backgammon
+// ============================================================================
+
+// Backgammon is the standard game, or Portes when played as part of Tavli
+type Backgammon struct {
+	Portes bool
+}
+
+// Return the rule set's name
+func (b Backgammon) Name() string {
+	if b.Portes {
+		return RuleSetPortes
+	}
+	return RuleSetBackgammon
+}
+
+// Return the standard starting position
+func (Backgammon) StartingPosition() Position {
+	return StartingPosition()
+}
+
+// Black's points run the opposite way round to white's
+func (Backgammon) BoardPoint(point int, color Color) int {
+	return sidePoint(point, color)
+}
+
+// Return every distinct legal play for the unused dice
+func (Backgammon) LegalPlays(pos Position, color Color, dice []int, diceUsed []bool) []Play {
+	return GetLegalPlays(pos, color, dice, diceUsed)
+}
+
+// Return every legal move, combined moves included
+func (Backgammon) LegalMoves(pos Position, color Color, dice []int, diceUsed []bool) []LegalMove {
+	return GetLegalMoves(pos.Board, color, dice, diceUsed, pos.BarCount(color), pos.BornedOff(color))
+}
+
+// Check a move against the board and the whole turn, then split it into steps
+func (Backgammon) PlayMove(pos Position, color Color, dice []int, diceUsed []bool, move LegalMove) ([]MoveStep, error) {
+	barCount := pos.BarCount(color)
+	if len(move.DiceIndices) == 1 {
+		if err := ValidateMove(pos.Board, move.FromPoint, move.ToPoint, move.DieUsed, color, barCount); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reject moves that would leave a playable die unused this turn
+	if err := ValidateTurnMove(pos.Board, color, dice, diceUsed, barCount, move); err != nil {
+		return nil, err
+	}
+
+	values := []int{}
+	for _, idx := range move.DiceIndices {
+		values = append(values, dice[idx])
+	}
+	return ExpandMove(pos.Board, color, barCount, move.FromPoint, move.ToPoint, values)
+}
+
+// Make one step
+func (Backgammon) ApplyStep(pos Position, step *MoveStep, color Color) (Position, error) {
+	return pos.ApplyStep(step, color)
+}
+
+// Return every move one die allows
+func (Backgammon) stepMoves(pos Position, color Color, die int) []MoveStep {
+	return singleDieMoves(pos.Board, color, die, pos.BarCount(color))
+}
+
+// Check whether color has borne off every checker
+func (Backgammon) HasWon(pos Position, color Color) bool {
+	return CheckWinCondition(pos, color)
+}
+
+// Work out the result type; Portes counts a backgammon as a gammon
+func (b Backgammon) GameResult(pos Position, winner Color) ResultType {
+	result := GameResult(pos, winner)
+	if b.Portes && result == ResultBackgammon {
+		return ResultGammon
+	}
+	return result
+}
+
+// Report whether the doubling cube is used; Tavli is played without it
+func (b Backgammon) UsesCube() bool {
+	return !b.Portes
+}
+
+// ============================================================================
+// Moves From Play Sequences
+// ============================================================================
+
+// Return the moves that begin some legal play: every first step, and every
+// run of steps by the same checker from there as one combined move
+func sequenceMoves(sequences []Play, dice []int, diceUsed []bool) []LegalMove {
+	moves := []LegalMove{}
+	seen := map[string]bool{}
+	for _, play := range sequences {
+		values := []int{}
+		for i, step := range play.Moves {
+			if i > 0 && step.FromPoint != play.Moves[i-1].ToPoint {
+				break
+			}
+			values = append(values, step.DieUsed)
+
+			indices := unusedDiceIndices(dice, diceUsed, values)
+			key := fmt.Sprint(play.Moves[0].FromPoint, step.ToPoint, indices)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			total := 0
+			for _, value := range values {
+				total += value
+			}
+			moves = append(moves, LegalMove{
+				FromPoint:      play.Moves[0].FromPoint,
+				ToPoint:        step.ToPoint,
+				DieUsed:        total,
+				DiceIndices:    indices,
+				IsCombinedMove: i > 0,
+			})
+		}
+	}
+	return moves
+}
+
+// Return the steps of a legal play that make up a move: a run of steps by one
+// checker from the move's starting point to its end, using the move's dice
+func sequenceMove(sequences []Play, dice []int, move LegalMove) ([]MoveStep, error) {
+	want := map[int]int{}
+	for _, idx := range move.DiceIndices {
+		if idx < 0 || idx >= len(dice) {
+			return nil, fmt.Errorf("invalid dice index")
+		}
+		want[dice[idx]]++
+	}
+
+	n := len(move.DiceIndices)
+	if n == 0 {
+		return nil, fmt.Errorf("no dice given for move")
+	}
+	for _, play := range sequences {
+		if len(play.Moves) < n || play.Moves[0].FromPoint != move.FromPoint || play.Moves[n-1].ToPoint != move.ToPoint {
+			continue
+		}
+
+		used := map[int]int{}
+		chained := true
+		for i, step := range play.Moves[:n] {
+			if i > 0 && step.FromPoint != play.Moves[i-1].ToPoint {
+				chained = false
+				break
+			}
+			used[step.DieUsed]++
+		}
+		if chained && maps.Equal(used, want) {
+			return append([]MoveStep(nil), play.Moves[:n]...), nil
+		}
+	}
+	return nil, fmt.Errorf("move is not part of a legal play")
+}
+
+// Return the indices of unused dice showing the given values, one die each
+func unusedDiceIndices(dice []int, diceUsed []bool, values []int) []int {
+	taken := append([]bool(nil), diceUsed...)
+	indices := []int{}
+	for _, value := range values {
+		for i, die := range dice {
+			if die == value && !taken[i] {
+				taken[i] = true
+				indices = append(indices, i)
+				break
+			}
+		}
+	}
+	return indices
+}
@@ -0,0 +1,46 @@
+package business
+
+// ============================================================================
+// Game Result Scoring
+// ============================================================================
+
+// Work out the result type from the final position, given the winner's color
+func GameResult(pos Position, winner Color) ResultType {
+	loser := winner.Opponent()
+
+	if pos.BornedOff(loser) > 0 {
+		return ResultSingle
+	}
+
+	// A gammoned loser with a checker on the bar or in the winner's home board is backgammoned
+	if pos.BarCount(loser) > 0 {
+		return ResultBackgammon
+	}
+	for point := 1; point <= 24; point++ {
+		if IsInHomeBoard(point, winner) && CountCheckersOnPoint(pos.Board, point, loser) > 0 {
+			return ResultBackgammon
+		}
+	}
+
+	return ResultGammon
+}
+
+// Return the stake multiplier for a result type
+func (r ResultType) Multiplier() int {
+	switch r {
+	case ResultGammon:
+		return 2
+	case ResultBackgammon:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// Return the points won for a result at the given cube value
+func PointsWon(result ResultType, cubeValue int) int {
+	if cubeValue < 1 {
+		cubeValue = 1
+	}
+	return result.Multiplier() * cubeValue
+}
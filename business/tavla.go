@@ -0,0 +1,356 @@
+package business
+
+import "fmt"
+
+// ============================================================================
+// Tavli Board Rules
+// ============================================================================
+
+// Own point every checker starts on in Plakoto and Fevga
+const tavlaStartPoint = 24
+
+// tavlaGame is what sets Plakoto and Fevga apart: where a color's points lie,
+// where it may land, and any further limit on a step
+type tavlaGame interface {
+	// Return the board point a color's own point lies on, and the reverse
+	BoardPoint(point int, color Color) int
+	canLand(pos Position, point int, color Color) bool
+	stepAllowed(pos Position, step MoveStep, color Color) bool
+}
+
+// Return the sign of a color's checkers on the board
+func colorSign(color Color) int {
+	if color == ColorWhite {
+		return 1
+	}
+	return -1
+}
+
+// Return the position with both sides' checkers on their own 24 point
+func tavlaStartingPosition(g tavlaGame) Position {
+	pos := Position{Board: make([]int, 24)}
+	for _, color := range []Color{ColorWhite, ColorBlack} {
+		pos.Board[g.BoardPoint(tavlaStartPoint, color)-1] = 15 * colorSign(color)
+	}
+	return pos
+}
+
+// Count a color's checkers on its own point, on top and pinned
+func tavlaCheckers(g tavlaGame, pos Position, point int, color Color) int {
+	board := g.BoardPoint(point, color)
+	count := CountCheckersOnPoint(pos.Board, board, color)
+	if pos.Pinned != nil {
+		count += CountCheckersOnPoint(pos.Pinned, board, color)
+	}
+	return count
+}
+
+// Return a color's highest own point holding one of its checkers, 0 if none
+func tavlaHighestPoint(g tavlaGame, pos Position, color Color) int {
+	for point := 24; point >= 1; point-- {
+		if tavlaCheckers(g, pos, point, color) > 0 {
+			return point
+		}
+	}
+	return 0
+}
+
+// Return every step one die allows. There is no bar; a color bears off once
+// all its checkers, pinned ones included, are in its home board.
+func tavlaStepMoves(g tavlaGame, pos Position, color Color, die int) []MoveStep {
+	highest := tavlaHighestPoint(g, pos, color)
+	moves := []MoveStep{}
+	for point := highest; point >= 1; point-- {
+		from := g.BoardPoint(point, color)
+		if CountCheckersOnPoint(pos.Board, from, color) == 0 {
+			continue
+		}
+
+		step := MoveStep{FromPoint: from, ToPoint: 25, DieUsed: die}
+		switch target := point - die; {
+		case target >= 1:
+			step.ToPoint = g.BoardPoint(target, color)
+			if !g.canLand(pos, step.ToPoint, color) {
+				continue
+			}
+		case highest > 6:
+			continue
+		case target < 0 && point != highest:
+			// A larger die only bears off from the highest point
+			continue
+		}
+
+		if g.stepAllowed(pos, step, color) {
+			moves = append(moves, step)
+		}
+	}
+	return moves
+}
+
+// Make one step. A checker landing on a lone opponent checker pins it, and a
+// pinned checker is freed once the last checker above it leaves.
+func tavlaApplyStep(g tavlaGame, pos Position, step *MoveStep, color Color) (Position, error) {
+	sign := colorSign(color)
+	if step.FromPoint < 1 || step.FromPoint > 24 || CountCheckersOnPoint(pos.Board, step.FromPoint, color) == 0 {
+		return pos, fmt.Errorf("no checker to move on point %d", step.FromPoint)
+	}
+
+	next := pos.Clone()
+	next.Board[step.FromPoint-1] -= sign
+	if next.Board[step.FromPoint-1] == 0 && next.Pinned != nil && next.Pinned[step.FromPoint-1] != 0 {
+		next.Board[step.FromPoint-1] = next.Pinned[step.FromPoint-1]
+		next.Pinned[step.FromPoint-1] = 0
+	}
+
+	if step.ToPoint == 25 {
+		if color == ColorWhite {
+			next.BornedOffWhite++
+		} else {
+			next.BornedOffBlack++
+		}
+	} else {
+		if step.ToPoint < 1 || step.ToPoint > 24 || !g.canLand(next, step.ToPoint, color) {
+			return pos, fmt.Errorf("point %d is blocked", step.ToPoint)
+		}
+		if next.Board[step.ToPoint-1]*sign < 0 {
+			if next.Pinned == nil {
+				next.Pinned = make([]int, 24)
+			}
+			next.Pinned[step.ToPoint-1] = next.Board[step.ToPoint-1]
+			next.Board[step.ToPoint-1] = 0
+			step.HitOpponent = true
+		}
+		next.Board[step.ToPoint-1] += sign
+	}
+
+	if next.Pinned != nil {
+		pinned := false
+		for _, count := range next.Pinned {
+			pinned = pinned || count != 0
+		}
+		if !pinned {
+			next.Pinned = nil
+		}
+	}
+	return next, nil
+}
+
+// Work out the result type: a gammon when the loser has borne off nothing
+func tavlaGameResult(pos Position, winner Color) ResultType {
+	if pos.BornedOff(winner.Opponent()) == 0 {
+		return ResultGammon
+	}
+	return ResultSingle
+}
+
+// ============================================================================
+// Plakoto
+// ============================================================================
+
+// Plakoto is played without hitting: a checker landing on a lone opponent
+// checker pins it until it leaves, and pinning the opponent's last checker on
+// its starting point, its mother, wins a gammon
+type Plakoto struct{}
+
+// Return the rule set's name
+func (Plakoto) Name() string {
+	return RuleSetPlakoto
+}
+
+// Return the starting position, each side's checkers on the other's ace point
+func (p Plakoto) StartingPosition() Position {
+	return tavlaStartingPosition(p)
+}
+
+// Return every distinct legal play for the unused dice
+func (p Plakoto) LegalPlays(pos Position, color Color, dice []int, diceUsed []bool) []Play {
+	return legalPlays(p, pos, color, dice, diceUsed)
+}
+
+// Return every legal move, combined moves included
+func (p Plakoto) LegalMoves(pos Position, color Color, dice []int, diceUsed []bool) []LegalMove {
+	return sequenceMoves(turnSequences(p, pos, color, dice, diceUsed), dice, diceUsed)
+}
+
+// Check a move against the whole turn and split it into steps
+func (p Plakoto) PlayMove(pos Position, color Color, dice []int, diceUsed []bool, move LegalMove) ([]MoveStep, error) {
+	return sequenceMove(turnSequences(p, pos, color, dice, diceUsed), dice, move)
+}
+
+// Make one step, pinning a lone opponent checker
+func (p Plakoto) ApplyStep(pos Position, step *MoveStep, color Color) (Position, error) {
+	return tavlaApplyStep(p, pos, step, color)
+}
+
+// Return every step one die allows
+func (p Plakoto) stepMoves(pos Position, color Color, die int) []MoveStep {
+	return tavlaStepMoves(p, pos, color, die)
+}
+
+// Black moves the opposite way round to white, as in backgammon
+func (Plakoto) BoardPoint(point int, color Color) int {
+	if color == ColorWhite {
+		return point
+	}
+	return 25 - point
+}
+
+// A point can be landed on unless the opponent holds it with two or more
+// checkers, or already pins one of ours there
+func (Plakoto) canLand(pos Position, point int, color Color) bool {
+	count := pos.Board[point-1] * colorSign(color)
+	if count >= 0 {
+		return true
+	}
+	return count == -1 && (pos.Pinned == nil || pos.Pinned[point-1] == 0)
+}
+
+// Plakoto has no rule beyond landing
+func (Plakoto) stepAllowed(Position, MoveStep, Color) bool {
+	return true
+}
+
+// Check whether a color's mother is pinned
+func (p Plakoto) motherPinned(pos Position, color Color) bool {
+	return pos.Pinned != nil && CountCheckersOnPoint(pos.Pinned, p.BoardPoint(tavlaStartPoint, color), color) > 0
+}
+
+// Check whether color has borne off every checker, or pinned the opponent's
+// mother while its own is free
+func (p Plakoto) HasWon(pos Position, color Color) bool {
+	if CheckWinCondition(pos, color) {
+		return true
+	}
+	return p.motherPinned(pos, color.Opponent()) && !p.motherPinned(pos, color)
+}
+
+// Work out the result type: pinning the mother or bearing off before the
+// loser has borne off a checker wins a gammon
+func (p Plakoto) GameResult(pos Position, winner Color) ResultType {
+	if p.motherPinned(pos, winner.Opponent()) {
+		return ResultGammon
+	}
+	return tavlaGameResult(pos, winner)
+}
+
+// Report whether the doubling cube is used; Tavli is played without it
+func (Plakoto) UsesCube() bool {
+	return false
+}
+
+// ============================================================================
+// Fevga
+// ============================================================================
+
+// Fevga is played without hitting or pinning: a single checker holds a point,
+// and both sides move counter-clockwise, black starting opposite white
+type Fevga struct{}
+
+// Return the rule set's name
+func (Fevga) Name() string {
+	return RuleSetFevga
+}
+
+// Return the starting position, each side's checkers in the right-hand
+// corner of the other's outer board
+func (f Fevga) StartingPosition() Position {
+	return tavlaStartingPosition(f)
+}
+
+// Return every distinct legal play for the unused dice
+func (f Fevga) LegalPlays(pos Position, color Color, dice []int, diceUsed []bool) []Play {
+	return legalPlays(f, pos, color, dice, diceUsed)
+}
+
+// Return every legal move, combined moves included
+func (f Fevga) LegalMoves(pos Position, color Color, dice []int, diceUsed []bool) []LegalMove {
+	return sequenceMoves(turnSequences(f, pos, color, dice, diceUsed), dice, diceUsed)
+}
+
+// Check a move against the whole turn and split it into steps
+func (f Fevga) PlayMove(pos Position, color Color, dice []int, diceUsed []bool, move LegalMove) ([]MoveStep, error) {
+	return sequenceMove(turnSequences(f, pos, color, dice, diceUsed), dice, move)
+}
+
+// Make one step
+func (f Fevga) ApplyStep(pos Position, step *MoveStep, color Color) (Position, error) {
+	return tavlaApplyStep(f, pos, step, color)
+}
+
+// Return every step one die allows
+func (f Fevga) stepMoves(pos Position, color Color, die int) []MoveStep {
+	return tavlaStepMoves(f, pos, color, die)
+}
+
+// Black's points run twelve ahead of white's, so its 24 point is white's 12
+func (Fevga) BoardPoint(point int, color Color) int {
+	if color == ColorWhite {
+		return point
+	}
+	return (point+11)%24 + 1
+}
+
+// A point can be landed on unless the opponent has a checker there
+func (Fevga) canLand(pos Position, point int, color Color) bool {
+	return pos.Board[point-1]*colorSign(color) >= 0
+}
+
+// A second checker may not leave the starting point until the first has
+// passed the opponent's, and no six-point prime may be built in front of
+// every opponent checker
+func (f Fevga) stepAllowed(pos Position, step MoveStep, color Color) bool {
+	if f.BoardPoint(step.FromPoint, color) == tavlaStartPoint && tavlaCheckers(f, pos, tavlaStartPoint, color) == 14 {
+		for point := 12; point < tavlaStartPoint; point++ {
+			if tavlaCheckers(f, pos, point, color) > 0 {
+				return false
+			}
+		}
+	}
+
+	if step.ToPoint == 25 {
+		return true
+	}
+	board := append([]int(nil), pos.Board...)
+	board[step.FromPoint-1] -= colorSign(color)
+	board[step.ToPoint-1] += colorSign(color)
+	return !f.primesOpponent(board, color)
+}
+
+// Check whether color holds six points in a row, counted along the opponent's
+// way round, with no opponent checker past them
+func (f Fevga) primesOpponent(board []int, color Color) bool {
+	opponent := color.Opponent()
+	run := 0
+	passed := false
+	for point := 1; point <= 24; point++ {
+		count := board[f.BoardPoint(point, opponent)-1]
+		switch {
+		case count*colorSign(color) > 0:
+			run++
+		case count != 0:
+			passed = true
+			run = 0
+		default:
+			run = 0
+		}
+		if run >= 6 && !passed {
+			return true
+		}
+	}
+	return false
+}
+
+// Check whether color has borne off every checker
+func (Fevga) HasWon(pos Position, color Color) bool {
+	return CheckWinCondition(pos, color)
+}
+
+// Work out the result type: a gammon when the loser has borne off nothing
+func (Fevga) GameResult(pos Position, winner Color) ResultType {
+	return tavlaGameResult(pos, winner)
+}
+
+// Report whether the doubling cube is used; Tavli is played without it
+func (Fevga) UsesCube() bool {
+	return false
+}
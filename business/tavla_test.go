@@ -0,0 +1,157 @@
+package business
+
+import (
+	"testing"
+)
+
+// Return whether the steps include a move between two board points
+func hasStep(steps []MoveStep, from, to int) bool {
+	for _, step := range steps {
+		if step.FromPoint == from && step.ToPoint == to {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTavlaStartingStats(t *testing.T) {
+	for _, rules := range []RuleSet{Plakoto{}, Fevga{}} {
+		stats := GetPositionStats(rules, rules.StartingPosition())
+		if stats.White.PipCount != 360 || stats.Black.PipCount != 360 {
+			t.Errorf("%s: got pip counts %d and %d, want 360 each", rules.Name(), stats.White.PipCount, stats.Black.PipCount)
+		}
+		if stats.Race {
+			t.Errorf("%s: starting position counted as a race", rules.Name())
+		}
+	}
+}
+
+func TestFevgaBoardPoint(t *testing.T) {
+	// Black starts on white's 12 point and bears off past white's 13
+	tests := []struct{ own, board int }{{24, 12}, {13, 1}, {12, 24}, {1, 13}}
+	for _, tt := range tests {
+		if got := (Fevga{}).BoardPoint(tt.own, ColorBlack); got != tt.board {
+			t.Errorf("black's %d point is board point %d, want %d", tt.own, got, tt.board)
+		}
+		if got := (Fevga{}).BoardPoint(tt.board, ColorBlack); got != tt.own {
+			t.Errorf("board point %d is black's %d point, want %d", tt.board, got, tt.own)
+		}
+	}
+}
+
+func TestPlakotoPinning(t *testing.T) {
+	rules := Plakoto{}
+	pos := Position{Board: testBoard(map[int]int{24: 13, 10: 2, 7: -1, 1: -14})}
+
+	step := MoveStep{FromPoint: 10, ToPoint: 7, DieUsed: 3}
+	pinned, err := rules.ApplyStep(pos, &step, ColorWhite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !step.HitOpponent || pinned.Board[6] != 1 || pinned.Pinned[6] != -1 {
+		t.Fatalf("got board %v and pinned %v, want black pinned on 7", pinned.Board, pinned.Pinned)
+	}
+
+	// The pinned checker still has its pips to go but holds no point
+	stats := GetPositionStats(rules, pinned)
+	if stats.Black.PipCount != 14*24+18 || stats.Black.Blots != 0 {
+		t.Fatalf("got black stats %+v", stats.Black)
+	}
+
+	// Black cannot land on its own pinned checker
+	for _, step := range rules.stepMoves(pinned, ColorBlack, 6) {
+		if step.ToPoint == 7 {
+			t.Fatal("black may land on a point where its checker is pinned")
+		}
+	}
+
+	// The checker is freed once white leaves
+	step = MoveStep{FromPoint: 7, ToPoint: 4, DieUsed: 3}
+	freed, err := rules.ApplyStep(pinned, &step, ColorWhite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if freed.Board[6] != -1 || freed.Pinned != nil {
+		t.Fatalf("got board %v and pinned %v, want black freed on 7", freed.Board, freed.Pinned)
+	}
+}
+
+func TestPlakotoMotherPinned(t *testing.T) {
+	rules := Plakoto{}
+	// Black's last checker on its starting point, white's 1 point
+	pos := Position{Board: testBoard(map[int]int{24: 14, 3: 1, 1: -1, 6: -14})}
+
+	step := MoveStep{FromPoint: 3, ToPoint: 1, DieUsed: 2}
+	next, err := rules.ApplyStep(pos, &step, ColorWhite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rules.HasWon(next, ColorWhite) || rules.HasWon(next, ColorBlack) {
+		t.Fatal("pinning the mother did not win the game")
+	}
+	if got := rules.GameResult(next, ColorWhite); got != ResultGammon {
+		t.Fatalf("got %s, want a gammon", got)
+	}
+}
+
+func TestFevgaStartingQuarter(t *testing.T) {
+	rules := Fevga{}
+	pos := Position{Board: testBoard(map[int]int{24: 14, 18: 1, 12: -15})}
+
+	// A second checker may not leave while the first is short of black's start
+	if hasStep(rules.stepMoves(pos, ColorWhite, 5), 24, 19) {
+		t.Fatal("second checker left before the first passed black's start")
+	}
+
+	pos.Board = testBoard(map[int]int{24: 14, 11: 1, 12: -15})
+	if !hasStep(rules.stepMoves(pos, ColorWhite, 5), 24, 19) {
+		t.Fatal("second checker kept back after the first passed black's start")
+	}
+}
+
+func TestFevgaPrime(t *testing.T) {
+	rules := Fevga{}
+	// 20/19 would make six points in a row, 19 to 14, in black's way round
+	board := map[int]int{24: 9, 20: 1, 18: 1, 17: 1, 16: 1, 15: 1, 14: 1, 12: -15}
+	pos := Position{Board: testBoard(board)}
+	if hasStep(rules.stepMoves(pos, ColorWhite, 1), 20, 19) {
+		t.Fatal("prime built in front of every black checker")
+	}
+
+	// A black checker already past the points may be primed behind
+	board[12] = -14
+	board[13] = -1
+	pos.Board = testBoard(board)
+	if !hasStep(rules.stepMoves(pos, ColorWhite, 1), 20, 19) {
+		t.Fatal("prime refused with a black checker past it")
+	}
+}
+
+func TestTavliMatchScoring(t *testing.T) {
+	want := []string{RuleSetPortes, RuleSetPlakoto, RuleSetFevga, RuleSetPortes}
+	for i, name := range want {
+		rules := GameRuleSet(RuleSetTavli, i+1)
+		if rules.Name() != name {
+			t.Errorf("game %d plays %s, want %s", i+1, rules.Name(), name)
+		}
+		if rules.UsesCube() {
+			t.Errorf("%s uses the cube", rules.Name())
+		}
+	}
+
+	// Portes counts a backgammon as a gammon
+	pos := Position{Board: testBoard(map[int]int{1: -15}), BornedOffWhite: 15}
+	if got := (Backgammon{Portes: true}).GameResult(pos, ColorWhite); got != ResultGammon {
+		t.Errorf("Portes backgammon scored as %s", got)
+	}
+
+	// Fevga wins a gammon only while the loser has borne off nothing
+	pos = Position{Board: testBoard(map[int]int{15: -15}), BornedOffWhite: 15}
+	if got := (Fevga{}).GameResult(pos, ColorWhite); got != ResultGammon {
+		t.Errorf("Fevga win with nothing off scored as %s", got)
+	}
+	pos = Position{Board: testBoard(map[int]int{15: -14}), BornedOffWhite: 15, BornedOffBlack: 1}
+	if got := (Fevga{}).GameResult(pos, ColorWhite); got != ResultSingle {
+		t.Errorf("Fevga win after the loser bore off scored as %s", got)
+	}
+}
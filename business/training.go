@@ -0,0 +1,143 @@
+package business
+
+import (
+	"math/rand/v2"
+)
+
+// ============================================================================
+// Self-Play Training
+// ============================================================================
+
+// Longest game self-play allows before abandoning it, counted in plays
+const maxTrainingPlies = 2000
+
+// TrainingOptions configures self-play training of a network
+type TrainingOptions struct {
+	Games        int     // Number of games to play
+	LearningRate float64 // Step size of each weight update
+	Exploration  float64 // Chance of making a random play instead of the best one
+	ReportEvery  int     // Games between calls to Progress, 0 never reports
+
+	// Progress is told how many games have been played so far
+	Progress func(games int)
+}
+
+// Train a network by temporal-difference learning over games it plays
+// against itself. After each play the evaluation of the position the mover
+// left is nudged towards the evaluation of the position after the reply; the
+// last position of a game is trained towards the actual result.
+func TrainSelfPlay(n *Network, opts TrainingOptions, rng *rand.Rand) {
+	for game := 1; game <= opts.Games; game++ {
+		n.trainGame(opts, rng)
+		if opts.Progress != nil && opts.ReportEvery > 0 && game%opts.ReportEvery == 0 {
+			opts.Progress(game)
+		}
+	}
+}
+
+// Play one self-play game, updating the network after every play
+func (n *Network) trainGame(opts TrainingOptions, rng *rand.Rand) {
+	pos := StartingPosition()
+	color := ColorWhite
+	if rng.IntN(2) == 0 {
+		color = ColorBlack
+	}
+
+	// The opening roll is never a double
+	die1, die2 := rollRandomDice(rng)
+	for die1 == die2 {
+		die1, die2 = rollRandomDice(rng)
+	}
+	dice := []int{die1, die2}
+
+	var previous []float64 // Inputs of the position the last mover left
+	for range maxTrainingPlies {
+		pos = n.choosePlay(pos, color, dice, opts.Exploration, rng)
+
+		// The previous mover's position is worth what this one is to them
+		if previous != nil {
+			target := n.Evaluate(pos, color).Flip()
+			n.train(previous, outputsFromProbabilities(target), opts.LearningRate)
+		}
+
+		if gameOver(Backgammon{}, pos) {
+			inputs := EncodePosition(Backgammon{}, pos, color)
+			n.train(inputs, outputsFromProbabilities(resultProbabilities(Backgammon{}, pos, color)), opts.LearningRate)
+			return
+		}
+
+		previous = EncodePosition(Backgammon{}, pos, color)
+		color = color.Opponent()
+		dice = randomRoll(rng)
+	}
+}
+
+// Return the position after the network's choice of play, occasionally a
+// random one to explore, or the same position when the roll cannot be played
+func (n *Network) choosePlay(pos Position, color Color, dice []int, exploration float64, rng *rand.Rand) Position {
+	plays := GetLegalPlays(pos, color, dice, make([]bool, len(dice)))
+	if len(plays) == 0 {
+		return pos
+	}
+	if exploration > 0 && rng.Float64() < exploration {
+		return plays[rng.IntN(len(plays))].Result
+	}
+
+	best := plays[0].Result
+	bestEquity := n.Evaluate(best, color).Equity()
+	for _, play := range plays[1:] {
+		if equity := n.Evaluate(play.Result, color).Equity(); equity > bestEquity {
+			best = play.Result
+			bestEquity = equity
+		}
+	}
+	return best
+}
+
+// Roll two dice
+func rollRandomDice(rng *rand.Rand) (int, int) {
+	return rng.IntN(6) + 1, rng.IntN(6) + 1
+}
+
+// Roll the dice for a turn
+func randomRoll(rng *rand.Rand) []int {
+	return TurnDice(rollRandomDice(rng))
+}
+
+// Move the network's outputs for the given inputs towards target by one
+// step of backpropagation on the squared error
+func (n *Network) train(inputs []float64, target [networkOutputs]float64, rate float64) {
+	hidden, outputs := n.forward(inputs)
+
+	var outputDelta [networkOutputs]float64
+	for o := range outputs {
+		outputDelta[o] = (target[o] - outputs[o]) * outputs[o] * (1 - outputs[o])
+	}
+
+	// Hidden errors use the output weights from before this update
+	hiddenDelta := make([]float64, n.Hidden)
+	for h := range hidden {
+		sum := 0.0
+		for o := range outputs {
+			sum += outputDelta[o] * n.OutputWeights[o][h]
+		}
+		hiddenDelta[h] = sum * hidden[h] * (1 - hidden[h])
+	}
+
+	for o := range outputs {
+		for h, x := range hidden {
+			n.OutputWeights[o][h] += rate * outputDelta[o] * x
+		}
+		n.OutputBias[o] += rate * outputDelta[o]
+	}
+
+	for h, weights := range n.HiddenWeights {
+		step := rate * hiddenDelta[h]
+		for i, x := range inputs {
+			if x != 0 {
+				weights[i] += step * x
+			}
+		}
+		n.HiddenBias[h] += step
+	}
+}
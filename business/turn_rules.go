@@ -0,0 +1,336 @@
+package business
+
+import (
+	"fmt"
+)
+
+// ============================================================================
+// Single Die Moves
+// ============================================================================
+
+// Return the bar entry point for a die value
+func entryPoint(dieValue int, color Color) int {
+	if color == ColorWhite {
+		return 25 - dieValue // White enters from 24 end
+	}
+	return dieValue // Black enters from 1 end
+}
+
+// Return every move a single die allows from the current position
+func singleDieMoves(board []int, color Color, dieValue int, barCount int) []MoveStep {
+	moves := []MoveStep{}
+
+	// Checkers on the bar must enter before anything else moves
+	if barCount > 0 {
+		toPoint := entryPoint(dieValue, color)
+		if IsPointOpen(board, toPoint, color) {
+			moves = append(moves, MoveStep{FromPoint: 0, ToPoint: toPoint, DieUsed: dieValue})
+		}
+		return moves
+	}
+
+	canBear := CanBearOff(board, color, barCount)
+
+	for point := 1; point <= 24; point++ {
+		if CountCheckersOnPoint(board, point, color) == 0 {
+			continue
+		}
+
+		toPoint := CalculateToPoint(point, dieValue, color)
+		if toPoint >= 1 && toPoint <= 24 {
+			if ValidateMove(board, point, toPoint, dieValue, color, barCount) == nil {
+				moves = append(moves, MoveStep{FromPoint: point, ToPoint: toPoint, DieUsed: dieValue})
+			}
+		} else if canBear {
+			if ValidateMove(board, point, 25, dieValue, color, barCount) == nil {
+				moves = append(moves, MoveStep{FromPoint: point, ToPoint: 25, DieUsed: dieValue})
+			}
+		}
+	}
+
+	return moves
+}
+
+// Apply a single step and return the new board and the mover's new bar count
+func applyStep(board []int, step *MoveStep, color Color, barCount int) ([]int, int, error) {
+	result, err := ExecuteMove(board, step.FromPoint, step.ToPoint, color)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	step.HitOpponent = result.HitOpponent
+	if step.FromPoint == 0 {
+		barCount--
+	}
+
+	return result.NewBoard, barCount, nil
+}
+
+// ============================================================================
+// Maximum Dice Usage
+// ============================================================================
+
+// Return the largest number of the given dice that can be played in sequence
+func maxDiceUsable(board []int, color Color, dice []int, barCount int) int {
+	best := 0
+	tried := map[int]bool{}
+
+	for i, die := range dice {
+		// Dice with equal values lead to the same positions
+		if tried[die] {
+			continue
+		}
+		tried[die] = true
+
+		remaining := removeDieAt(dice, i)
+		for _, step := range singleDieMoves(board, color, die, barCount) {
+			newBoard, newBar, err := applyStep(board, &step, color, barCount)
+			if err != nil {
+				continue
+			}
+
+			used := 1 + maxDiceUsable(newBoard, color, remaining, newBar)
+			if used > best {
+				best = used
+			}
+			if best == len(dice) {
+				return best
+			}
+		}
+	}
+
+	return best
+}
+
+// Return the largest die value that can be played on its own, or 0 if none can
+func largestPlayableDie(board []int, color Color, dice []int, barCount int) int {
+	largest := 0
+	for _, die := range dice {
+		if die > largest && len(singleDieMoves(board, color, die, barCount)) > 0 {
+			largest = die
+		}
+	}
+	return largest
+}
+
+// Return the values of the dice that have not been used yet
+func unusedDiceValues(dice []int, diceUsed []bool) []int {
+	values := []int{}
+	for i, die := range dice {
+		if i < len(diceUsed) && !diceUsed[i] {
+			values = append(values, die)
+		}
+	}
+	return values
+}
+
+// Return a copy of dice with the die at index i removed
+func removeDieAt(dice []int, i int) []int {
+	remaining := make([]int, 0, len(dice)-1)
+	remaining = append(remaining, dice[:i]...)
+	return append(remaining, dice[i+1:]...)
+}
+
+// Return a copy of dice with one die of each given value removed
+func removeDiceValues(dice []int, values []int) ([]int, error) {
+	remaining := make([]int, len(dice))
+	copy(remaining, dice)
+
+	for _, value := range values {
+		found := false
+		for i, die := range remaining {
+			if die == value {
+				remaining = removeDieAt(remaining, i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("die %d not available", value)
+		}
+	}
+
+	return remaining, nil
+}
+
+// Return the maximum number of unused dice that can be played this turn
+func MaxDiceUsable(board []int, color Color, dice []int, diceUsed []bool, barCount int) int {
+	return maxDiceUsable(board, color, unusedDiceValues(dice, diceUsed), barCount)
+}
+
+// ============================================================================
+// Move Expansion
+// ============================================================================
+
+// Split a (possibly combined) move into the single-die steps that make it up.
+// Every order of the dice is tried, so a combined move is found whenever any
+// intermediate landing point is open. Hits on intermediate points are included.
+func ExpandMove(board []int, color Color, barCount int, fromPoint, toPoint int, dice []int) ([]MoveStep, error) {
+	if len(dice) == 0 {
+		return nil, fmt.Errorf("no dice given for move")
+	}
+
+	for _, order := range diceOrders(dice) {
+		steps, ok := followDiceOrder(board, color, barCount, fromPoint, toPoint, order)
+		if ok {
+			return steps, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no legal path from %d to %d with the given dice", fromPoint, toPoint)
+}
+
+// Try to move one checker from fromPoint to toPoint using the dice in the given order
+func followDiceOrder(board []int, color Color, barCount int, fromPoint, toPoint int, order []int) ([]MoveStep, bool) {
+	currentBoard := board
+	currentBar := barCount
+	currentPoint := fromPoint
+	steps := []MoveStep{}
+
+	for i, die := range order {
+		var next int
+		if currentPoint == 0 {
+			next = entryPoint(die, color)
+		} else {
+			next = CalculateToPoint(currentPoint, die, color)
+			if next < 1 || next > 24 {
+				next = 25 // Bearing off
+			}
+		}
+
+		// Only the last die may take the checker off the board
+		if next == 25 && i != len(order)-1 {
+			return nil, false
+		}
+
+		if ValidateMove(currentBoard, currentPoint, next, die, color, currentBar) != nil {
+			return nil, false
+		}
+
+		step := MoveStep{FromPoint: currentPoint, ToPoint: next, DieUsed: die}
+		newBoard, newBar, err := applyStep(currentBoard, &step, color, currentBar)
+		if err != nil {
+			return nil, false
+		}
+
+		steps = append(steps, step)
+		currentBoard = newBoard
+		currentBar = newBar
+		currentPoint = next
+	}
+
+	if currentPoint != toPoint {
+		return nil, false
+	}
+
+	return steps, true
+}
+
+// Return the distinct orderings of a set of dice
+func diceOrders(dice []int) [][]int {
+	orders := [][]int{}
+	seen := map[string]bool{}
+
+	var permute func(current []int, remaining []int)
+	permute = func(current []int, remaining []int) {
+		if len(remaining) == 0 {
+			key := fmt.Sprint(current)
+			if !seen[key] {
+				seen[key] = true
+				order := make([]int, len(current))
+				copy(order, current)
+				orders = append(orders, order)
+			}
+			return
+		}
+
+		for i := range remaining {
+			permute(append(current, remaining[i]), removeDieAt(remaining, i))
+		}
+	}
+
+	permute([]int{}, dice)
+	return orders
+}
+
+// ============================================================================
+// Turn Legality
+// ============================================================================
+
+// Check that a move keeps a maximal play available. A player must use as many
+// dice as the position allows, and must use the larger die when only one of
+// two different dice can be played.
+func ValidateTurnMove(board []int, color Color, dice []int, diceUsed []bool, barCount int, move LegalMove) error {
+	available := unusedDiceValues(dice, diceUsed)
+	maxUsable := maxDiceUsable(board, color, available, barCount)
+
+	moveDice := []int{}
+	for _, idx := range move.DiceIndices {
+		if idx < 0 || idx >= len(dice) {
+			return fmt.Errorf("invalid dice index")
+		}
+		moveDice = append(moveDice, dice[idx])
+	}
+
+	return checkTurnMove(board, color, available, barCount, maxUsable, move, moveDice)
+}
+
+// Check a move against a precomputed maximum dice usage
+func checkTurnMove(board []int, color Color, available []int, barCount int, maxUsable int, move LegalMove, moveDice []int) error {
+	remaining, err := removeDiceValues(available, moveDice)
+	if err != nil {
+		return err
+	}
+
+	steps, err := ExpandMove(board, color, barCount, move.FromPoint, move.ToPoint, moveDice)
+	if err != nil {
+		return err
+	}
+
+	// Play the move out and see how many dice can still be used afterwards
+	currentBoard := board
+	currentBar := barCount
+	for i := range steps {
+		currentBoard, currentBar, err = applyStep(currentBoard, &steps[i], color, currentBar)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(moveDice)+maxDiceUsable(currentBoard, color, remaining, currentBar) < maxUsable {
+		return fmt.Errorf("move must allow the maximum number of dice to be used")
+	}
+
+	// When only one of two different dice can be played, it must be the larger one
+	if maxUsable == 1 && len(available) == 2 && available[0] != available[1] {
+		if moveDice[0] != largestPlayableDie(board, color, available, barCount) {
+			return fmt.Errorf("must use the larger die")
+		}
+	}
+
+	return nil
+}
+
+// Keep only the moves that leave a maximal play available
+func filterMaximalMoves(board []int, color Color, dice []int, diceUsed []bool, barCount int, moves []LegalMove) []LegalMove {
+	if len(moves) == 0 {
+		return moves
+	}
+
+	available := unusedDiceValues(dice, diceUsed)
+	maxUsable := maxDiceUsable(board, color, available, barCount)
+
+	filtered := []LegalMove{}
+	for _, move := range moves {
+		moveDice := []int{}
+		for _, idx := range move.DiceIndices {
+			moveDice = append(moveDice, dice[idx])
+		}
+
+		if checkTurnMove(board, color, available, barCount, maxUsable, move, moveDice) == nil {
+			filtered = append(filtered, move)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,168 @@
+package business
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// Return a board with the given checkers per point, positive for white
+func testBoard(points map[int]int) []int {
+	board := make([]int, 24)
+	for point, count := range points {
+		board[point-1] = count
+	}
+	return board
+}
+
+// Return the from and to points of moves, sorted for comparison
+func movePoints(moves []LegalMove) [][2]int {
+	points := [][2]int{}
+	for _, move := range moves {
+		points = append(points, [2]int{move.FromPoint, move.ToPoint})
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i][0] != points[j][0] {
+			return points[i][0] > points[j][0]
+		}
+		return points[i][1] > points[j][1]
+	})
+	return points
+}
+
+func TestGetLegalMovesDiceUsage(t *testing.T) {
+	tests := []struct {
+		name  string
+		board map[int]int
+		dice  []int
+		want  [][2]int
+	}{
+		{
+			// Either die alone is playable, but the 2 point stops both
+			name:  "only one die playable uses the larger",
+			board: map[int]int{13: 1, 2: -2},
+			dice:  []int{5, 6},
+			want:  [][2]int{{13, 7}},
+		},
+		{
+			name:  "larger die blocked plays the smaller",
+			board: map[int]int{13: 1, 7: -2, 2: -2},
+			dice:  []int{6, 5},
+			want:  [][2]int{{13, 8}},
+		},
+		{
+			// Playing 2/1 leaves the 6 unplayable, while 13/12/6 plays both
+			name:  "move that wastes a die is excluded",
+			board: map[int]int{13: 1, 2: 1, 7: -2},
+			dice:  []int{6, 1},
+			want:  [][2]int{{13, 12}, {13, 6}},
+		},
+		{
+			name:  "both dice playable either way",
+			board: map[int]int{13: 1, 9: 1},
+			dice:  []int{6, 5},
+			want:  [][2]int{{13, 8}, {13, 7}, {13, 2}, {9, 4}, {9, 3}},
+		},
+		{
+			name:  "no die playable",
+			board: map[int]int{13: 1, 8: -2, 7: -2},
+			dice:  []int{6, 5},
+			want:  [][2]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moves := GetLegalMoves(testBoard(tt.board), ColorWhite, tt.dice, make([]bool, len(tt.dice)), 0, 13)
+			if got := movePoints(moves); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got moves %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTurnMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		board   map[int]int
+		dice    []int
+		move    LegalMove
+		wantErr bool
+	}{
+		{
+			name:  "larger die when only one is playable",
+			board: map[int]int{13: 1, 2: -2},
+			dice:  []int{5, 6},
+			move:  LegalMove{FromPoint: 13, ToPoint: 7, DiceIndices: []int{1}},
+		},
+		{
+			name:    "smaller die when only one is playable",
+			board:   map[int]int{13: 1, 2: -2},
+			dice:    []int{5, 6},
+			move:    LegalMove{FromPoint: 13, ToPoint: 8, DiceIndices: []int{0}},
+			wantErr: true,
+		},
+		{
+			name:  "smaller die when the larger is blocked",
+			board: map[int]int{13: 1, 7: -2, 2: -2},
+			dice:  []int{6, 5},
+			move:  LegalMove{FromPoint: 13, ToPoint: 8, DiceIndices: []int{1}},
+		},
+		{
+			name:    "move that leaves the other die unplayable",
+			board:   map[int]int{13: 1, 2: 1, 7: -2},
+			dice:    []int{6, 1},
+			move:    LegalMove{FromPoint: 2, ToPoint: 1, DiceIndices: []int{1}},
+			wantErr: true,
+		},
+		{
+			name:  "move that keeps both dice playable",
+			board: map[int]int{13: 1, 2: 1, 7: -2},
+			dice:  []int{6, 1},
+			move:  LegalMove{FromPoint: 13, ToPoint: 12, DiceIndices: []int{1}},
+		},
+		{
+			name:  "entering from the bar",
+			board: map[int]int{13: 1, 20: -2},
+			dice:  []int{5, 3},
+			move:  LegalMove{FromPoint: 0, ToPoint: 22, DiceIndices: []int{1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barCount := 0
+			if tt.move.FromPoint == 0 {
+				barCount = 1
+			}
+			err := ValidateTurnMove(testBoard(tt.board), ColorWhite, tt.dice, make([]bool, len(tt.dice)), barCount, tt.move)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaxDiceUsable(t *testing.T) {
+	tests := []struct {
+		name     string
+		board    map[int]int
+		dice     []int
+		diceUsed []bool
+		want     int
+	}{
+		{"both dice", map[int]int{13: 1, 9: 1}, []int{6, 5}, []bool{false, false}, 2},
+		{"one die", map[int]int{13: 1, 2: -2}, []int{6, 5}, []bool{false, false}, 1},
+		{"no dice", map[int]int{13: 1, 8: -2, 7: -2}, []int{6, 5}, []bool{false, false}, 0},
+		{"double blocked after two", map[int]int{13: 2, 5: -2}, []int{4, 4, 4, 4}, []bool{false, false, false, false}, 2},
+		{"used dice left out", map[int]int{13: 1, 9: 1}, []int{6, 5}, []bool{true, false}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaxDiceUsable(testBoard(tt.board), ColorWhite, tt.dice, tt.diceUsed, 0); got != tt.want {
+				t.Fatalf("got %d dice, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,23 @@ const (
 	ColorBlack Color = "black"
 )
 
+// Return the other color
+func (c Color) Opponent() Color {
+	if c == ColorWhite {
+		return ColorBlack
+	}
+	return ColorWhite
+}
+
+// ResultType describes how decisively a game was won
+type ResultType string
+
+const (
+	ResultSingle     ResultType = "single"     // Loser has borne off at least one checker
+	ResultGammon     ResultType = "gammon"     // Loser has borne off no checkers
+	ResultBackgammon ResultType = "backgammon" // Gammon with a loser's checker on the bar or in the winner's home board
+)
+
 // LegalMove represents a valid move option
 type LegalMove struct {
 	FromPoint      int   `json:"fromPoint"`      // 0=bar, 1-24=board points, 25=bear off
@@ -21,6 +38,32 @@ type LegalMove struct {
 	IsCombinedMove bool  `json:"isCombinedMove"` // True if this move uses multiple dice
 }
 
+// MoveStep is a single checker hop using exactly one die
+type MoveStep struct {
+	FromPoint   int  `json:"fromPoint"`   // 0=bar, 1-24=board points
+	ToPoint     int  `json:"toPoint"`     // 1-24=board points, 25=bear off
+	DieUsed     int  `json:"dieUsed"`     // Value of the die used
+	HitOpponent bool `json:"hitOpponent"` // True if an opponent blot was hit (pinned in Plakoto)
+}
+
+// Play is one complete legal turn: the ordered single-die moves and the position they lead to
+type Play struct {
+	Moves  []MoveStep `json:"moves"`
+	Result Position   `json:"-"`
+}
+
+// Position is a full snapshot of both sides' checkers
+type Position struct {
+	Board          []int // 24 integers: positive=white, negative=black, 0=empty
+	BarWhite       int
+	BarBlack       int
+	BornedOffWhite int
+	BornedOffBlack int
+	HandWhite      int // Acey-Deucey: checkers not yet entered onto the board
+	HandBlack      int
+	Pinned         []int // Plakoto: per point, 1 for a white checker pinned under black, -1 for black under white; nil when none
+}
+
 // MoveResult contains the outcome of executing a move
 type MoveResult struct {
 	NewBoard    []int
@@ -0,0 +1,68 @@
+package business
+
+import "fmt"
+
+// ============================================================================
+// Variants
+// ============================================================================
+
+// Variant is a game played with backgammon's rules from a different start
+type Variant string
+
+const (
+	VariantStandard    Variant = "standard"
+	VariantNackgammon  Variant = "nackgammon"  // Back checkers split over the 24 and 23 points
+	VariantHypergammon Variant = "hypergammon" // Three checkers a side, all back
+	VariantLongGammon  Variant = "longgammon"  // Every checker starts on the 24 point
+	VariantCustom      Variant = "custom"      // A starting board chosen by the players
+)
+
+// Checkers each side starts with on its points, numbered from its own side
+var variantLayouts = map[Variant]map[int]int{
+	VariantStandard:    {24: 2, 13: 5, 8: 3, 6: 5},
+	VariantNackgammon:  {24: 2, 23: 2, 13: 4, 8: 3, 6: 4},
+	VariantHypergammon: {24: 1, 23: 1, 22: 1},
+	VariantLongGammon:  {24: 15},
+}
+
+// Return the variant with the given name, standard when empty
+func ParseVariant(name string) (Variant, error) {
+	switch variant := Variant(name); variant {
+	case "":
+		return VariantStandard, nil
+	case VariantStandard, VariantNackgammon, VariantHypergammon, VariantLongGammon, VariantCustom:
+		return variant, nil
+	}
+	return "", fmt.Errorf("unknown variant: %s", name)
+}
+
+// Return the starting position of a variant. Custom games bring their own.
+func VariantStartingPosition(variant Variant) (Position, error) {
+	layout, ok := variantLayouts[variant]
+	if !ok {
+		return Position{}, fmt.Errorf("variant %s has no fixed starting position", variant)
+	}
+
+	pos := Position{Board: make([]int, 24)}
+	for point, count := range layout {
+		pos.Board[point-1] += count
+		pos.Board[24-point] -= count
+	}
+	return pos, nil
+}
+
+// Check a custom starting board, where each side needs between 1 and 15
+// checkers on the points, and return its position
+func CustomStartingPosition(board []int) (Position, error) {
+	if len(board) != 24 {
+		return Position{}, fmt.Errorf("starting board must have 24 points")
+	}
+
+	pos := Position{Board: append([]int(nil), board...)}
+	for _, color := range []Color{ColorWhite, ColorBlack} {
+		if checkers := pos.CheckersLeft(color); checkers < 1 || checkers > 15 {
+			return Position{}, fmt.Errorf("starting board must give %s between 1 and 15 checkers", color)
+		}
+	}
+	return pos, nil
+}
@@ -0,0 +1,115 @@
+package business
+
+import (
+	"testing"
+)
+
+func TestVariantStartingPositions(t *testing.T) {
+	tests := []struct {
+		variant  Variant
+		checkers int
+		pips     int
+	}{
+		{VariantStandard, 15, 167},
+		{VariantNackgammon, 15, 194},
+		{VariantHypergammon, 3, 69},
+		{VariantLongGammon, 15, 360},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.variant), func(t *testing.T) {
+			pos, err := VariantStartingPosition(tt.variant)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, color := range []Color{ColorWhite, ColorBlack} {
+				if got := pos.CheckersLeft(color); got != tt.checkers {
+					t.Errorf("%s has %d checkers, want %d", color, got, tt.checkers)
+				}
+			}
+			stats := GetPositionStats(Backgammon{}, pos)
+			if stats.White.PipCount != tt.pips || stats.Black.PipCount != tt.pips {
+				t.Errorf("got pip counts %d and %d, want %d each", stats.White.PipCount, stats.Black.PipCount, tt.pips)
+			}
+		})
+	}
+
+	if _, err := VariantStartingPosition(VariantCustom); err == nil {
+		t.Fatal("custom variant given a fixed starting position")
+	}
+}
+
+func TestCustomStartingPosition(t *testing.T) {
+	if _, err := CustomStartingPosition(testBoard(map[int]int{6: 2, 19: -1})); err != nil {
+		t.Fatal(err)
+	}
+	for name, board := range map[string][]int{
+		"short board":    make([]int, 23),
+		"no black":       testBoard(map[int]int{6: 2}),
+		"too many white": testBoard(map[int]int{6: 16, 19: -1}),
+	} {
+		if _, err := CustomStartingPosition(board); err == nil {
+			t.Errorf("%s accepted", name)
+		}
+	}
+}
+
+func TestVariantWinsAndScoring(t *testing.T) {
+	tests := []struct {
+		name   string
+		pos    Position
+		won    bool
+		result ResultType
+	}{
+		{
+			name:   "hypergammon single",
+			pos:    Position{Board: testBoard(map[int]int{20: -2}), BornedOffWhite: 3, BornedOffBlack: 1},
+			won:    true,
+			result: ResultSingle,
+		},
+		{
+			name:   "hypergammon gammon",
+			pos:    Position{Board: testBoard(map[int]int{12: -3}), BornedOffWhite: 3},
+			won:    true,
+			result: ResultGammon,
+		},
+		{
+			name:   "hypergammon backgammon",
+			pos:    Position{Board: testBoard(map[int]int{12: -2, 3: -1}), BornedOffWhite: 3},
+			won:    true,
+			result: ResultBackgammon,
+		},
+		{
+			name: "hypergammon checker left",
+			pos:  Position{Board: testBoard(map[int]int{1: 1, 12: -3}), BornedOffWhite: 2},
+		},
+		{
+			name:   "nackgammon gammon",
+			pos:    Position{Board: testBoard(map[int]int{7: -15}), BornedOffWhite: 15},
+			won:    true,
+			result: ResultGammon,
+		},
+		{
+			name:   "nackgammon backgammon from the bar",
+			pos:    Position{Board: testBoard(map[int]int{7: -14}), BarBlack: 1, BornedOffWhite: 15},
+			won:    true,
+			result: ResultBackgammon,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckWinCondition(tt.pos, ColorWhite); got != tt.won {
+				t.Fatalf("white won = %v, want %v", got, tt.won)
+			}
+			if CheckWinCondition(tt.pos, ColorBlack) {
+				t.Fatal("black won without bearing off")
+			}
+			if tt.won {
+				if got := GameResult(tt.pos, ColorWhite); got != tt.result {
+					t.Fatalf("got %s, want %s", got, tt.result)
+				}
+			}
+		})
+	}
+}
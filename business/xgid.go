@@ -0,0 +1,258 @@
+package business
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// eXtreme Gammon XGID
+// ============================================================================
+
+// Prefix eXtreme Gammon writes before an XGID
+const xgidPrefix = "XGID="
+
+// Largest cube exponent an XGID may record
+const maxXGIDCubeLog = 15
+
+// Cube exponent assumed when an XGID leaves out the cube limit
+const defaultXGIDMaxCubeLog = 10
+
+// XGID is a position with its cube, dice and score as eXtreme Gammon records
+// them. Its player X is white and player O is black.
+type XGID struct {
+	Position      Position
+	CubeValue     int
+	CubeOwner     Color  // Empty when the cube is centered
+	Turn          Color  // Player who must act, the opponent when answering a double
+	Dice          [2]int // Zero when the dice have not been rolled
+	DoubleOffered bool
+	Beavered      bool // The doubled player beavered; the doubler may raccoon
+	Raccooned     bool // The doubler raccooned
+	WhiteScore    int
+	BlackScore    int
+	Crawford      bool // This is the Crawford game, match play only
+	Jacoby        bool // Gammons only count once the cube is turned, money play only
+	Beavers       bool // Beavers are allowed, money play only
+	MatchLength   int  // Zero for a money game
+	MaxCube       int  // Largest cube value allowed
+}
+
+// Parse an XGID, with or without its "XGID=" prefix
+func ParseXGID(s string) (XGID, error) {
+	fields := strings.Split(strings.TrimPrefix(strings.TrimSpace(s), xgidPrefix), ":")
+	if len(fields) != 9 && len(fields) != 10 {
+		return XGID{}, fmt.Errorf("XGID must have 9 or 10 fields, found %d", len(fields))
+	}
+
+	var x XGID
+	var err error
+	if x.Position, err = parseXGIDPosition(fields[0]); err != nil {
+		return XGID{}, err
+	}
+
+	numbers := make([]int, len(fields))
+	for i, field := range fields {
+		if i == 0 || i == 4 {
+			continue
+		}
+		if numbers[i], err = strconv.Atoi(field); err != nil {
+			return XGID{}, fmt.Errorf("invalid XGID field %d: %q", i+1, field)
+		}
+	}
+
+	if numbers[1] < 0 || numbers[1] > maxXGIDCubeLog {
+		return XGID{}, fmt.Errorf("invalid XGID cube value")
+	}
+	x.CubeValue = 1 << numbers[1]
+
+	if x.CubeOwner, err = xgidColor(numbers[2], true); err != nil {
+		return XGID{}, fmt.Errorf("invalid XGID cube owner")
+	}
+	if x.Turn, err = xgidColor(numbers[3], false); err != nil {
+		return XGID{}, fmt.Errorf("invalid XGID turn")
+	}
+
+	switch dice := fields[4]; dice {
+	case "00":
+	case "D":
+		x.DoubleOffered = true
+	case "B":
+		x.Beavered = true
+	case "R":
+		x.Raccooned = true
+	default:
+		if len(dice) != 2 || dice[0] < '1' || dice[0] > '6' || dice[1] < '1' || dice[1] > '6' {
+			return XGID{}, fmt.Errorf("invalid XGID dice: %q", dice)
+		}
+		x.Dice = [2]int{int(dice[0] - '0'), int(dice[1] - '0')}
+	}
+
+	x.WhiteScore, x.BlackScore = numbers[5], numbers[6]
+	x.MatchLength = numbers[8]
+	if x.MatchLength < 0 || x.WhiteScore < 0 || x.BlackScore < 0 {
+		return XGID{}, fmt.Errorf("invalid XGID score")
+	}
+	if x.MatchLength > 0 && (x.WhiteScore >= x.MatchLength || x.BlackScore >= x.MatchLength) {
+		return XGID{}, fmt.Errorf("XGID score must be below the match length")
+	}
+
+	// The same field holds the Crawford flag in a match and rule bits in money
+	rules := numbers[7]
+	if rules < 0 || rules > 3 || (x.MatchLength > 0 && rules > 1) {
+		return XGID{}, fmt.Errorf("invalid XGID rules field")
+	}
+	if x.MatchLength > 0 {
+		x.Crawford = rules == 1
+	} else {
+		x.Jacoby = rules&1 != 0
+		x.Beavers = rules&2 != 0
+	}
+
+	maxCubeLog := defaultXGIDMaxCubeLog
+	if len(fields) == 10 {
+		maxCubeLog = numbers[9]
+	}
+	if maxCubeLog < 0 || maxCubeLog > maxXGIDCubeLog {
+		return XGID{}, fmt.Errorf("invalid XGID cube limit")
+	}
+	x.MaxCube = 1 << maxCubeLog
+
+	return x, nil
+}
+
+// Parse the 26 characters of an XGID position: O's bar, points 1 to 24 and
+// X's bar, with X's checkers in capitals counting "A" as one
+func parseXGIDPosition(s string) (Position, error) {
+	if len(s) != 26 {
+		return Position{}, fmt.Errorf("XGID position must be 26 characters")
+	}
+
+	pos := Position{Board: make([]int, 24)}
+	white, black := 0, 0
+	for i := range len(s) {
+		c := s[i]
+		var count int
+		var color Color
+		switch {
+		case c == '-':
+			continue
+		case c >= 'A' && c <= 'O':
+			count, color = int(c-'A')+1, ColorWhite
+		case c >= 'a' && c <= 'o':
+			count, color = int(c-'a')+1, ColorBlack
+		default:
+			return Position{}, fmt.Errorf("invalid XGID position character %q", c)
+		}
+
+		switch {
+		case i == 0 && color == ColorBlack:
+			pos.BarBlack = count
+		case i == 25 && color == ColorWhite:
+			pos.BarWhite = count
+		case i == 0 || i == 25:
+			return Position{}, fmt.Errorf("XGID position has checkers on the wrong bar")
+		case color == ColorWhite:
+			pos.Board[i-1] = count
+		default:
+			pos.Board[i-1] = -count
+		}
+
+		if color == ColorWhite {
+			white += count
+		} else {
+			black += count
+		}
+	}
+
+	if white > 15 || black > 15 {
+		return Position{}, fmt.Errorf("XGID position has more than 15 checkers for a side")
+	}
+	pos.BornedOffWhite = 15 - white
+	pos.BornedOffBlack = 15 - black
+	return pos, nil
+}
+
+// Return the color of an XGID player number, 1 for X and -1 for O. Zero is
+// no one, allowed only when centered is.
+func xgidColor(player int, centered bool) (Color, error) {
+	switch {
+	case player == 1:
+		return ColorWhite, nil
+	case player == -1:
+		return ColorBlack, nil
+	case player == 0 && centered:
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown player %d", player)
+}
+
+// Return the XGID player number of a color, zero when empty
+func xgidPlayer(color Color) int {
+	switch color {
+	case ColorWhite:
+		return 1
+	case ColorBlack:
+		return -1
+	}
+	return 0
+}
+
+// Return the XGID, with its "XGID=" prefix
+func (x XGID) String() string {
+	var position strings.Builder
+	position.WriteString(xgidPoint(-x.Position.BarBlack))
+	for _, count := range x.Position.Board {
+		position.WriteString(xgidPoint(count))
+	}
+	position.WriteString(xgidPoint(x.Position.BarWhite))
+
+	maxCubeLog := defaultXGIDMaxCubeLog
+	if x.MaxCube > 0 {
+		maxCubeLog = xgidCubeLog(x.MaxCube)
+	}
+
+	dice := "00"
+	switch {
+	case x.DoubleOffered:
+		dice = "D"
+	case x.Beavered:
+		dice = "B"
+	case x.Raccooned:
+		dice = "R"
+	case x.Dice[0] != 0:
+		dice = fmt.Sprintf("%d%d", x.Dice[0], x.Dice[1])
+	}
+
+	var rules int
+	if x.MatchLength > 0 {
+		rules = int(boolBit(x.Crawford))
+	} else {
+		rules = int(boolBit(x.Jacoby) | boolBit(x.Beavers)<<1)
+	}
+
+	return fmt.Sprintf("%s%s:%d:%d:%d:%s:%d:%d:%d:%d:%d", xgidPrefix, position.String(),
+		xgidCubeLog(x.CubeValue), xgidPlayer(x.CubeOwner), xgidPlayer(x.Turn), dice,
+		x.WhiteScore, x.BlackScore, rules, x.MatchLength, maxCubeLog)
+}
+
+// Return the exponent of the smallest power of two at least a cube value
+func xgidCubeLog(value int) int {
+	cubeLog := 0
+	for 1<<cubeLog < value {
+		cubeLog++
+	}
+	return cubeLog
+}
+
+// Return the XGID character of a point's checkers, positive for X
+func xgidPoint(count int) string {
+	switch {
+	case count > 0:
+		return string(rune('A' + count - 1))
+	case count < 0:
+		return string(rune('a' - count - 1))
+	}
+	return "-"
+}
@@ -0,0 +1,203 @@
+package business
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// XGID of the starting position with X on roll before rolling
+const startingXGID = "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:0:0:10"
+
+func TestParseXGIDRoundTrip(t *testing.T) {
+	tests := []string{
+		startingXGID,
+		"XGID=-b----E-C---eE---c-e----B-:1:1:-1:D:0:0:3:0:10",
+		"XGID=aa-BBCC-----------ccb-b-A-:2:-1:1:63:2:4:1:7:8",
+		"XGID=-ABC-----------------cba--:0:0:-1:00:0:0:0:0:10",
+		"XGID=-b----E-C---eE---c-e----B-:3:-1:1:B:0:0:2:0:10",
+		"XGID=-b----E-C---eE---c-e----B-:4:1:-1:R:0:0:3:0:10",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			x, err := ParseXGID(input)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if got := x.String(); got != input {
+				t.Fatalf("String() = %q, want %q", got, input)
+			}
+			again, err := ParseXGID(x.String())
+			if err != nil {
+				t.Fatalf("failed to parse String(): %v", err)
+			}
+			if !reflect.DeepEqual(again, x) {
+				t.Fatalf("parsed again as %+v, want %+v", again, x)
+			}
+		})
+	}
+}
+
+func TestParseXGIDStartingPosition(t *testing.T) {
+	x, err := ParseXGID(startingXGID)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if !reflect.DeepEqual(x.Position, StartingPosition()) {
+		t.Fatalf("position = %+v, want the starting position", x.Position)
+	}
+	if x.CubeValue != 1 || x.CubeOwner != "" || x.Turn != ColorWhite || x.MaxCube != 1024 {
+		t.Fatalf("got cube %d owned by %q, turn %q, limit %d", x.CubeValue, x.CubeOwner, x.Turn, x.MaxCube)
+	}
+
+	// The prefix and the cube limit may both be left out
+	short, err := ParseXGID(strings.TrimSuffix(strings.TrimPrefix(startingXGID, xgidPrefix), ":10"))
+	if err != nil {
+		t.Fatalf("failed to parse without prefix and cube limit: %v", err)
+	}
+	if !reflect.DeepEqual(short, x) {
+		t.Fatalf("parsed as %+v, want %+v", short, x)
+	}
+}
+
+func TestParseXGIDBars(t *testing.T) {
+	x, err := ParseXGID("XGID=b-----D-C---eE---c-e----BA:0:0:1:00:0:0:0:0:10")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if x.Position.BarBlack != 2 || x.Position.BarWhite != 1 {
+		t.Fatalf("bars = %d white, %d black, want 1 and 2", x.Position.BarWhite, x.Position.BarBlack)
+	}
+	if x.Position.BornedOffWhite != 0 || x.Position.BornedOffBlack != 0 {
+		t.Fatalf("borne off = %d white, %d black, want none", x.Position.BornedOffWhite, x.Position.BornedOffBlack)
+	}
+}
+
+func TestParseXGIDCube(t *testing.T) {
+	tests := []struct {
+		field string
+		value int
+		owner Color
+	}{
+		{"0:0", 1, ""},
+		{"1:1", 2, ColorWhite},
+		{"2:-1", 4, ColorBlack},
+		{"6:1", 64, ColorWhite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			x, err := ParseXGID("XGID=-b----E-C---eE---c-e----B-:" + tt.field + ":1:00:0:0:0:0:10")
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if x.CubeValue != tt.value || x.CubeOwner != tt.owner {
+				t.Fatalf("cube = %d owned by %q, want %d owned by %q", x.CubeValue, x.CubeOwner, tt.value, tt.owner)
+			}
+		})
+	}
+}
+
+func TestParseXGIDTurnAndDice(t *testing.T) {
+	tests := []struct {
+		field string
+		want  XGID
+	}{
+		{"1:00", XGID{Turn: ColorWhite}},
+		{"-1:00", XGID{Turn: ColorBlack}},
+		{"1:D", XGID{Turn: ColorWhite, DoubleOffered: true}},
+		{"-1:B", XGID{Turn: ColorBlack, Beavered: true}},
+		{"1:R", XGID{Turn: ColorWhite, Raccooned: true}},
+		{"1:52", XGID{Turn: ColorWhite, Dice: [2]int{5, 2}}},
+		{"-1:66", XGID{Turn: ColorBlack, Dice: [2]int{6, 6}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			x, err := ParseXGID("XGID=-b----E-C---eE---c-e----B-:0:0:" + tt.field + ":0:0:0:0:10")
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			got := XGID{Turn: x.Turn, Dice: x.Dice, DoubleOffered: x.DoubleOffered, Beavered: x.Beavered, Raccooned: x.Raccooned}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseXGIDScoreAndRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		want  XGID
+	}{
+		{"match", "2:4:0:7", XGID{WhiteScore: 2, BlackScore: 4, MatchLength: 7}},
+		{"Crawford game", "6:3:1:7", XGID{WhiteScore: 6, BlackScore: 3, MatchLength: 7, Crawford: true}},
+		{"money", "0:0:0:0", XGID{}},
+		{"money with Jacoby", "0:0:1:0", XGID{Jacoby: true}},
+		{"money with beavers", "0:0:2:0", XGID{Beavers: true}},
+		{"money with Jacoby and beavers", "0:0:3:0", XGID{Jacoby: true, Beavers: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := ParseXGID("XGID=-b----E-C---eE---c-e----B-:0:0:1:00:" + tt.field + ":10")
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			got := XGID{
+				WhiteScore:  x.WhiteScore,
+				BlackScore:  x.BlackScore,
+				MatchLength: x.MatchLength,
+				Crawford:    x.Crawford,
+				Jacoby:      x.Jacoby,
+				Beavers:     x.Beavers,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseXGIDMalformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"too few fields", "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:0"},
+		{"too many fields", "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:0:0:10:0"},
+		{"short position", "XGID=-b----E-C---eE---c-e----B:0:0:1:00:0:0:0:0:10"},
+		{"long position", "XGID=-b----E-C---eE---c-e----B--:0:0:1:00:0:0:0:0:10"},
+		{"bad position character", "XGID=-b----E-C---eE---c-e----Z-:0:0:1:00:0:0:0:0:10"},
+		{"too many checkers", "XGID=-b----E-C---eE---c-e----O-:0:0:1:00:0:0:0:0:10"},
+		{"X on O's bar", "XGID=Ab----E-C---eE---c-e----B-:0:0:1:00:0:0:0:0:10"},
+		{"O on X's bar", "XGID=-b----E-C---eE---c-e----Ba:0:0:1:00:0:0:0:0:10"},
+		{"non-numeric field", "XGID=-b----E-C---eE---c-e----B-:x:0:1:00:0:0:0:0:10"},
+		{"cube too large", "XGID=-b----E-C---eE---c-e----B-:16:0:1:00:0:0:0:0:10"},
+		{"negative cube", "XGID=-b----E-C---eE---c-e----B-:-1:0:1:00:0:0:0:0:10"},
+		{"unknown cube owner", "XGID=-b----E-C---eE---c-e----B-:0:2:1:00:0:0:0:0:10"},
+		{"no one on turn", "XGID=-b----E-C---eE---c-e----B-:0:0:0:00:0:0:0:0:10"},
+		{"die of zero", "XGID=-b----E-C---eE---c-e----B-:0:0:1:07:0:0:0:0:10"},
+		{"die of seven", "XGID=-b----E-C---eE---c-e----B-:0:0:1:71:0:0:0:0:10"},
+		{"one die", "XGID=-b----E-C---eE---c-e----B-:0:0:1:5:0:0:0:0:10"},
+		{"unknown dice letter", "XGID=-b----E-C---eE---c-e----B-:0:0:1:X:0:0:0:0:10"},
+		{"negative score", "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:-1:0:0:7:10"},
+		{"score reaches match length", "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:7:0:0:7:10"},
+		{"negative match length", "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:0:-1:10"},
+		{"money rule bits in a match", "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:2:7:10"},
+		{"unknown rule bits", "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:4:0:10"},
+		{"cube limit too large", "XGID=-b----E-C---eE---c-e----B-:0:0:1:00:0:0:0:0:16"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if x, err := ParseXGID(tt.input); err == nil {
+				t.Fatalf("parsed %q as %+v, want an error", tt.input, x)
+			}
+		})
+	}
+}
@@ -95,6 +95,21 @@ export async function makeMove(gameId: number, move: MoveRequest): Promise<GameS
   return response.json();
 }
 
+// Pass a roll that has no legal move
+export async function passTurn(gameId: number): Promise<GameState> {
+  const response = await fetch(`${API_BASE}/games/${gameId}/pass`, {
+    method: 'POST',
+    credentials: 'include',
+  });
+
+  if (!response.ok) {
+    const error = await response.json();
+    throw new Error(error.error || 'Failed to pass');
+  }
+
+  return response.json();
+}
+
 // Get legal moves for current position
 export async function getLegalMoves(gameId: number): Promise<LegalMove[]> {
   const response = await fetch(`${API_BASE}/games/${gameId}/legal-moves`, {
@@ -4,12 +4,14 @@ import {
     type ChatMessageData,
     type ConnectionStatus,
     type ErrorData,
+    type GameEventData,
+    type GameEventType,
     type MessageHistoryData,
     type UserEventData,
     type WSMessage,
 } from "@/types/chat";
 import { useAuth } from "@/contexts/AuthContext";
-import { createContext, type ReactNode, useCallback, useContext, useState } from "react";
+import { createContext, type ReactNode, useCallback, useContext, useEffect, useRef, useState } from "react";
 import { useGameWebSocket } from "@/hooks/useGameWebSocket";
 
 interface ChatContextType {
@@ -161,17 +163,43 @@ export function useChatContext() {
 // Game Chat Context (for game-specific chat rooms)
 const GameChatContext = createContext<ChatContextType | undefined>(undefined);
 
+const GAME_EVENT_TYPES: GameEventType[] = [
+    "dice_rolled",
+    "checker_moved",
+    "turn_changed",
+    "game_completed",
+    "game_forfeited",
+    "cube_action",
+    "opening_roll",
+    "match_updated",
+];
+
 interface GameChatProviderProps {
     children: ReactNode;
     gameId: number | null;
+    onGameEvent?: (type: GameEventType, data: GameEventData) => void; // Game events share the chat socket
+    onReconnect?: () => void; // Called on every (re)connection so the caller can resync
 }
 
-export function GameChatProvider({ children, gameId }: GameChatProviderProps) {
+export function GameChatProvider({ children, gameId, onGameEvent, onReconnect }: GameChatProviderProps) {
     const { user } = useAuth();
     const [messages, setMessages] = useState<ChatMessage[]>([]);
     const [error, setError] = useState<string | null>(null);
 
+    // Keep the latest callbacks without reconnecting the socket when they change
+    const onGameEventRef = useRef(onGameEvent);
+    const onReconnectRef = useRef(onReconnect);
+    useEffect(() => {
+        onGameEventRef.current = onGameEvent;
+        onReconnectRef.current = onReconnect;
+    }, [onGameEvent, onReconnect]);
+
     const handleMessage = useCallback((wsMessage: WSMessage) => {
+        if (GAME_EVENT_TYPES.includes(wsMessage.type as GameEventType)) {
+            onGameEventRef.current?.(wsMessage.type as GameEventType, wsMessage.data as GameEventData);
+            return;
+        }
+
         switch (wsMessage.type) {
             case "history": {
                 const data = wsMessage.data as MessageHistoryData;
@@ -229,6 +257,7 @@ export function GameChatProvider({ children, gameId }: GameChatProviderProps) {
     const handleOpen = useCallback(() => {
         console.log("Game chat WebSocket connected");
         setError(null);
+        onReconnectRef.current?.();
     }, []);
 
     const handleClose = useCallback(() => {
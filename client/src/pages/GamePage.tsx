@@ -4,6 +4,7 @@ import {
     getGameState,
     getLegalMoves,
     makeMove,
+    passTurn,
     rollDice,
 } from "@/api/game";
 import ChatPanel from "@/components/common/ChatPanel";
@@ -13,6 +14,7 @@ import { Button } from "@/components/ui/button";
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
 import { useAuth } from "@/contexts/AuthContext";
 import { GameChatProvider, useGameChatContext } from "@/contexts/ChatContext";
+import type { GameEventData, GameEventType } from "@/types/chat";
 import type { GameData, GameState, LegalMove } from "@/types/game";
 import { useEffect, useRef, useState } from "react";
 import { useNavigate, useParams } from "react-router-dom";
@@ -45,6 +47,7 @@ export default function GamePage() {
     const [error, setError] = useState<string | null>(null);
     const [actionLoading, setActionLoading] = useState(false);
     const hasSeenActiveGame = useRef(false);
+    const lastEventSequence = useRef<number | null>(null);
 
     // Fetch game data
     const fetchGameData = async () => {
@@ -68,7 +71,7 @@ export default function GamePage() {
     // Fetch game state
     const fetchGameState = async () => {
         if (!gameId) return;
-        if (gameData?.gameStatus !== "in_progress") return;
+        if (gameData?.gameStatus !== "pending" && gameData?.gameStatus !== "in_progress") return;
 
         try {
             const state = await getGameState(parseInt(gameId));
@@ -111,7 +114,7 @@ export default function GamePage() {
 
     // Fetch game state when game data changes
     useEffect(() => {
-        if (gameData?.gameStatus === "in_progress") {
+        if (gameData?.gameStatus === "pending" || gameData?.gameStatus === "in_progress") {
             fetchGameState();
         }
     }, [gameData, gameId]);
@@ -135,17 +138,47 @@ export default function GamePage() {
         }
     }, [loading, gameData?.gameStatus, navigate]);
 
-    // Poll for updates every second
-    useEffect(() => {
-        const pollInterval = setInterval(async () => {
-            await fetchGameData();
-            if (gameData?.gameStatus === "in_progress") {
-                await fetchGameState();
-            }
-        }, 1000);
+    // Resync game data and state from the server (state is refetched when game data changes)
+    const resync = async () => {
+        await fetchGameData();
+    };
+
+    // Refetch game data and the state itself after missed events, without
+    // waiting for the game data to change
+    const resyncState = async () => {
+        if (!gameId) return;
+        await fetchGameData();
+        try {
+            const state = await getGameState(parseInt(gameId));
+            setGameState(state);
+        } catch (err) {
+            console.error("Failed to fetch game state:", err);
+        }
+    };
+
+    // Refresh from the server whenever a game event is pushed over the game WebSocket.
+    // The sequence is the state version the event's change committed.
+    const handleGameEvent = (type: GameEventType, data: GameEventData) => {
+        const last = lastEventSequence.current;
+
+        // An older change arriving late is already covered by the newer state
+        if (last !== null && data.sequence < last) return;
+        lastEventSequence.current = data.sequence;
+
+        if (last !== null && data.sequence > last + 1) {
+            console.warn(`Missed game changes before ${type} (version ${last} -> ${data.sequence}), resyncing`);
+            resyncState();
+            return;
+        }
+
+        resync();
+    };
 
-        return () => clearInterval(pollInterval);
-    }, [gameId, gameData?.gameStatus]);
+    // Refetch after (re)connecting, since events may have been missed while offline
+    const handleReconnect = () => {
+        lastEventSequence.current = null;
+        resync();
+    };
 
     const handleRollDice = async () => {
         if (!gameId) return;
@@ -162,6 +195,21 @@ export default function GamePage() {
         }
     };
 
+    const handlePass = async () => {
+        if (!gameId) return;
+        setActionLoading(true);
+        try {
+            const newState = await passTurn(parseInt(gameId));
+            setGameState(newState);
+            await fetchGameData();
+        } catch (err) {
+            console.error("Failed to pass:", err);
+            alert(err instanceof Error ? err.message : "Failed to pass");
+        } finally {
+            setActionLoading(false);
+        }
+    };
+
     const handleDragStart = (point: number) => {
         if (!gameId || !gameData || !gameState) return;
         if (gameData.currentTurn !== user?.id) return;
@@ -351,9 +399,16 @@ export default function GamePage() {
     const isGameActive = gameData.gameStatus === "in_progress";
     const isMyTurn = gameData.currentTurn === user?.id;
     const myColor = myPlayer.color as "white" | "black";
+    const isOpeningRoll = gameData.gameStatus === "pending";
+    const myOpeningDie = isPlayer1 ? gameState?.openingRoll.player1 : gameState?.openingRoll.player2;
+    const opponentOpeningDie = isPlayer1 ? gameState?.openingRoll.player2 : gameState?.openingRoll.player1;
 
     return (
-        <GameChatProvider gameId={gameId ? parseInt(gameId) : null}>
+        <GameChatProvider
+            gameId={gameId ? parseInt(gameId) : null}
+            onGameEvent={handleGameEvent}
+            onReconnect={handleReconnect}
+        >
             <div className="min-h-screen bg-felt felt-texture p-4 pr-[336px]">
                 <div className="max-w-7xl mx-auto">
                     <div className="flex justify-between items-center mb-4">
@@ -452,6 +507,26 @@ export default function GamePage() {
                                     <CardTitle className="text-lg">Actions</CardTitle>
                                 </CardHeader>
                                 <CardContent className="space-y-2">
+                                    {isOpeningRoll && (
+                                        <div className="text-sm space-y-2">
+                                            <p className="font-medium">Opening roll</p>
+                                            <p className="text-xs text-muted-foreground">
+                                                You: {myOpeningDie ?? "-"} / Opponent:{" "}
+                                                {opponentOpeningDie ?? "-"}
+                                            </p>
+                                            {gameState && myOpeningDie == null && (
+                                                <Button
+                                                    onClick={handleRollDice}
+                                                    disabled={actionLoading}
+                                                    variant="casino"
+                                                    className="w-full"
+                                                >
+                                                    Roll Opening Die
+                                                </Button>
+                                            )}
+                                        </div>
+                                    )}
+
                                     {isGameActive && isMyTurn && !gameState?.diceRoll && (
                                         <Button
                                             onClick={handleRollDice}
@@ -482,9 +557,20 @@ export default function GamePage() {
                                                 </p>
                                             )}
                                             {legalMoves.length === 0 && !actionLoading && (
-                                                <p className="text-xs text-destructive mt-2">
-                                                    No legal moves available
-                                                </p>
+                                                <>
+                                                    <p className="text-xs text-destructive mt-2">
+                                                        No legal moves available
+                                                    </p>
+                                                    <Button
+                                                        onClick={handlePass}
+                                                        disabled={actionLoading}
+                                                        variant="outline"
+                                                        className="w-full mt-2"
+                                                        size="sm"
+                                                    >
+                                                        Pass
+                                                    </Button>
+                                                </>
                                             )}
                                         </div>
                                     )}
@@ -107,7 +107,12 @@ export default function LobbyPage() {
     const handleChallenge = async (userId: number) => {
         setActionLoading(userId);
         try {
-            await sendInvitation(userId);
+            const response = await sendInvitation(userId);
+            // Computer players accept straight away
+            if (response.gameId) {
+                navigate(`/game/${response.gameId}`);
+                return;
+            }
             await fetchLobbyData();
         } catch (err) {
             console.error("Failed to send invitation:", err);
@@ -489,6 +494,11 @@ export default function LobbyPage() {
                                                             <div className="relative">
                                                                 <p className="font-semibold text-sm">
                                                                     {player.username}
+                                                                    {player.isBot && (
+                                                                        <span className="ml-2 text-xs text-muted-foreground">
+                                                                            Computer ({player.botLevel})
+                                                                        </span>
+                                                                    )}
                                                                 </p>
                                                                 <div className="absolute -top-0.5 -right-3">
                                                                     <div className="w-2.5 h-2.5 rounded-full bg-green-500 ring-2 ring-card"></div>
@@ -6,11 +6,26 @@ export interface ChatMessage {
     timestamp: string; // ISO 8601 format
 }
 
+export type GameEventType =
+    | "dice_rolled"
+    | "checker_moved"
+    | "turn_changed"
+    | "game_completed"
+    | "game_forfeited"
+    | "cube_action"
+    | "opening_roll"
+    | "match_updated";
+
 export interface WSMessage {
-    type: "send_message" | "chat_message" | "history" | "user_joined" | "user_left" | "error";
+    type: "send_message" | "chat_message" | "history" | "user_joined" | "user_left" | "error" | GameEventType;
     data: any;
 }
 
+export interface GameEventData {
+    gameId: number;
+    sequence: number; // State version the change committed, shared by its events; a jump means changes were missed
+}
+
 export interface SendMessageRequest {
     message: string;
 }
@@ -11,9 +11,14 @@ export interface GameData {
     currentTurn: number;
     gameStatus: "pending" | "in_progress" | "completed" | "abandoned";
     winnerId: number | null;
+    resultType: "single" | "gammon" | "backgammon" | null;
+    pointsWon: number | null;
     createdAt: string;
     startedAt: string | null;
     endedAt: string | null;
+    matchId: number | null;
+    gameNumber: number;
+    crawford: boolean;
 }
 
 export interface ActiveGamesResponse {
@@ -30,9 +35,51 @@ export interface GameState {
     bornedOffBlack: number;
     diceRoll: number[] | null; // 2 dice for regular rolls, 4 dice for doubles
     diceUsed: boolean[] | null;
+    stats: PositionStats;
+    positionId?: string | null; // GNU Backgammon Position ID, from /state only and null outside backgammon
+    gnubgMatchId?: string | null; // GNU Backgammon Match ID, from /state only
+    cube: CubeState;
+    openingRoll: OpeningRollState; // Opening dice while the game is pending (and after it is decided)
+    clock: ClockState | null; // Null for untimed games
+    version: number; // Incremented on every state change
     lastUpdated: string;
 }
 
+export interface SideStats {
+    pipCount: number;
+    blots: number; // Points held by a single checker
+    pointsMade: number; // Points held by two or more checkers
+    longestPrime: number; // Most consecutive made points
+    checkersBack: number; // Checkers on the bar or in the opponent's home board
+}
+
+export interface PositionStats {
+    white: SideStats;
+    black: SideStats;
+    race: boolean; // The sides have passed each other
+}
+
+export interface ClockState {
+    player1RemainingMs: number; // Reserve left, as of the response
+    player2RemainingMs: number;
+    delayRemainingMs: number; // Delay left in the running player's turn
+    running: number | null; // User whose clock is running
+    delaySeconds: number;
+    reserveSeconds: number;
+}
+
+export interface OpeningRollState {
+    player1: number | null;
+    player2: number | null;
+}
+
+export interface CubeState {
+    value: number;
+    owner: number | null; // User who owns the cube, null when centered
+    offeredBy: number | null; // User whose double awaits a response
+    beavered: boolean; // The original doubler may raccoon
+}
+
 export interface LegalMove {
     fromPoint: number; // 0=bar, 1-24=board points, 25=bear off
     toPoint: number;
@@ -47,6 +94,7 @@ export interface MoveRequest {
     dieUsed: number;
     diceIndices?: number[];
     isCombinedMove?: boolean;
+    expectedVersion?: number; // Rejected with 409 if the state has changed since
 }
 
 export interface LegalMovesResponse {
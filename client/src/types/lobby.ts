@@ -1,8 +1,10 @@
 export interface LobbyUser {
   userId: number;
   username: string;
-  joinedAt: string;
-  lastHeartbeat: string;
+  joinedAt?: string; // Absent for computer players
+  lastHeartbeat?: string;
+  isBot: boolean;
+  botLevel?: 'easy' | 'medium' | 'hard';
 }
 
 export interface Invitation {
@@ -29,6 +31,7 @@ export interface CreateInvitationResponse {
   invitationId: number;
   challengedId: number;
   status: string;
+  gameId?: number; // Present when a computer player accepted straight away
   message: string;
 }
 
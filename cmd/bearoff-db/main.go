@@ -0,0 +1,43 @@
+// Command bearoff-db generates the one-sided bear-off database used for exact
+// evaluation once both sides are bearing off, and checks it against a
+// brute-force search of small positions before writing it.
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"backgammon/business"
+)
+
+func main() {
+	out := flag.String("out", "bearoff.db", "database file to write")
+	checkers := flag.Int("checkers", business.BearoffCheckers, "most checkers a position in the database has")
+	verify := flag.Int("verify", 5, "checkers up to which positions are checked by brute force, 0 skips the check")
+	every := flag.Int("every", 5000, "positions between progress reports")
+	flag.Parse()
+
+	if *checkers < 1 || *checkers > business.BearoffCheckers {
+		log.Fatalf("Checkers must be from 1 to %d", business.BearoffCheckers)
+	}
+
+	started := time.Now()
+	db := business.GenerateBearoffDatabase(*checkers, func(done, total int) {
+		if done%*every == 0 || done == total {
+			log.Printf("%d of %d positions in %s", done, total, time.Since(started).Round(time.Second))
+		}
+	})
+
+	if *verify > 0 {
+		if err := db.Verify(*verify); err != nil {
+			log.Fatalf("Bear-off database failed verification: %v", err)
+		}
+		log.Printf("Verified positions of up to %d checkers", *verify)
+	}
+
+	if err := db.Save(*out); err != nil {
+		log.Fatalf("Failed to save bear-off database: %v", err)
+	}
+	log.Printf("Wrote %s", *out)
+}
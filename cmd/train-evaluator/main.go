@@ -0,0 +1,60 @@
+// Command train-evaluator trains the neural network position evaluator by
+// self-play and writes its weights file. Training runs on the CPU; pass
+// -in to continue from an earlier weights file.
+package main
+
+import (
+	"flag"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"backgammon/business"
+)
+
+func main() {
+	games := flag.Int("games", 100000, "number of self-play games")
+	hidden := flag.Int("hidden", 64, "hidden units for a new network")
+	rate := flag.Float64("rate", 0.1, "learning rate")
+	exploration := flag.Float64("explore", 0.01, "chance of a random play")
+	seed := flag.Uint64("seed", uint64(time.Now().UnixNano()), "random seed")
+	in := flag.String("in", "", "weights file to continue training from")
+	out := flag.String("out", "evaluator.json", "weights file to write")
+	every := flag.Int("every", 1000, "games between progress reports and checkpoints")
+	flag.Parse()
+
+	rng := rand.New(rand.NewPCG(*seed, *seed^0x9e3779b97f4a7c15))
+
+	var network *business.Network
+	if *in != "" {
+		var err error
+		network, err = business.LoadNetwork(*in)
+		if err != nil {
+			log.Fatalf("Failed to load network: %v", err)
+		}
+		log.Printf("Continuing from %s (%d hidden units)", *in, network.Hidden)
+	} else {
+		network = business.NewNetwork(*hidden, rng)
+		log.Printf("Training a new network with %d hidden units", *hidden)
+	}
+
+	started := time.Now()
+	business.TrainSelfPlay(network, business.TrainingOptions{
+		Games:        *games,
+		LearningRate: *rate,
+		Exploration:  *exploration,
+		ReportEvery:  *every,
+		Progress: func(played int) {
+			opening := network.Evaluate(business.StartingPosition(), business.ColorWhite)
+			log.Printf("%d games in %s, opening equity %.3f", played, time.Since(started).Round(time.Second), opening.Equity())
+			if err := network.Save(*out); err != nil {
+				log.Fatalf("Failed to save checkpoint: %v", err)
+			}
+		},
+	}, rng)
+
+	if err := network.Save(*out); err != nil {
+		log.Fatalf("Failed to save network: %v", err)
+	}
+	log.Printf("Wrote %s after %d games", *out, *games)
+}
@@ -45,9 +45,52 @@ func main() {
 	}
 	log.Printf("Lobby chat room initialized (ID: %d)", roomID)
 
+	// Ensure the computer players exist
+	if err := service.InitBots(context.Background()); err != nil {
+		log.Fatalf("Failed to create computer players: %v", err)
+	}
+	log.Println("Computer players initialized")
+
+	// Load the trained position evaluator when one is configured
+	if weights := os.Getenv("EVALUATOR_WEIGHTS"); weights != "" {
+		if err := service.InitEvaluator(weights); err != nil {
+			log.Fatalf("Failed to load position evaluator: %v", err)
+		}
+		log.Printf("Position evaluator loaded from %s", weights)
+	} else {
+		log.Println("No EVALUATOR_WEIGHTS set, using the heuristic position evaluator")
+	}
+
+	// Load the bear-off database when one is configured
+	if bearoff := os.Getenv("BEAROFF_DB"); bearoff != "" {
+		if err := service.InitBearoffDatabase(bearoff); err != nil {
+			log.Fatalf("Failed to load bear-off database: %v", err)
+		}
+		log.Printf("Bear-off database loaded from %s", bearoff)
+	}
+
+	// Resume rollouts interrupted by a restart
+	count, err := db.RequeueRunningRollouts(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to requeue rollouts: %v", err)
+	}
+	if count > 0 {
+		log.Printf("Requeued %d interrupted rollouts", count)
+	}
+
+	// Recover the dice log of games played before it existed
+	count, err = db.BackfillDiceRolls(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to backfill dice rolls: %v", err)
+	}
+	if count > 0 {
+		log.Printf("Recovered %d dice rolls from game history", count)
+	}
+
 	// Initialize WebSocket hub for chat
 	chatHub := service.NewHub()
 	go chatHub.Run()
+	service.SetGameEventHub(chatHub)
 	log.Println("WebSocket hub initialized and running")
 
 	// Routers
@@ -84,6 +127,19 @@ func main() {
 		}
 	})
 
+	// Match endpoints
+	protectedMux.HandleFunc("/api/v1/matches/", service.MatchHandler)
+
+	// Dice endpoints
+	protectedMux.HandleFunc("/api/v1/dice/stats", service.DiceStatsHandler)
+
+	// Position endpoints
+	protectedMux.HandleFunc("/api/v1/positions", service.PositionHandler)
+
+	// Rollout endpoints
+	protectedMux.HandleFunc("/api/v1/rollouts", service.RolloutRouterHandler)
+	protectedMux.HandleFunc("/api/v1/rollouts/", service.RolloutRouterHandler)
+
 	// Chat endpoints
 	protectedMux.HandleFunc("/api/v1/lobby/ws", service.ChatWebSocketHandler(chatHub))
 	// protectedMux.HandleFunc("/api/v1/chat/rooms/{:roomId}/messages", service.ChatMessagesHandler)
@@ -143,6 +199,43 @@ func main() {
 		}
 	}()
 
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		log.Println("Started game clock sweep job (runs every 1s)")
+		for range ticker.C {
+			service.SweepExpiredClocks(context.Background())
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		log.Println("Started stalled match sweep job (runs at startup and every 30s)")
+		for {
+			service.SweepStalledMatches(context.Background())
+			<-ticker.C
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(15 * time.Second)
+		defer ticker.Stop()
+		log.Println("Started finished game analysis job (runs every 15s)")
+		for range ticker.C {
+			service.AnalyzeFinishedGames(context.Background())
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+		log.Println("Started rollout job (runs every 5s)")
+		for range ticker.C {
+			service.RunQueuedRollouts(context.Background())
+		}
+	}()
+
 	log.Println("Server starting on :8080")
 	http.ListenAndServe("0.0.0.0:8080", mux)
 }
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Retrieve finished games that have moves but no analysis yet, oldest first
+func (pg *Postgres) GetGamesAwaitingAnalysis(ctx context.Context, limit int) ([]int, error) {
+	query := `
+		SELECT g.game_id
+		FROM GAME g
+		WHERE g.game_status IN ('completed', 'abandoned')
+		  AND g.ruleset IN ('backgammon', 'portes')
+		  AND EXISTS (SELECT 1 FROM MOVE m WHERE m.game_id = g.game_id)
+		  AND NOT EXISTS (SELECT 1 FROM GAME_ANALYSIS ga WHERE ga.game_id = g.game_id)
+		ORDER BY g.ended_at
+		LIMIT $1
+	`
+
+	rows, err := pg.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get games awaiting analysis: %w", err)
+	}
+	defer rows.Close()
+
+	gameIDs := []int{}
+	for rows.Next() {
+		var gameID int
+		if err := rows.Scan(&gameID); err != nil {
+			return nil, fmt.Errorf("failed to scan game: %w", err)
+		}
+		gameIDs = append(gameIDs, gameID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get games awaiting analysis: %w", err)
+	}
+
+	return gameIDs, nil
+}
+
+// Store a game's analysis, replacing any earlier one
+func (pg *Postgres) SaveGameAnalysis(ctx context.Context, analysis *GameAnalysis) error {
+	query := `
+		INSERT INTO GAME_ANALYSIS (game_id, evaluator, player1_error_rate, player2_error_rate, analysis, analyzed_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (game_id) DO UPDATE
+		SET evaluator = EXCLUDED.evaluator,
+		    player1_error_rate = EXCLUDED.player1_error_rate,
+		    player2_error_rate = EXCLUDED.player2_error_rate,
+		    analysis = EXCLUDED.analysis,
+		    analyzed_at = EXCLUDED.analyzed_at
+	`
+
+	_, err := pg.db.Exec(ctx, query,
+		analysis.GameID,
+		analysis.Evaluator,
+		analysis.Player1ErrorRate,
+		analysis.Player2ErrorRate,
+		[]byte(analysis.Analysis),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save game analysis: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve a game's analysis. Returns nil when the game has not been analyzed.
+func (pg *Postgres) GetGameAnalysis(ctx context.Context, gameID int) (*GameAnalysis, error) {
+	query := `
+		SELECT game_id, evaluator, player1_error_rate, player2_error_rate, analysis, analyzed_at
+		FROM GAME_ANALYSIS
+		WHERE game_id = $1
+	`
+
+	var analysis GameAnalysis
+	var analysisJSON []byte
+	err := pg.db.QueryRow(ctx, query, gameID).Scan(
+		&analysis.GameID,
+		&analysis.Evaluator,
+		&analysis.Player1ErrorRate,
+		&analysis.Player2ErrorRate,
+		&analysisJSON,
+		&analysis.AnalyzedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get game analysis: %w", err)
+	}
+	analysis.Analysis = analysisJSON
+
+	return &analysis, nil
+}
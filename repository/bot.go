@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Password hash stored for computer players; it is not a valid bcrypt hash, so
+// no password ever matches it
+const botPasswordHash = "!bot"
+
+// Create a computer player account unless it exists, and return its user ID
+func (pg *Postgres) EnsureBotUser(ctx context.Context, username, level string) (int, error) {
+	query := `
+		INSERT INTO "USER" (username, password_hash, bot_level)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (username) DO NOTHING
+	`
+
+	if _, err := pg.db.Exec(ctx, query, username, botPasswordHash, level); err != nil {
+		return 0, fmt.Errorf("failed to create bot user: %w", err)
+	}
+
+	var userID int
+	err := pg.db.QueryRow(ctx, `SELECT user_id FROM "USER" WHERE username = $1 AND bot_level = $2`, username, level).Scan(&userID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, fmt.Errorf("username %s is taken by another account", username)
+		}
+		return 0, fmt.Errorf("failed to get bot user: %w", err)
+	}
+
+	return userID, nil
+}
+
+// Retrieve every computer player
+func (pg *Postgres) GetBotUsers(ctx context.Context) ([]BotUser, error) {
+	query := `
+		SELECT user_id, username, bot_level
+		FROM "USER"
+		WHERE bot_level IS NOT NULL
+		ORDER BY user_id
+	`
+
+	rows, err := pg.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bot users: %w", err)
+	}
+	defer rows.Close()
+
+	bots := []BotUser{}
+	for rows.Next() {
+		var bot BotUser
+		if err := rows.Scan(&bot.UserID, &bot.Username, &bot.Level); err != nil {
+			return nil, fmt.Errorf("failed to scan bot user: %w", err)
+		}
+		bots = append(bots, bot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get bot users: %w", err)
+	}
+
+	return bots, nil
+}
@@ -5,11 +5,23 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// dbtx is satisfied by both the connection pool and an open transaction
+type dbtx interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 type Postgres struct {
-	db *pgxpool.Pool
+	db   dbtx
+	pool *pgxpool.Pool
+	dice DiceRoller // Rolls every game's dice when set, see SetDiceRoller
 }
 
 var (
@@ -27,7 +39,7 @@ func NewPG(ctx context.Context, connString string) (*Postgres, error) {
 			return
 		}
 
-		pgInstance = &Postgres{db}
+		pgInstance = &Postgres{db: db, pool: db}
 	})
 
 	if err != nil {
@@ -39,12 +51,32 @@ func NewPG(ctx context.Context, connString string) (*Postgres, error) {
 
 // Ping the database to check connectivity
 func (pg *Postgres) Ping(ctx context.Context) error {
-	return pg.db.Ping(ctx)
+	return pg.pool.Ping(ctx)
 }
 
 // Close the database connection pool
 func (pg *Postgres) Close() {
-	pg.db.Close()
+	pg.pool.Close()
+}
+
+// Run fn inside a transaction. The Postgres passed to fn executes every query
+// on the transaction, which is committed if fn returns nil and rolled back otherwise.
+func (pg *Postgres) withTx(ctx context.Context, fn func(tx *Postgres) error) error {
+	tx, err := pg.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&Postgres{db: tx, pool: pg.pool, dice: pg.dice}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
 }
 
 // Return the underlying pgxpool.Pool for executing queries
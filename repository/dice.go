@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// ============================================================================
+// Dice Rollers
+// ============================================================================
+
+// How a game's dice are rolled
+const (
+	DiceModeRandom = "random" // Fresh randomness from crypto/rand for every die
+	DiceModeSeeded = "seeded" // Drawn from a known seed, so games can be replayed
+	DiceModeFair   = "fair"   // Drawn from a secret seed committed to at the start and revealed at the end
+)
+
+// Length in bytes of the seed a fair game commits to
+const fairSeedLength = 32
+
+// DiceRoller produces the dice of a game. Dice are numbered from 0 in the
+// order the game rolls them, opening dice included, so a seeded roller gives
+// the same die for the same number every time.
+type DiceRoller interface {
+	RollDie(n int) (int, error)
+}
+
+// CryptoDiceRoller rolls every die from crypto/rand
+type CryptoDiceRoller struct{}
+
+// Roll a die (1-6), ignoring its number
+func (CryptoDiceRoller) RollDie(n int) (int, error) {
+	die, err := rand.Int(rand.Reader, big.NewInt(6))
+	if err != nil {
+		return 0, err
+	}
+	return int(die.Int64()) + 1, nil
+}
+
+// SeededDiceRoller derives every die from a seed. Die n is the first byte
+// below 252 of HMAC-SHA256(seed, "n:round"), taken modulo 6 plus 1, trying
+// rounds 0, 1, ... until such a byte turns up. Players of a fair game can
+// check each roll with the revealed seed this way.
+type SeededDiceRoller struct {
+	Seed []byte
+}
+
+// Return a seeded roller for a numeric seed, for tests and replays
+func NewSeededDiceRoller(seed int64) SeededDiceRoller {
+	return SeededDiceRoller{Seed: binary.BigEndian.AppendUint64(nil, uint64(seed))}
+}
+
+// Roll die n from the seed
+func (r SeededDiceRoller) RollDie(n int) (int, error) {
+	if len(r.Seed) == 0 {
+		return 0, fmt.Errorf("dice seed is missing")
+	}
+	for round := 0; ; round++ {
+		mac := hmac.New(sha256.New, r.Seed)
+		mac.Write([]byte(strconv.Itoa(n) + ":" + strconv.Itoa(round)))
+		for _, b := range mac.Sum(nil) {
+			// 252 is the largest multiple of 6 a byte holds, so every face is equally likely
+			if b < 252 {
+				return int(b%6) + 1, nil
+			}
+		}
+	}
+}
+
+// Return the commitment published for a fair game's seed: its SHA-256 in hex
+func DiceCommitment(seed []byte) string {
+	sum := sha256.Sum256(seed)
+	return hex.EncodeToString(sum[:])
+}
+
+// Work out the seed and commitment a new game stores for its dice mode. Fair
+// games always get a fresh secret seed; seeded games keep the one given.
+func gameDiceSeed(options GameOptions) (seed []byte, commitment *string, err error) {
+	switch options.DiceMode {
+	case DiceModeRandom:
+		return nil, nil, nil
+	case DiceModeSeeded:
+		if len(options.DiceSeed) == 0 {
+			return nil, nil, fmt.Errorf("seeded dice need a seed")
+		}
+		return options.DiceSeed, nil, nil
+	case DiceModeFair:
+		seed = make([]byte, fairSeedLength)
+		if _, err := rand.Read(seed); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate dice seed: %w", err)
+		}
+		sum := DiceCommitment(seed)
+		return seed, &sum, nil
+	}
+	return nil, nil, fmt.Errorf("unknown dice mode: %s", options.DiceMode)
+}
+
+// Use roller for every game's dice in place of each game's own mode, for
+// tests and replays. Nil goes back to each game's mode.
+func (pg *Postgres) SetDiceRoller(roller DiceRoller) {
+	pg.dice = roller
+}
+
+// Return the roller for a game's dice
+func (pg *Postgres) diceRoller(game *Game) DiceRoller {
+	if pg.dice != nil {
+		return pg.dice
+	}
+	if game.DiceMode == DiceModeSeeded || game.DiceMode == DiceModeFair {
+		return SeededDiceRoller{Seed: game.DiceSeed}
+	}
+	return CryptoDiceRoller{}
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"backgammon/business"
+)
+
+// ============================================================================
+// Dice Roll Log
+// ============================================================================
+
+// Return how many of a color's checkers wait on the bar and how many of its
+// entry points the opponent holds
+func barEntry(rules business.RuleSet, state *GameState, color business.Color) (bar, blocked int) {
+	bar = state.BarBlack
+	if color == business.ColorWhite {
+		bar = state.BarWhite
+	}
+	for die := 1; die <= 6; die++ {
+		if !entryOpen(rules, state, color, die) {
+			blocked++
+		}
+	}
+	return bar, blocked
+}
+
+// Report whether a die lets a color's checker in from the bar, onto its own
+// point 25 minus the die unless the opponent holds it
+func entryOpen(rules business.RuleSet, state *GameState, color business.Color, die int) bool {
+	point := rules.BoardPoint(25-die, color)
+	return business.CountCheckersOnPoint(state.BoardState, point, color.Opponent()) < 2
+}
+
+// Log a roll of the dice
+func (pg *Postgres) logDiceRoll(ctx context.Context, roll *DiceRoll) error {
+	query := `
+		INSERT INTO DICE_ROLL (
+			game_id, player_id, kind, die1, die2, dice_index,
+			bar_checkers, blocked_entry_points, entered, rolled_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+	`
+
+	_, err := pg.db.Exec(ctx, query,
+		roll.GameID,
+		roll.PlayerID,
+		roll.Kind,
+		roll.Die1,
+		roll.Die2,
+		roll.DiceIndex,
+		roll.BarCheckers,
+		roll.BlockedEntryPoints,
+		roll.Entered,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to log dice roll: %w", err)
+	}
+
+	return nil
+}
+
+// Log the turn roll of the player on turn, noting how it fared against the
+// opponent's points when checkers wait on the bar
+func (pg *Postgres) logTurnRoll(ctx context.Context, game *Game, state *GameState, die1, die2 int) error {
+	color := business.Color(game.Player1Color)
+	if game.CurrentTurn == game.Player2ID {
+		color = business.Color(game.Player2Color)
+	}
+	rules := business.GameRuleSet(game.RuleSet, game.GameNumber)
+
+	index := state.DiceCount
+	roll := &DiceRoll{
+		GameID:    game.GameID,
+		PlayerID:  game.CurrentTurn,
+		Kind:      "turn",
+		Die1:      die1,
+		Die2:      &die2,
+		DiceIndex: &index,
+	}
+
+	bar, blocked := barEntry(rules, state, color)
+	if bar > 0 {
+		entered := entryOpen(rules, state, color, die1) || entryOpen(rules, state, color, die2)
+		roll.BarCheckers = &bar
+		roll.BlockedEntryPoints = &blocked
+		roll.Entered = &entered
+	}
+
+	return pg.logDiceRoll(ctx, roll)
+}
+
+// Name the dice roll recovery is recorded under once it has run
+const diceRollBackfill = "dice_roll"
+
+// Recover the log of games played before it existed from their opening dice
+// and move history, once: later runs find the recovery recorded and do
+// nothing. Only each game's deciding opening dice survive, and turns without
+// a legal move left no trace, so those rolls stay missing. The first turn
+// plays the opening dice, so it only counts as a roll of its own in games
+// that had no opening roll. Return the number of rolls recovered.
+func (pg *Postgres) BackfillDiceRolls(ctx context.Context) (int64, error) {
+	var recovered int64
+	err := pg.withTx(ctx, func(tx *Postgres) error {
+		result, err := tx.db.Exec(ctx, `
+			INSERT INTO DATA_BACKFILL (name) VALUES ($1)
+			ON CONFLICT (name) DO NOTHING
+		`, diceRollBackfill)
+		if err != nil {
+			return fmt.Errorf("failed to record dice roll backfill: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return nil
+		}
+
+		query := `
+			INSERT INTO DICE_ROLL (game_id, player_id, kind, die1, die2, rolled_at)
+			SELECT r.game_id, r.player_id, r.kind, r.die1, r.die2, r.rolled_at
+			FROM (
+				SELECT g.game_id, g.player1_id AS player_id, 'opening'::dice_roll_kind_enum AS kind,
+				       s.opening_die_player1 AS die1, NULL::int AS die2,
+				       COALESCE(g.started_at, g.created_at) AS rolled_at
+				FROM GAME g
+				JOIN GAME_STATE s ON s.game_id = g.game_id
+				WHERE s.opening_die_player1 IS NOT NULL
+				UNION ALL
+				SELECT g.game_id, g.player2_id, 'opening', s.opening_die_player2, NULL,
+				       COALESCE(g.started_at, g.created_at)
+				FROM GAME g
+				JOIN GAME_STATE s ON s.game_id = g.game_id
+				WHERE s.opening_die_player2 IS NOT NULL
+				UNION ALL
+				SELECT * FROM (
+					SELECT DISTINCT ON (m.game_id, m.turn_number)
+					       m.game_id, m.player_id, 'turn'::dice_roll_kind_enum,
+					       (m.dice_roll->>0)::int, (m.dice_roll->>1)::int, m.timestamp
+					FROM MOVE m
+					JOIN GAME_STATE s ON s.game_id = m.game_id
+					WHERE m.dice_roll IS NOT NULL
+					  AND (m.turn_number > 1 OR s.opening_die_player1 IS NULL OR s.opening_die_player2 IS NULL)
+					ORDER BY m.game_id, m.turn_number, m.move_id
+				) t
+			) r
+			WHERE NOT EXISTS (SELECT 1 FROM DICE_ROLL d WHERE d.game_id = r.game_id)
+		`
+
+		result, err = tx.db.Exec(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to backfill dice rolls: %w", err)
+		}
+		recovered = result.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return recovered, nil
+}
+
+// Count the logged rolls of one player, or of everyone when userID is nil
+func (pg *Postgres) GetDiceRollCounts(ctx context.Context, userID *int) (*DiceRollCounts, error) {
+	query := `
+		SELECT kind, LEAST(die1, COALESCE(die2, die1)), GREATEST(die1, COALESCE(die2, die1)), COUNT(*)
+		FROM DICE_ROLL
+		WHERE $1::int IS NULL OR player_id = $1
+		GROUP BY 1, 2, 3
+	`
+
+	rows, err := pg.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dice roll counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := &DiceRollCounts{}
+	for rows.Next() {
+		var kind string
+		var low, high, count int
+		if err := rows.Scan(&kind, &low, &high, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan dice roll count: %w", err)
+		}
+		if kind == "opening" {
+			counts.OpeningDice[low-1] += count
+		} else {
+			counts.TurnRolls[low-1][high-1] += count
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get dice roll counts: %w", err)
+	}
+
+	// A roll enters unless both dice land on held points
+	query = `
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE entered),
+		       COALESCE(SUM(1 - (blocked_entry_points / 6.0) ^ 2), 0)::float8
+		FROM DICE_ROLL
+		WHERE entered IS NOT NULL AND ($1::int IS NULL OR player_id = $1)
+	`
+
+	err = pg.db.QueryRow(ctx, query, userID).Scan(&counts.BarRolls, &counts.BarEntered, &counts.BarExpected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bar entry counts: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"testing"
+
+	"backgammon/business"
+)
+
+func TestBarEntry(t *testing.T) {
+	// Black holds white's entry points for 1 and 3, and has a blot on the 5
+	board := make([]int, 24)
+	board[23] = -2
+	board[21] = -3
+	board[19] = -1
+	state := &GameState{BoardState: board, BarWhite: 2}
+
+	bar, blocked := barEntry(business.Backgammon{}, state, business.ColorWhite)
+	if bar != 2 || blocked != 2 {
+		t.Fatalf("got %d on the bar with %d points blocked, want 2 and 2", bar, blocked)
+	}
+	for die, open := range map[int]bool{1: false, 3: false, 5: true, 6: true} {
+		if got := entryOpen(business.Backgammon{}, state, business.ColorWhite, die); got != open {
+			t.Errorf("entering with %d: got open %v, want %v", die, got, open)
+		}
+	}
+
+	// Black enters on white's home board, which is empty
+	if _, blocked := barEntry(business.Backgammon{}, state, business.ColorBlack); blocked != 0 {
+		t.Fatalf("got %d of black's entry points blocked, want 0", blocked)
+	}
+}
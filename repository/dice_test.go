@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"backgammon/business"
+)
+
+// Play a game between two bots on the dice a game's roller gives, numbered as
+// the game rolls them with the opening dice first, and return its plays
+func playDiceGame(t *testing.T, pg *Postgres, game *Game) []string {
+	t.Helper()
+	roller := pg.diceRoller(game)
+	count := 0
+	roll := func() int {
+		die, err := roller.RollDie(count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+		return die
+	}
+
+	// Each side rolls one die until they differ, and the higher plays both
+	white, black := roll(), roll()
+	for white == black {
+		white, black = roll(), roll()
+	}
+	color := business.ColorWhite
+	if black > white {
+		color = business.ColorBlack
+	}
+	dice := []int{white, black}
+
+	bot := business.NewBot(business.BotHard, business.HeuristicEvaluator{}, nil)
+	pos := business.StartingPosition()
+	plays := []string{}
+	for range 1000 {
+		if business.CheckWinCondition(pos, business.ColorWhite) || business.CheckWinCondition(pos, business.ColorBlack) {
+			return plays
+		}
+		if play, ok := bot.ChoosePlay(pos, color, dice, make([]bool, len(dice))); ok {
+			pos = play.Result
+			plays = append(plays, business.FormatPlay(business.Backgammon{}, play.Moves, color))
+		}
+
+		color = color.Opponent()
+		dice = business.TurnDice(roll(), roll())
+	}
+	t.Fatal("game did not finish")
+	return nil
+}
+
+func TestSetDiceRollerReplaysGames(t *testing.T) {
+	pg := &Postgres{}
+	game := &Game{GameOptions: GameOptions{DiceMode: DiceModeRandom}}
+
+	pg.SetDiceRoller(NewSeededDiceRoller(7))
+	first := playDiceGame(t, pg, game)
+	if again := playDiceGame(t, pg, game); !reflect.DeepEqual(first, again) {
+		t.Fatal("the same seed played a different game")
+	}
+
+	pg.SetDiceRoller(NewSeededDiceRoller(8))
+	if other := playDiceGame(t, pg, game); reflect.DeepEqual(first, other) {
+		t.Fatal("different seeds played the same game")
+	}
+
+	// Without an override a seeded game rolls from its own seed
+	pg.SetDiceRoller(nil)
+	seeded := &Game{GameOptions: GameOptions{DiceMode: DiceModeSeeded, DiceSeed: NewSeededDiceRoller(7).Seed}}
+	if replay := playDiceGame(t, pg, seeded); !reflect.DeepEqual(first, replay) {
+		t.Fatal("a seeded game did not replay the seed's game")
+	}
+	if _, ok := pg.diceRoller(game).(CryptoDiceRoller); !ok {
+		t.Fatal("a random game is not rolled from crypto/rand")
+	}
+}
+
+func TestSeededDiceRoller(t *testing.T) {
+	roller := NewSeededDiceRoller(1)
+	faces := map[int]int{}
+	for n := range 600 {
+		die, err := roller.RollDie(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if die < 1 || die > 6 {
+			t.Fatalf("die %d rolled %d", n, die)
+		}
+		faces[die]++
+	}
+	for face := 1; face <= 6; face++ {
+		if faces[face] < 60 || faces[face] > 140 {
+			t.Errorf("face %d came up %d times in 600", face, faces[face])
+		}
+	}
+
+	if _, err := (SeededDiceRoller{}).RollDie(0); err == nil {
+		t.Fatal("rolled without a seed")
+	}
+}
+
+func TestGameDiceSeed(t *testing.T) {
+	seed, commitment, err := gameDiceSeed(GameOptions{DiceMode: DiceModeFair})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seed) != fairSeedLength || commitment == nil || *commitment != DiceCommitment(seed) {
+		t.Fatal("fair game's commitment does not match its seed")
+	}
+
+	if _, _, err := gameDiceSeed(GameOptions{DiceMode: DiceModeSeeded}); err == nil {
+		t.Fatal("seeded game accepted without a seed")
+	}
+	if seed, commitment, err := gameDiceSeed(GameOptions{DiceMode: DiceModeRandom}); err != nil || seed != nil || commitment != nil {
+		t.Fatal("random game was given a seed")
+	}
+}
@@ -6,12 +6,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
 
-// Create a new game between two players with random color and turn assignment
-func (pg *Postgres) CreateGame(ctx context.Context, player1ID, player2ID int) (int, error) {
+// Create a new game between two players with random color assignment. The
+// game stays pending until the opening roll decides who moves first.
+func (pg *Postgres) CreateGame(ctx context.Context, player1ID, player2ID int, options GameOptions) (int, error) {
+	return pg.createGame(ctx, player1ID, player2ID, options, nil, 1, false)
+}
+
+// Create a game, optionally as the numbered game of a match
+func (pg *Postgres) createGame(ctx context.Context, player1ID, player2ID int, options GameOptions, matchID *int, gameNumber int, crawford bool) (int, error) {
 	// Validate that players are different
 	if player1ID == player2ID {
 		return 0, fmt.Errorf("cannot create game with same player")
@@ -32,7 +39,7 @@ func (pg *Postgres) CreateGame(ctx context.Context, player1ID, player2ID int) (i
 		player2Color = "white"
 	}
 
-	// Randomly select starting player (0 = player1, 1 = player2)
+	// Provisional starting player until the opening roll (0 = player1, 1 = player2)
 	turnRand, err := rand.Int(rand.Reader, big.NewInt(2))
 	if err != nil {
 		return 0, fmt.Errorf("failed to generate random turn: %w", err)
@@ -45,6 +52,22 @@ func (pg *Postgres) CreateGame(ctx context.Context, player1ID, player2ID int) (i
 		currentTurn = player2ID
 	}
 
+	if options.DiceMode == "" {
+		options.DiceMode = DiceModeRandom
+	}
+	if options.RuleSet == "" {
+		options.RuleSet = "backgammon"
+	}
+	diceSeed, diceCommitment, err := gameDiceSeed(options)
+	if err != nil {
+		return 0, err
+	}
+
+	variant, startingBoardJSON, err := gameVariant(options)
+	if err != nil {
+		return 0, err
+	}
+
 	// Create game record
 	query := `
 		INSERT INTO GAME (
@@ -54,14 +77,29 @@ func (pg *Postgres) CreateGame(ctx context.Context, player1ID, player2ID int) (i
 			game_status,
 			player1_color,
 			player2_color,
+			allow_beavers,
+			allow_hints,
+			time_delay_seconds,
+			time_reserve_seconds,
+			match_id,
+			game_number,
+			crawford,
+			dice_mode,
+			dice_seed,
+			dice_commitment,
+			variant,
+			starting_board,
+			ruleset,
 			created_at
 		)
-		VALUES ($1, $2, $3, 'pending', $4, $5, NOW())
+		VALUES ($1, $2, $3, 'pending', $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, NOW())
 		RETURNING game_id
 	`
 
 	var gameID int
-	err = pg.db.QueryRow(ctx, query, player1ID, player2ID, currentTurn, player1Color, player2Color).Scan(&gameID)
+	err = pg.db.QueryRow(ctx, query, player1ID, player2ID, currentTurn, player1Color, player2Color,
+		options.AllowBeavers, options.AllowHints, options.DelaySeconds, options.ReserveSeconds, matchID, gameNumber, crawford,
+		options.DiceMode, diceSeed, diceCommitment, variant, startingBoardJSON, options.RuleSet).Scan(&gameID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create game: %w", err)
 	}
@@ -69,6 +107,137 @@ func (pg *Postgres) CreateGame(ctx context.Context, player1ID, player2ID int) (i
 	return gameID, nil
 }
 
+// Create a game already under way from a set-up position, with player1
+// playing player1Color and currentTurn to act. The state is stored as given,
+// and as the game's starting position; its game ID is filled in.
+func (pg *Postgres) CreateGameFromPosition(ctx context.Context, player1ID, player2ID int, player1Color string, currentTurn int, options GameOptions, crawford bool, state *GameState) (int, error) {
+	if player1ID == player2ID {
+		return 0, fmt.Errorf("cannot create game with same player")
+	}
+
+	player2Color := "black"
+	if player1Color == "black" {
+		player2Color = "white"
+	}
+
+	if options.DiceMode == "" {
+		options.DiceMode = DiceModeRandom
+	}
+	if options.RuleSet == "" {
+		options.RuleSet = "backgammon"
+	}
+	diceSeed, diceCommitment, err := gameDiceSeed(options)
+	if err != nil {
+		return 0, err
+	}
+
+	options.StartingBoard = state.BoardState
+	variant, startingBoardJSON, err := gameVariant(options)
+	if err != nil {
+		return 0, err
+	}
+
+	boardJSON, err := json.Marshal(state.BoardState)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal board state: %w", err)
+	}
+
+	var diceRollJSON []byte
+	var diceUsedJSON []byte
+
+	if state.DiceRoll != nil {
+		diceRollJSON, err = json.Marshal(state.DiceRoll)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal dice roll: %w", err)
+		}
+	}
+
+	if state.DiceUsed != nil {
+		diceUsedJSON, err = json.Marshal(state.DiceUsed)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal dice used: %w", err)
+		}
+	}
+
+	var gameID int
+	err = pg.withTx(ctx, func(tx *Postgres) error {
+		query := `
+			INSERT INTO GAME (
+				player1_id, player2_id, current_turn, game_status, player1_color, player2_color,
+				allow_beavers, allow_hints, time_delay_seconds, time_reserve_seconds,
+				crawford, dice_mode, dice_seed, dice_commitment, variant, starting_board,
+				starting_bar_white, starting_bar_black, starting_borne_off_white, starting_borne_off_black,
+				ruleset, created_at, started_at
+			)
+			VALUES ($1, $2, $3, 'in_progress', $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, NOW(), NOW())
+			RETURNING game_id
+		`
+
+		err := tx.db.QueryRow(ctx, query, player1ID, player2ID, currentTurn, player1Color, player2Color,
+			options.AllowBeavers, options.AllowHints, options.DelaySeconds, options.ReserveSeconds, crawford,
+			options.DiceMode, diceSeed, diceCommitment, variant, startingBoardJSON,
+			state.BarWhite, state.BarBlack, state.BornedOffWhite, state.BornedOffBlack,
+			options.RuleSet).Scan(&gameID)
+		if err != nil {
+			return fmt.Errorf("failed to create game: %w", err)
+		}
+
+		query = `
+			INSERT INTO GAME_STATE (
+				game_id, board_state, bar_white, bar_black, borne_off_white, borne_off_black,
+				dice_roll, dice_used, turn_number, cube_value, cube_owner, cube_offered_by,
+				cube_beavered, last_updated
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW())
+		`
+
+		_, err = tx.db.Exec(ctx, query,
+			gameID,
+			boardJSON,
+			state.BarWhite,
+			state.BarBlack,
+			state.BornedOffWhite,
+			state.BornedOffBlack,
+			diceRollJSON,
+			diceUsedJSON,
+			state.TurnNumber,
+			state.CubeValue,
+			state.CubeOwner,
+			state.CubeOfferedBy,
+			state.CubeBeavered,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to initialize game state: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	state.GameID = gameID
+	return gameID, nil
+}
+
+// Return the variant a new game stores, standard by default, and its
+// starting board as JSON
+func gameVariant(options GameOptions) (string, []byte, error) {
+	variant := options.Variant
+	if variant == "" {
+		variant = "standard"
+	}
+	if len(options.StartingBoard) != 24 {
+		return "", nil, fmt.Errorf("starting board must have 24 points")
+	}
+
+	boardJSON, err := json.Marshal(options.StartingBoard)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal starting board: %w", err)
+	}
+	return variant, boardJSON, nil
+}
+
 // Retrieve a game by its ID
 func (pg *Postgres) GetGameByID(ctx context.Context, gameID int) (*Game, error) {
 	query := `
@@ -79,16 +248,36 @@ func (pg *Postgres) GetGameByID(ctx context.Context, gameID int) (*Game, error)
 			current_turn,
 			game_status,
 			winner_id,
+			result_type,
+			points_won,
 			created_at,
 			started_at,
 			ended_at,
 			player1_color,
-			player2_color
+			player2_color,
+			allow_beavers,
+			allow_hints,
+			time_delay_seconds,
+			time_reserve_seconds,
+			match_id,
+			game_number,
+			crawford,
+			dice_mode,
+			dice_seed,
+			dice_commitment,
+			variant,
+			starting_board,
+			starting_bar_white,
+			starting_bar_black,
+			starting_borne_off_white,
+			starting_borne_off_black,
+			ruleset
 		FROM GAME
 		WHERE game_id = $1
 	`
 
 	var game Game
+	var startingBoardJSON []byte
 	err := pg.db.QueryRow(ctx, query, gameID).Scan(
 		&game.GameID,
 		&game.Player1ID,
@@ -96,16 +285,39 @@ func (pg *Postgres) GetGameByID(ctx context.Context, gameID int) (*Game, error)
 		&game.CurrentTurn,
 		&game.GameStatus,
 		&game.WinnerID,
+		&game.ResultType,
+		&game.PointsWon,
 		&game.CreatedAt,
 		&game.StartedAt,
 		&game.EndedAt,
 		&game.Player1Color,
 		&game.Player2Color,
+		&game.AllowBeavers,
+		&game.AllowHints,
+		&game.DelaySeconds,
+		&game.ReserveSeconds,
+		&game.MatchID,
+		&game.GameNumber,
+		&game.Crawford,
+		&game.DiceMode,
+		&game.DiceSeed,
+		&game.DiceCommitment,
+		&game.Variant,
+		&startingBoardJSON,
+		&game.StartingBarWhite,
+		&game.StartingBarBlack,
+		&game.StartingBornedOffWhite,
+		&game.StartingBornedOffBlack,
+		&game.RuleSet,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get game: %w", err)
 	}
 
+	if err := json.Unmarshal(startingBoardJSON, &game.StartingBoard); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal starting board: %w", err)
+	}
+
 	return &game, nil
 }
 
@@ -125,8 +337,8 @@ func (pg *Postgres) UpdateGameStatus(ctx context.Context, gameID int, status str
 	return nil
 }
 
-// Mark a game as abandoned with the opponent as winner
-func (pg *Postgres) ForfeitGame(ctx context.Context, gameID int, forfeitingPlayerID int) error {
+// Mark a game as abandoned with the opponent as winner and record the result
+func (pg *Postgres) ForfeitGame(ctx context.Context, gameID int, forfeitingPlayerID int, result GameResult) error {
 	// Get game details to determine the winner
 	game, err := pg.GetGameByID(ctx, gameID)
 	if err != nil {
@@ -148,20 +360,25 @@ func (pg *Postgres) ForfeitGame(ctx context.Context, gameID int, forfeitingPlaye
 		UPDATE GAME
 		SET game_status = 'abandoned',
 		    winner_id = $2,
+		    result_type = $3,
+		    points_won = $4,
 		    ended_at = NOW()
-		WHERE game_id = $1
+		WHERE game_id = $1 AND game_status IN ('pending', 'in_progress')
 	`
 
-	_, err = pg.db.Exec(ctx, query, gameID, winnerID)
+	tag, err := pg.db.Exec(ctx, query, gameID, winnerID, result.ResultType, result.PointsWon)
 	if err != nil {
 		return fmt.Errorf("failed to forfeit game: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("game already finished")
+	}
 
 	return nil
 }
 
-// Mark a game as completed with a winner
-func (pg *Postgres) CompleteGame(ctx context.Context, gameID int, winnerID int) error {
+// Mark a game as completed with a winner and record the result
+func (pg *Postgres) CompleteGame(ctx context.Context, gameID int, winnerID int, result GameResult) error {
 	// Verify the winner is a player in this game
 	game, err := pg.GetGameByID(ctx, gameID)
 	if err != nil {
@@ -176,14 +393,19 @@ func (pg *Postgres) CompleteGame(ctx context.Context, gameID int, winnerID int)
 		UPDATE GAME
 		SET game_status = 'completed',
 		    winner_id = $2,
+		    result_type = $3,
+		    points_won = $4,
 		    ended_at = NOW()
-		WHERE game_id = $1
+		WHERE game_id = $1 AND game_status IN ('pending', 'in_progress')
 	`
 
-	_, err = pg.db.Exec(ctx, query, gameID, winnerID)
+	tag, err := pg.db.Exec(ctx, query, gameID, winnerID, result.ResultType, result.PointsWon)
 	if err != nil {
 		return fmt.Errorf("failed to complete game: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("game already finished")
+	}
 
 	return nil
 }
@@ -223,9 +445,21 @@ func (pg *Postgres) GetGameWithPlayers(ctx context.Context, gameID int) (*GameWi
 			g.current_turn,
 			g.game_status,
 			g.winner_id,
+			g.result_type,
+			g.points_won,
 			g.created_at,
 			g.started_at,
-			g.ended_at
+			g.ended_at,
+			g.allow_beavers,
+			g.allow_hints,
+			g.time_delay_seconds,
+			g.time_reserve_seconds,
+			g.match_id,
+			g.game_number,
+			g.crawford,
+			g.dice_mode,
+			g.variant,
+			g.ruleset
 		FROM GAME g
 		JOIN "USER" u1 ON g.player1_id = u1.user_id
 		JOIN "USER" u2 ON g.player2_id = u2.user_id
@@ -244,9 +478,21 @@ func (pg *Postgres) GetGameWithPlayers(ctx context.Context, gameID int) (*GameWi
 		&game.CurrentTurn,
 		&game.GameStatus,
 		&game.WinnerID,
+		&game.ResultType,
+		&game.PointsWon,
 		&game.CreatedAt,
 		&game.StartedAt,
 		&game.EndedAt,
+		&game.AllowBeavers,
+		&game.AllowHints,
+		&game.DelaySeconds,
+		&game.ReserveSeconds,
+		&game.MatchID,
+		&game.GameNumber,
+		&game.Crawford,
+		&game.DiceMode,
+		&game.Variant,
+		&game.RuleSet,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get game with players: %w", err)
@@ -269,9 +515,21 @@ func (pg *Postgres) GetActiveGamesForUser(ctx context.Context, userID int) ([]Ga
 			g.current_turn,
 			g.game_status,
 			g.winner_id,
+			g.result_type,
+			g.points_won,
 			g.created_at,
 			g.started_at,
-			g.ended_at
+			g.ended_at,
+			g.allow_beavers,
+			g.allow_hints,
+			g.time_delay_seconds,
+			g.time_reserve_seconds,
+			g.match_id,
+			g.game_number,
+			g.crawford,
+			g.dice_mode,
+			g.variant,
+			g.ruleset
 		FROM GAME g
 		JOIN "USER" u1 ON g.player1_id = u1.user_id
 		JOIN "USER" u2 ON g.player2_id = u2.user_id
@@ -300,9 +558,21 @@ func (pg *Postgres) GetActiveGamesForUser(ctx context.Context, userID int) ([]Ga
 			&game.CurrentTurn,
 			&game.GameStatus,
 			&game.WinnerID,
+			&game.ResultType,
+			&game.PointsWon,
 			&game.CreatedAt,
 			&game.StartedAt,
 			&game.EndedAt,
+			&game.AllowBeavers,
+			&game.AllowHints,
+			&game.DelaySeconds,
+			&game.ReserveSeconds,
+			&game.MatchID,
+			&game.GameNumber,
+			&game.Crawford,
+			&game.DiceMode,
+			&game.Variant,
+			&game.RuleSet,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan active game: %w", err)
@@ -313,52 +583,54 @@ func (pg *Postgres) GetActiveGamesForUser(ctx context.Context, userID int) ([]Ga
 	return games, nil
 }
 
+// Check whether a user is a player in an unfinished game with hints disabled
+func (pg *Postgres) HasActiveGameWithoutHints(ctx context.Context, userID int) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM GAME
+			WHERE (player1_id = $1 OR player2_id = $1)
+			  AND game_status IN ('pending', 'in_progress')
+			  AND NOT allow_hints
+		)
+	`
+
+	var exists bool
+	if err := pg.db.QueryRow(ctx, query, userID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check games without hints: %w", err)
+	}
+
+	return exists, nil
+}
+
 // ============================================================================
 // GAME_STATE Management
 // ============================================================================
 
-// Create the initial board state for a new game
+// Create the initial board state for a new game from its variant's starting
+// board. Acey-Deucey games start with every checker in hand.
 func (pg *Postgres) InitializeGameState(ctx context.Context, gameID int) error {
-	// Standard backgammon setup:
-	// White moves from 24->1 (counterclockwise), Black moves from 1->24 (clockwise)
-	// Point 1: 2 black, Point 6: 5 white, Point 8: 3 white, Point 12: 5 black
-	// Point 13: 5 white, Point 17: 3 black, Point 19: 5 black, Point 24: 2 white
-	// Using array indices 0-23 for points 1-24
-	initialBoard := make([]int, 24)
-	initialBoard[0] = -2   // Point 1: 2 black
-	initialBoard[5] = 5    // Point 6: 5 white
-	initialBoard[7] = 3    // Point 8: 3 white
-	initialBoard[11] = -5  // Point 12: 5 black
-	initialBoard[12] = 5   // Point 13: 5 white
-	initialBoard[16] = -3  // Point 17: 3 black
-	initialBoard[18] = -5  // Point 19: 5 black
-	initialBoard[23] = 2   // Point 24: 2 white
-
-	// TESTING SETUP (commented out - for testing bear-off):
-	// Both players have checkers in home board for testing bear-off
-	// White home: points 1-6, Black home: points 19-24
-	// initialBoard := make([]int, 24)
-	// initialBoard[3] = 5    // Point 4: 5 white
-	// initialBoard[4] = 5    // Point 5: 5 white
-	// initialBoard[5] = 5    // Point 6: 5 white
-	// initialBoard[18] = -5  // Point 19: 5 black
-	// initialBoard[19] = -5  // Point 20: 5 black
-	// initialBoard[20] = -5  // Point 21: 5 black
-
-	boardJSON, err := json.Marshal(initialBoard)
-	if err != nil {
-		return fmt.Errorf("failed to marshal board state: %w", err)
-	}
-
+	// Timed games start with a full reserve on both clocks
 	query := `
 		INSERT INTO GAME_STATE (
 			game_id, board_state, bar_white, bar_black,
-			borne_off_white, borne_off_black, dice_roll, dice_used, last_updated
+			borne_off_white, borne_off_black, hand_white, hand_black, dice_roll, dice_used,
+			clock_player1_ms, clock_player2_ms, last_updated
 		)
-		VALUES ($1, $2, 0, 0, 0, 0, NULL, NULL, NOW())
+		SELECT $1, starting_board, 0, 0, 0, 0, hand, hand, NULL, NULL,
+		       CASE WHEN timed THEN reserve_ms END,
+		       CASE WHEN timed THEN reserve_ms END,
+		       NOW()
+		FROM (
+			SELECT starting_board,
+			       CASE WHEN ruleset = 'aceydeucey' THEN 15 ELSE 0 END AS hand,
+			       time_delay_seconds > 0 OR time_reserve_seconds > 0 AS timed,
+			       time_reserve_seconds * 1000::BIGINT AS reserve_ms
+			FROM GAME
+			WHERE game_id = $1
+		) tc
 	`
 
-	_, err = pg.db.Exec(ctx, query, gameID, boardJSON)
+	_, err := pg.db.Exec(ctx, query, gameID)
 	if err != nil {
 		return fmt.Errorf("failed to initialize game state: %w", err)
 	}
@@ -371,13 +643,18 @@ func (pg *Postgres) GetGameState(ctx context.Context, gameID int) (*GameState, e
 	query := `
 		SELECT
 			state_id, game_id, board_state, bar_white, bar_black,
-			borne_off_white, borne_off_black, dice_roll, dice_used, last_updated
+			borne_off_white, borne_off_black, hand_white, hand_black, pinned,
+			dice_roll, dice_used, turn_number, dice_count, bonus_stage,
+			cube_value, cube_owner, cube_offered_by, cube_beavered, version,
+			opening_die_player1, opening_die_player2,
+			clock_player1_ms, clock_player2_ms, clock_running, clock_started_at, last_updated
 		FROM GAME_STATE
 		WHERE game_id = $1
 	`
 
 	var state GameState
 	var boardJSON []byte
+	var pinnedJSON []byte
 	var diceRollJSON []byte
 	var diceUsedJSON []byte
 
@@ -389,8 +666,25 @@ func (pg *Postgres) GetGameState(ctx context.Context, gameID int) (*GameState, e
 		&state.BarBlack,
 		&state.BornedOffWhite,
 		&state.BornedOffBlack,
+		&state.HandWhite,
+		&state.HandBlack,
+		&pinnedJSON,
 		&diceRollJSON,
 		&diceUsedJSON,
+		&state.TurnNumber,
+		&state.DiceCount,
+		&state.BonusStage,
+		&state.CubeValue,
+		&state.CubeOwner,
+		&state.CubeOfferedBy,
+		&state.CubeBeavered,
+		&state.Version,
+		&state.OpeningDie1,
+		&state.OpeningDie2,
+		&state.ClockPlayer1,
+		&state.ClockPlayer2,
+		&state.ClockRunning,
+		&state.ClockStartedAt,
 		&state.LastUpdated,
 	)
 	if err != nil {
@@ -405,6 +699,13 @@ func (pg *Postgres) GetGameState(ctx context.Context, gameID int) (*GameState, e
 		return nil, fmt.Errorf("failed to unmarshal board state: %w", err)
 	}
 
+	// Unmarshal pinned checkers if present
+	if pinnedJSON != nil {
+		if err := json.Unmarshal(pinnedJSON, &state.Pinned); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal pinned checkers: %w", err)
+		}
+	}
+
 	// Unmarshal dice roll if present
 	if diceRollJSON != nil {
 		if err := json.Unmarshal(diceRollJSON, &state.DiceRoll); err != nil {
@@ -429,9 +730,17 @@ func (pg *Postgres) UpdateGameState(ctx context.Context, state *GameState) error
 		return fmt.Errorf("failed to marshal board state: %w", err)
 	}
 
+	var pinnedJSON []byte
 	var diceRollJSON []byte
 	var diceUsedJSON []byte
 
+	if state.Pinned != nil {
+		pinnedJSON, err = json.Marshal(state.Pinned)
+		if err != nil {
+			return fmt.Errorf("failed to marshal pinned checkers: %w", err)
+		}
+	}
+
 	if state.DiceRoll != nil {
 		diceRollJSON, err = json.Marshal(state.DiceRoll)
 		if err != nil {
@@ -455,6 +764,19 @@ func (pg *Postgres) UpdateGameState(ctx context.Context, state *GameState) error
 		    borne_off_black = $6,
 		    dice_roll = $7,
 		    dice_used = $8,
+		    cube_value = $9,
+		    cube_owner = $10,
+		    cube_offered_by = $11,
+		    cube_beavered = $12,
+		    clock_player1_ms = $13,
+		    clock_player2_ms = $14,
+		    clock_running = $15,
+		    clock_started_at = $16,
+		    pinned = $17,
+		    hand_white = $18,
+		    hand_black = $19,
+		    bonus_stage = $20,
+		    version = version + 1,
 		    last_updated = NOW()
 		WHERE game_id = $1
 	`
@@ -468,6 +790,18 @@ func (pg *Postgres) UpdateGameState(ctx context.Context, state *GameState) error
 		state.BornedOffBlack,
 		diceRollJSON,
 		diceUsedJSON,
+		state.CubeValue,
+		state.CubeOwner,
+		state.CubeOfferedBy,
+		state.CubeBeavered,
+		state.ClockPlayer1,
+		state.ClockPlayer2,
+		state.ClockRunning,
+		state.ClockStartedAt,
+		pinnedJSON,
+		state.HandWhite,
+		state.HandBlack,
+		state.BonusStage,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update game state: %w", err)
@@ -480,22 +814,44 @@ func (pg *Postgres) UpdateGameState(ctx context.Context, state *GameState) error
 	return nil
 }
 
-// Generate a new dice roll for the current turn
-func (pg *Postgres) RollDice(ctx context.Context, gameID int) ([]int, error) {
-	// Generate two random dice (1-6)
-	die1, err := rand.Int(rand.Reader, big.NewInt(6))
+// Generate a new dice roll for the current turn. The game and state rows are
+// locked while check runs, so two concurrent rolls cannot both succeed.
+func (pg *Postgres) RollDice(ctx context.Context, gameID int, check func(game *Game, state *GameState) error) ([]int, error) {
+	var dice []int
+	err := pg.withTx(ctx, func(tx *Postgres) error {
+		game, state, err := tx.lockGame(ctx, gameID)
+		if err != nil {
+			return err
+		}
+
+		if err := check(game, state); err != nil {
+			return err
+		}
+
+		dice, err = tx.rollDice(ctx, game, state)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dice, nil
+}
+
+// Write a fresh dice roll to the game state
+func (pg *Postgres) rollDice(ctx context.Context, game *Game, state *GameState) ([]int, error) {
+	// Roll the game's next two dice (1-6)
+	roller := pg.diceRoller(game)
+	val1, err := roller.RollDie(state.DiceCount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate die 1: %w", err)
 	}
 
-	die2, err := rand.Int(rand.Reader, big.NewInt(6))
+	val2, err := roller.RollDie(state.DiceCount + 1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate die 2: %w", err)
 	}
 
-	val1 := int(die1.Int64()) + 1
-	val2 := int(die2.Int64()) + 1
-
 	// For doubles, player gets 4 moves of the same value
 	var dice []int
 	var diceUsed []bool
@@ -519,11 +875,12 @@ func (pg *Postgres) RollDice(ctx context.Context, gameID int) ([]int, error) {
 
 	query := `
 		UPDATE GAME_STATE
-		SET dice_roll = $2, dice_used = $3, last_updated = NOW()
+		SET dice_roll = $2, dice_used = $3, turn_number = turn_number + 1, dice_count = dice_count + 2,
+		    cube_beavered = FALSE, version = version + 1, last_updated = NOW()
 		WHERE game_id = $1
 	`
 
-	result, err := pg.db.Exec(ctx, query, gameID, diceJSON, diceUsedJSON)
+	result, err := pg.db.Exec(ctx, query, game.GameID, diceJSON, diceUsedJSON)
 	if err != nil {
 		return nil, fmt.Errorf("failed to roll dice: %w", err)
 	}
@@ -532,10 +889,192 @@ func (pg *Postgres) RollDice(ctx context.Context, gameID int) ([]int, error) {
 		return nil, fmt.Errorf("game state not found")
 	}
 
+	if err := pg.logTurnRoll(ctx, game, state, val1, val2); err != nil {
+		return nil, err
+	}
+
 	return dice, nil
 }
 
-// Clear the dice roll at the end of a turn
+// Roll a player's opening die for a pending game. The game and state rows are
+// locked while check runs. Once both players have rolled, matching dice are
+// cleared for a re-roll; otherwise the higher roller takes the first turn,
+// playing both dice shown, and the game starts.
+func (pg *Postgres) RollOpeningDie(ctx context.Context, gameID, playerID int, check func(game *Game, state *GameState) error) (*OpeningRoll, error) {
+	var roll OpeningRoll
+	err := pg.withTx(ctx, func(tx *Postgres) error {
+		game, state, err := tx.lockGame(ctx, gameID)
+		if err != nil {
+			return err
+		}
+
+		if err := check(game, state); err != nil {
+			return err
+		}
+
+		roll.Die, err = tx.diceRoller(game).RollDie(state.DiceCount)
+		if err != nil {
+			return fmt.Errorf("failed to generate opening die: %w", err)
+		}
+
+		index := state.DiceCount
+		err = tx.logDiceRoll(ctx, &DiceRoll{
+			GameID:    gameID,
+			PlayerID:  playerID,
+			Kind:      "opening",
+			Die1:      roll.Die,
+			DiceIndex: &index,
+		})
+		if err != nil {
+			return err
+		}
+
+		if playerID == game.Player1ID {
+			state.OpeningDie1 = &roll.Die
+		} else {
+			state.OpeningDie2 = &roll.Die
+		}
+
+		// Wait for the other player's die
+		if state.OpeningDie1 == nil || state.OpeningDie2 == nil {
+			return tx.setOpeningDice(ctx, gameID, state.OpeningDie1, state.OpeningDie2, nil, nil)
+		}
+
+		// Ties are re-rolled by both players
+		die1, die2 := *state.OpeningDie1, *state.OpeningDie2
+		if die1 == die2 {
+			roll.Tie = true
+			return tx.setOpeningDice(ctx, gameID, nil, nil, nil, nil)
+		}
+
+		if die1 > die2 {
+			roll.StartingPlayer = game.Player1ID
+			roll.Dice = []int{die1, die2}
+		} else {
+			roll.StartingPlayer = game.Player2ID
+			roll.Dice = []int{die2, die1}
+		}
+
+		if err := tx.setOpeningDice(ctx, gameID, state.OpeningDie1, state.OpeningDie2, roll.Dice, []bool{false, false}); err != nil {
+			return err
+		}
+		if err := tx.startClock(ctx, gameID, roll.StartingPlayer); err != nil {
+			return err
+		}
+		if err := tx.UpdateGameTurn(ctx, gameID, roll.StartingPlayer); err != nil {
+			return err
+		}
+		return tx.StartGame(ctx, gameID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &roll, nil
+}
+
+// Store the opening roll dice and, once decided, the first turn's dice. Each
+// call records one more opening die rolled.
+func (pg *Postgres) setOpeningDice(ctx context.Context, gameID int, die1, die2 *int, dice []int, diceUsed []bool) error {
+	var diceJSON, diceUsedJSON []byte
+	if dice != nil {
+		var err error
+		diceJSON, err = json.Marshal(dice)
+		if err != nil {
+			return fmt.Errorf("failed to marshal dice: %w", err)
+		}
+		diceUsedJSON, err = json.Marshal(diceUsed)
+		if err != nil {
+			return fmt.Errorf("failed to marshal dice used: %w", err)
+		}
+	}
+
+	// The first turn's dice count as its roll
+	turns := 0
+	if dice != nil {
+		turns = 1
+	}
+
+	query := `
+		UPDATE GAME_STATE
+		SET opening_die_player1 = $2,
+		    opening_die_player2 = $3,
+		    dice_roll = $4,
+		    dice_used = $5,
+		    turn_number = turn_number + $6,
+		    dice_count = dice_count + 1,
+		    version = version + 1,
+		    last_updated = NOW()
+		WHERE game_id = $1
+	`
+
+	result, err := pg.db.Exec(ctx, query, gameID, die1, die2, diceJSON, diceUsedJSON, turns)
+	if err != nil {
+		return fmt.Errorf("failed to set opening roll: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("game state not found")
+	}
+
+	return nil
+}
+
+// Start a timed game's clock for the player to move first
+func (pg *Postgres) startClock(ctx context.Context, gameID, playerID int) error {
+	query := `
+		UPDATE GAME_STATE
+		SET clock_running = $2,
+		    clock_started_at = $3
+		WHERE game_id = $1 AND clock_player1_ms IS NOT NULL
+	`
+
+	// Clock times are written from the server clock, which also measures them
+	_, err := pg.db.Exec(ctx, query, gameID, playerID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to start clock: %w", err)
+	}
+
+	return nil
+}
+
+// Return the IDs of in-progress games whose running clock may need settling:
+// the player has used their whole delay and reserve, or has used the delay
+// after rolling
+func (pg *Postgres) GetGamesWithClocksDue(ctx context.Context, now time.Time) ([]int, error) {
+	query := `
+		SELECT g.game_id
+		FROM GAME g
+		JOIN GAME_STATE gs ON gs.game_id = g.game_id
+		WHERE g.game_status = 'in_progress'
+		  AND gs.clock_running IS NOT NULL
+		  AND (
+			(gs.dice_roll IS NOT NULL AND gs.clock_started_at + make_interval(secs => g.time_delay_seconds) <= $1)
+			OR gs.clock_started_at + make_interval(secs => g.time_delay_seconds
+				+ (CASE WHEN gs.clock_running = g.player1_id THEN gs.clock_player1_ms ELSE gs.clock_player2_ms END) / 1000.0) < $1
+		  )
+	`
+
+	rows, err := pg.db.Query(ctx, query, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timed games: %w", err)
+	}
+	defer rows.Close()
+
+	gameIDs := []int{}
+	for rows.Next() {
+		var gameID int
+		if err := rows.Scan(&gameID); err != nil {
+			return nil, fmt.Errorf("failed to scan timed game: %w", err)
+		}
+		gameIDs = append(gameIDs, gameID)
+	}
+
+	return gameIDs, nil
+}
+
+// Clear the dice roll at the end of a turn. Only called within a turn step,
+// whose state write has already raised the version for the change.
 func (pg *Postgres) ClearDice(ctx context.Context, gameID int) error {
 	query := `
 		UPDATE GAME_STATE
@@ -551,6 +1090,106 @@ func (pg *Postgres) ClearDice(ctx context.Context, gameID int) error {
 	return nil
 }
 
+// Lock the GAME and GAME_STATE rows for the rest of the transaction and return them
+func (pg *Postgres) lockGame(ctx context.Context, gameID int) (*Game, *GameState, error) {
+	var locked int
+	err := pg.db.QueryRow(ctx, `SELECT game_id FROM GAME WHERE game_id = $1 FOR UPDATE`, gameID).Scan(&locked)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil, fmt.Errorf("game not found")
+		}
+		return nil, nil, fmt.Errorf("failed to lock game: %w", err)
+	}
+
+	err = pg.db.QueryRow(ctx, `SELECT game_id FROM GAME_STATE WHERE game_id = $1 FOR UPDATE`, gameID).Scan(&locked)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil, fmt.Errorf("game state not found")
+		}
+		return nil, nil, fmt.Errorf("failed to lock game state: %w", err)
+	}
+
+	game, err := pg.GetGameByID(ctx, gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state, err := pg.GetGameState(ctx, gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return game, state, nil
+}
+
+// Apply one game action (a checker move or a cube action) atomically. The game
+// and state rows are locked, build validates the action against them and returns
+// the changes, and the state write, move records, turn switch and game completion
+// are committed together. Any error from build rolls the transaction back and is
+// returned unchanged.
+func (pg *Postgres) ApplyTurnStep(ctx context.Context, gameID int, build func(game *Game, state *GameState) (*TurnStep, error)) (*GameState, error) {
+	var updated *GameState
+	err := pg.withTx(ctx, func(tx *Postgres) error {
+		game, state, err := tx.lockGame(ctx, gameID)
+		if err != nil {
+			return err
+		}
+
+		step, err := build(game, state)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.UpdateGameState(ctx, step.State); err != nil {
+			return err
+		}
+
+		moveNumber, err := tx.GetLastMoveNumber(ctx, gameID)
+		if err != nil {
+			return err
+		}
+		for i := range step.Moves {
+			moveNumber++
+			step.Moves[i].GameID = gameID
+			step.Moves[i].MoveNumber = moveNumber
+			step.Moves[i].TurnNumber = step.State.TurnNumber
+			step.Moves[i].Dice = step.State.DiceRoll
+			if _, err := tx.CreateMove(ctx, &step.Moves[i]); err != nil {
+				return err
+			}
+		}
+
+		if step.ForfeitedBy != 0 {
+			if err := tx.ForfeitGame(ctx, gameID, step.ForfeitedBy, step.Result); err != nil {
+				return err
+			}
+		} else if step.WinnerID != 0 {
+			if err := tx.CompleteGame(ctx, gameID, step.WinnerID, step.Result); err != nil {
+				return err
+			}
+		} else if step.NextTurn != 0 {
+			if err := tx.UpdateGameTurn(ctx, gameID, step.NextTurn); err != nil {
+				return err
+			}
+			if err := tx.ClearDice(ctx, gameID); err != nil {
+				return err
+			}
+		} else if step.RollAgain {
+			if err := tx.ClearDice(ctx, gameID); err != nil {
+				return err
+			}
+		}
+
+		updated, err = tx.GetGameState(ctx, gameID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
 // ============================================================================
 // MOVE History Management
 // ============================================================================
@@ -560,12 +1199,21 @@ func (pg *Postgres) CreateMove(ctx context.Context, move *Move) (int, error) {
 	query := `
 		INSERT INTO MOVE (
 			game_id, player_id, move_number, from_point, to_point,
-			die_used, hit_opponent, timestamp
+			die_used, hit_opponent, turn_number, dice_roll, timestamp
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
 		RETURNING move_id
 	`
 
+	var diceJSON []byte
+	if move.Dice != nil {
+		var err error
+		diceJSON, err = json.Marshal(move.Dice)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal dice: %w", err)
+		}
+	}
+
 	var moveID int
 	err := pg.db.QueryRow(ctx, query,
 		move.GameID,
@@ -575,6 +1223,8 @@ func (pg *Postgres) CreateMove(ctx context.Context, move *Move) (int, error) {
 		move.ToPoint,
 		move.DieUsed,
 		move.HitOpponent,
+		move.TurnNumber,
+		diceJSON,
 	).Scan(&moveID)
 
 	if err != nil {
@@ -589,7 +1239,7 @@ func (pg *Postgres) GetMoveHistory(ctx context.Context, gameID int) ([]Move, err
 	query := `
 		SELECT
 			move_id, game_id, player_id, move_number, from_point,
-			to_point, die_used, hit_opponent, timestamp
+			to_point, die_used, hit_opponent, turn_number, dice_roll, timestamp
 		FROM MOVE
 		WHERE game_id = $1
 		ORDER BY move_number ASC, timestamp ASC
@@ -604,6 +1254,7 @@ func (pg *Postgres) GetMoveHistory(ctx context.Context, gameID int) ([]Move, err
 	moves := []Move{}
 	for rows.Next() {
 		var move Move
+		var diceJSON []byte
 		err := rows.Scan(
 			&move.MoveID,
 			&move.GameID,
@@ -613,11 +1264,18 @@ func (pg *Postgres) GetMoveHistory(ctx context.Context, gameID int) ([]Move, err
 			&move.ToPoint,
 			&move.DieUsed,
 			&move.HitOpponent,
+			&move.TurnNumber,
+			&diceJSON,
 			&move.Timestamp,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan move: %w", err)
 		}
+		if diceJSON != nil {
+			if err := json.Unmarshal(diceJSON, &move.Dice); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal dice: %w", err)
+			}
+		}
 		moves = append(moves, move)
 	}
 
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -9,7 +10,7 @@ import (
 )
 
 // CreateInvitation creates a new game invitation
-func (pg *Postgres) CreateInvitation(ctx context.Context, challengerID, challengedID int) (int, error) {
+func (pg *Postgres) CreateInvitation(ctx context.Context, challengerID, challengedID, matchLength int, options GameOptions) (int, error) {
 	// Check for existing pending invitation between these users
 	checkQuery := `
 		SELECT invitation_id FROM GAME_INVITATION
@@ -25,15 +26,29 @@ func (pg *Postgres) CreateInvitation(ctx context.Context, challengerID, challeng
 		return 0, fmt.Errorf("failed to check existing invitation: %w", err)
 	}
 
+	variant, startingBoardJSON, err := gameVariant(options)
+	if err != nil {
+		return 0, err
+	}
+	if options.RuleSet == "" {
+		options.RuleSet = "backgammon"
+	}
+
 	// Create new invitation
 	query := `
-		INSERT INTO GAME_INVITATION (challenger_id, challenged_id, status, created_at)
-		VALUES ($1, $2, 'pending', NOW())
+		INSERT INTO GAME_INVITATION (
+			challenger_id, challenged_id, status, match_length,
+			allow_beavers, allow_hints, time_delay_seconds, time_reserve_seconds, dice_mode,
+			variant, starting_board, ruleset, created_at
+		)
+		VALUES ($1, $2, 'pending', $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
 		RETURNING invitation_id
 	`
 
 	var invitationID int
-	err = pg.db.QueryRow(ctx, query, challengerID, challengedID).Scan(&invitationID)
+	err = pg.db.QueryRow(ctx, query, challengerID, challengedID, matchLength,
+		options.AllowBeavers, options.AllowHints, options.DelaySeconds, options.ReserveSeconds, options.DiceMode,
+		variant, startingBoardJSON, options.RuleSet).Scan(&invitationID)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create invitation: %w", err)
 	}
@@ -53,7 +68,15 @@ func (pg *Postgres) GetInvitationsByUser(ctx context.Context, userID int) (sent
 			u2.username as challenged_username,
 			gi.status,
 			gi.game_id,
-			gi.created_at
+			gi.match_length,
+			gi.created_at,
+			gi.allow_beavers,
+			gi.allow_hints,
+			gi.time_delay_seconds,
+			gi.time_reserve_seconds,
+			gi.dice_mode,
+			gi.variant,
+			gi.ruleset
 		FROM GAME_INVITATION gi
 		JOIN "USER" u1 ON gi.challenger_id = u1.user_id
 		JOIN "USER" u2 ON gi.challenged_id = u2.user_id
@@ -78,7 +101,15 @@ func (pg *Postgres) GetInvitationsByUser(ctx context.Context, userID int) (sent
 			&inv.ChallengedUsername,
 			&inv.Status,
 			&inv.GameID,
+			&inv.MatchLength,
 			&inv.CreatedAt,
+			&inv.AllowBeavers,
+			&inv.AllowHints,
+			&inv.DelaySeconds,
+			&inv.ReserveSeconds,
+			&inv.DiceMode,
+			&inv.Variant,
+			&inv.RuleSet,
 		)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to scan sent invitation: %w", err)
@@ -96,7 +127,15 @@ func (pg *Postgres) GetInvitationsByUser(ctx context.Context, userID int) (sent
 			u2.username as challenged_username,
 			gi.status,
 			gi.game_id,
-			gi.created_at
+			gi.match_length,
+			gi.created_at,
+			gi.allow_beavers,
+			gi.allow_hints,
+			gi.time_delay_seconds,
+			gi.time_reserve_seconds,
+			gi.dice_mode,
+			gi.variant,
+			gi.ruleset
 		FROM GAME_INVITATION gi
 		JOIN "USER" u1 ON gi.challenger_id = u1.user_id
 		JOIN "USER" u2 ON gi.challenged_id = u2.user_id
@@ -121,7 +160,15 @@ func (pg *Postgres) GetInvitationsByUser(ctx context.Context, userID int) (sent
 			&inv.ChallengedUsername,
 			&inv.Status,
 			&inv.GameID,
+			&inv.MatchLength,
 			&inv.CreatedAt,
+			&inv.AllowBeavers,
+			&inv.AllowHints,
+			&inv.DelaySeconds,
+			&inv.ReserveSeconds,
+			&inv.DiceMode,
+			&inv.Variant,
+			&inv.RuleSet,
 		)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to scan received invitation: %w", err)
@@ -143,7 +190,16 @@ func (pg *Postgres) GetInvitationByID(ctx context.Context, invitationID int) (*I
 			u2.username as challenged_username,
 			gi.status,
 			gi.game_id,
-			gi.created_at
+			gi.match_length,
+			gi.created_at,
+			gi.allow_beavers,
+			gi.allow_hints,
+			gi.time_delay_seconds,
+			gi.time_reserve_seconds,
+			gi.dice_mode,
+			gi.variant,
+			gi.starting_board,
+			gi.ruleset
 		FROM GAME_INVITATION gi
 		JOIN "USER" u1 ON gi.challenger_id = u1.user_id
 		JOIN "USER" u2 ON gi.challenged_id = u2.user_id
@@ -151,6 +207,7 @@ func (pg *Postgres) GetInvitationByID(ctx context.Context, invitationID int) (*I
 	`
 
 	var inv InvitationWithUsers
+	var startingBoardJSON []byte
 	err := pg.db.QueryRow(ctx, query, invitationID).Scan(
 		&inv.InvitationID,
 		&inv.ChallengerID,
@@ -159,7 +216,16 @@ func (pg *Postgres) GetInvitationByID(ctx context.Context, invitationID int) (*I
 		&inv.ChallengedUsername,
 		&inv.Status,
 		&inv.GameID,
+		&inv.MatchLength,
 		&inv.CreatedAt,
+		&inv.AllowBeavers,
+		&inv.AllowHints,
+		&inv.DelaySeconds,
+		&inv.ReserveSeconds,
+		&inv.DiceMode,
+		&inv.Variant,
+		&startingBoardJSON,
+		&inv.RuleSet,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -168,6 +234,10 @@ func (pg *Postgres) GetInvitationByID(ctx context.Context, invitationID int) (*I
 		return nil, fmt.Errorf("failed to get invitation: %w", err)
 	}
 
+	if err := json.Unmarshal(startingBoardJSON, &inv.StartingBoard); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal starting board: %w", err)
+	}
+
 	return &inv, nil
 }
 
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Create a match to matchLength points and its first game
+func (pg *Postgres) CreateMatch(ctx context.Context, player1ID, player2ID, matchLength int, options GameOptions) (matchID, gameID int, err error) {
+	if matchLength < 1 {
+		return 0, 0, fmt.Errorf("match length must be at least 1")
+	}
+
+	err = pg.withTx(ctx, func(tx *Postgres) error {
+		query := `
+			INSERT INTO MATCH (player1_id, player2_id, match_length, match_status, created_at)
+			VALUES ($1, $2, $3, 'in_progress', NOW())
+			RETURNING match_id
+		`
+
+		if err := tx.db.QueryRow(ctx, query, player1ID, player2ID, matchLength).Scan(&matchID); err != nil {
+			return fmt.Errorf("failed to create match: %w", err)
+		}
+
+		gameID, err = tx.createGame(ctx, player1ID, player2ID, options, &matchID, 1, false)
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return matchID, gameID, nil
+}
+
+// Retrieve a match by its ID
+func (pg *Postgres) GetMatchByID(ctx context.Context, matchID int) (*Match, error) {
+	query := `
+		SELECT
+			match_id,
+			player1_id,
+			player2_id,
+			match_length,
+			player1_score,
+			player2_score,
+			crawford,
+			post_crawford,
+			match_status,
+			winner_id,
+			created_at,
+			ended_at
+		FROM MATCH
+		WHERE match_id = $1
+	`
+
+	var match Match
+	err := pg.db.QueryRow(ctx, query, matchID).Scan(
+		&match.MatchID,
+		&match.Player1ID,
+		&match.Player2ID,
+		&match.MatchLength,
+		&match.Player1Score,
+		&match.Player2Score,
+		&match.Crawford,
+		&match.PostCrawford,
+		&match.MatchStatus,
+		&match.WinnerID,
+		&match.CreatedAt,
+		&match.EndedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("match not found")
+		}
+		return nil, fmt.Errorf("failed to get match: %w", err)
+	}
+
+	return &match, nil
+}
+
+// Retrieve the games of a match in the order they were played
+func (pg *Postgres) GetMatchGames(ctx context.Context, matchID int) ([]Game, error) {
+	query := `
+		SELECT game_id
+		FROM GAME
+		WHERE match_id = $1
+		ORDER BY game_number
+	`
+
+	rows, err := pg.db.Query(ctx, query, matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match games: %w", err)
+	}
+
+	gameIDs := []int{}
+	for rows.Next() {
+		var gameID int
+		if err := rows.Scan(&gameID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan match game: %w", err)
+		}
+		gameIDs = append(gameIDs, gameID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get match games: %w", err)
+	}
+
+	games := []Game{}
+	for _, gameID := range gameIDs {
+		game, err := pg.GetGameByID(ctx, gameID)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, *game)
+	}
+
+	return games, nil
+}
+
+// Update a match's score after one of its games has finished, and create the
+// next game unless the match is over. score receives the locked match, its
+// games and the next game's options, copied from the last game; it updates
+// the match and options in place and reports whether another game should be
+// played. Returns the ID of the new game, or 0 when none was created.
+func (pg *Postgres) AdvanceMatch(ctx context.Context, matchID int, score func(match *Match, games []Game, next *GameOptions) (bool, error)) (int, error) {
+	var nextGameID int
+	err := pg.withTx(ctx, func(tx *Postgres) error {
+		var locked int
+		err := tx.db.QueryRow(ctx, `SELECT match_id FROM MATCH WHERE match_id = $1 FOR UPDATE`, matchID).Scan(&locked)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return fmt.Errorf("match not found")
+			}
+			return fmt.Errorf("failed to lock match: %w", err)
+		}
+
+		match, err := tx.GetMatchByID(ctx, matchID)
+		if err != nil {
+			return err
+		}
+
+		// A finished match has nothing left to advance
+		if match.MatchStatus != "in_progress" {
+			return nil
+		}
+
+		games, err := tx.GetMatchGames(ctx, matchID)
+		if err != nil {
+			return err
+		}
+		if len(games) == 0 {
+			return fmt.Errorf("match has no games")
+		}
+
+		// The next game keeps the options of the last one unless score changes them
+		last := games[len(games)-1]
+		next := last.GameOptions
+		startNext, err := score(match, games, &next)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.updateMatch(ctx, match); err != nil {
+			return err
+		}
+
+		if !startNext {
+			return nil
+		}
+
+		nextGameID, err = tx.createGame(ctx, match.Player1ID, match.Player2ID, next, &matchID, last.GameNumber+1, match.Crawford)
+		if err != nil {
+			return err
+		}
+		// The new game waits for its opening roll like the first one
+		return tx.InitializeGameState(ctx, nextGameID)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return nextGameID, nil
+}
+
+// Return the last game of every match still in progress whose last game has
+// finished, i.e. matches left without a next game or a result
+func (pg *Postgres) GetStalledMatchGames(ctx context.Context) ([]int, error) {
+	query := `
+		SELECT g.game_id
+		FROM MATCH m
+		JOIN LATERAL (
+			SELECT game_id, game_status
+			FROM GAME
+			WHERE match_id = m.match_id
+			ORDER BY game_number DESC
+			LIMIT 1
+		) g ON TRUE
+		WHERE m.match_status = 'in_progress'
+		  AND g.game_status IN ('completed', 'abandoned')
+	`
+
+	rows, err := pg.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stalled matches: %w", err)
+	}
+	defer rows.Close()
+
+	gameIDs := []int{}
+	for rows.Next() {
+		var gameID int
+		if err := rows.Scan(&gameID); err != nil {
+			return nil, fmt.Errorf("failed to scan stalled match game: %w", err)
+		}
+		gameIDs = append(gameIDs, gameID)
+	}
+
+	return gameIDs, nil
+}
+
+// Store a match's score, Crawford flags and outcome
+func (pg *Postgres) updateMatch(ctx context.Context, match *Match) error {
+	query := `
+		UPDATE MATCH
+		SET player1_score = $2,
+		    player2_score = $3,
+		    crawford = $4,
+		    post_crawford = $5,
+		    match_status = $6,
+		    winner_id = $7,
+		    ended_at = CASE WHEN $6 = 'in_progress' THEN NULL ELSE COALESCE(ended_at, NOW()) END
+		WHERE match_id = $1
+	`
+
+	_, err := pg.db.Exec(ctx, query,
+		match.MatchID,
+		match.Player1Score,
+		match.Player2Score,
+		match.Crawford,
+		match.PostCrawford,
+		match.MatchStatus,
+		match.WinnerID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update match: %w", err)
+	}
+
+	return nil
+}
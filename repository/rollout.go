@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Columns read for a rollout, in the order scanRollout expects
+const rolloutColumns = `
+	rollout_id, user_id, game_id, board_state, bar_white, bar_black,
+	borne_off_white, borne_off_black, on_roll, dice_roll, dice_used,
+	trials, truncation, bot_level, variance_reduction, seed, candidates,
+	status, trials_done, result, error_message, created_at, started_at, completed_at
+`
+
+// Scan a rollout row selected with rolloutColumns
+func scanRollout(row pgx.Row) (*Rollout, error) {
+	var rollout Rollout
+	var boardJSON, diceRollJSON, diceUsedJSON, resultJSON []byte
+	err := row.Scan(
+		&rollout.RolloutID,
+		&rollout.UserID,
+		&rollout.GameID,
+		&boardJSON,
+		&rollout.BarWhite,
+		&rollout.BarBlack,
+		&rollout.BornedOffWhite,
+		&rollout.BornedOffBlack,
+		&rollout.OnRoll,
+		&diceRollJSON,
+		&diceUsedJSON,
+		&rollout.Trials,
+		&rollout.Truncation,
+		&rollout.BotLevel,
+		&rollout.VarianceReduction,
+		&rollout.Seed,
+		&rollout.Candidates,
+		&rollout.Status,
+		&rollout.TrialsDone,
+		&resultJSON,
+		&rollout.ErrorMessage,
+		&rollout.CreatedAt,
+		&rollout.StartedAt,
+		&rollout.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(boardJSON, &rollout.BoardState); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal board state: %w", err)
+	}
+	if diceRollJSON != nil {
+		if err := json.Unmarshal(diceRollJSON, &rollout.DiceRoll); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal dice roll: %w", err)
+		}
+	}
+	if diceUsedJSON != nil {
+		if err := json.Unmarshal(diceUsedJSON, &rollout.DiceUsed); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal dice used: %w", err)
+		}
+	}
+	rollout.Result = resultJSON
+
+	return &rollout, nil
+}
+
+// Queue a rollout and return its ID. check is given the number of rollouts
+// the user already has queued or running, counted while the user is locked so
+// concurrent requests cannot both pass a limit; an error from it is returned
+// unchanged.
+func (pg *Postgres) CreateRollout(ctx context.Context, rollout *Rollout, check func(active int) error) (int, error) {
+	boardJSON, err := json.Marshal(rollout.BoardState)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal board state: %w", err)
+	}
+
+	var diceRollJSON []byte
+	var diceUsedJSON []byte
+
+	if rollout.DiceRoll != nil {
+		diceRollJSON, err = json.Marshal(rollout.DiceRoll)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal dice roll: %w", err)
+		}
+	}
+
+	if rollout.DiceUsed != nil {
+		diceUsedJSON, err = json.Marshal(rollout.DiceUsed)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal dice used: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO ROLLOUT (
+			user_id, game_id, board_state, bar_white, bar_black, borne_off_white, borne_off_black,
+			on_roll, dice_roll, dice_used, trials, truncation, bot_level, variance_reduction,
+			seed, candidates, status, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, 'queued', NOW())
+		RETURNING rollout_id
+	`
+
+	var rolloutID int
+	err = pg.withTx(ctx, func(tx *Postgres) error {
+		if _, err := tx.db.Exec(ctx, `SELECT 1 FROM "USER" WHERE user_id = $1 FOR UPDATE`, rollout.UserID); err != nil {
+			return fmt.Errorf("failed to lock user: %w", err)
+		}
+
+		active, err := tx.countActiveRollouts(ctx, rollout.UserID)
+		if err != nil {
+			return err
+		}
+		if err := check(active); err != nil {
+			return err
+		}
+
+		err = tx.db.QueryRow(ctx, query,
+			rollout.UserID,
+			rollout.GameID,
+			boardJSON,
+			rollout.BarWhite,
+			rollout.BarBlack,
+			rollout.BornedOffWhite,
+			rollout.BornedOffBlack,
+			rollout.OnRoll,
+			diceRollJSON,
+			diceUsedJSON,
+			rollout.Trials,
+			rollout.Truncation,
+			rollout.BotLevel,
+			rollout.VarianceReduction,
+			rollout.Seed,
+			rollout.Candidates,
+		).Scan(&rolloutID)
+		if err != nil {
+			return fmt.Errorf("failed to create rollout: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return rolloutID, nil
+}
+
+// Retrieve a rollout by ID
+func (pg *Postgres) GetRollout(ctx context.Context, rolloutID int) (*Rollout, error) {
+	query := `SELECT ` + rolloutColumns + ` FROM ROLLOUT WHERE rollout_id = $1`
+
+	rollout, err := scanRollout(pg.db.QueryRow(ctx, query, rolloutID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("rollout not found")
+		}
+		return nil, fmt.Errorf("failed to get rollout: %w", err)
+	}
+
+	return rollout, nil
+}
+
+// Count a user's rollouts that are queued or running
+func (pg *Postgres) countActiveRollouts(ctx context.Context, userID int) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM ROLLOUT
+		WHERE user_id = $1 AND status IN ('queued', 'running')
+	`
+
+	var count int
+	if err := pg.db.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count active rollouts: %w", err)
+	}
+
+	return count, nil
+}
+
+// Mark the next queued rollout as running and return it. Returns nil when
+// the queue is empty. Users take turns: the oldest rollout of the user whose
+// last rollout started longest ago goes first, so one user's queue cannot
+// hold up everyone else's.
+func (pg *Postgres) ClaimNextRollout(ctx context.Context) (*Rollout, error) {
+	query := `
+		UPDATE ROLLOUT
+		SET status = 'running', started_at = NOW()
+		WHERE rollout_id = (
+			SELECT r.rollout_id FROM ROLLOUT r
+			WHERE r.status = 'queued'
+			ORDER BY (
+				SELECT MAX(started.started_at) FROM ROLLOUT started
+				WHERE started.user_id = r.user_id
+			) NULLS FIRST, r.created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + rolloutColumns
+
+	rollout, err := scanRollout(pg.db.QueryRow(ctx, query))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim rollout: %w", err)
+	}
+
+	return rollout, nil
+}
+
+// Record how many trials a running rollout has finished
+func (pg *Postgres) UpdateRolloutProgress(ctx context.Context, rolloutID, trialsDone int) error {
+	query := `
+		UPDATE ROLLOUT
+		SET trials_done = $2
+		WHERE rollout_id = $1 AND status = 'running'
+	`
+
+	_, err := pg.db.Exec(ctx, query, rolloutID, trialsDone)
+	if err != nil {
+		return fmt.Errorf("failed to update rollout progress: %w", err)
+	}
+
+	return nil
+}
+
+// Store a finished rollout's result
+func (pg *Postgres) CompleteRollout(ctx context.Context, rolloutID, trialsDone int, result []byte) error {
+	query := `
+		UPDATE ROLLOUT
+		SET status = 'completed', trials_done = $2, result = $3, completed_at = NOW()
+		WHERE rollout_id = $1
+	`
+
+	_, err := pg.db.Exec(ctx, query, rolloutID, trialsDone, result)
+	if err != nil {
+		return fmt.Errorf("failed to complete rollout: %w", err)
+	}
+
+	return nil
+}
+
+// Mark a rollout as failed with the reason
+func (pg *Postgres) FailRollout(ctx context.Context, rolloutID int, message string) error {
+	query := `
+		UPDATE ROLLOUT
+		SET status = 'failed', error_message = $2, completed_at = NOW()
+		WHERE rollout_id = $1
+	`
+
+	_, err := pg.db.Exec(ctx, query, rolloutID, message)
+	if err != nil {
+		return fmt.Errorf("failed to mark rollout as failed: %w", err)
+	}
+
+	return nil
+}
+
+// Put rollouts left running by a previous server back in the queue
+func (pg *Postgres) RequeueRunningRollouts(ctx context.Context) (int64, error) {
+	query := `
+		UPDATE ROLLOUT
+		SET status = 'queued', trials_done = 0, started_at = NULL
+		WHERE status = 'running'
+	`
+
+	result, err := pg.db.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue rollouts: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
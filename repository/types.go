@@ -1,6 +1,9 @@
 package repository
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // ============================================================================
 // User Types
@@ -10,6 +13,7 @@ type User struct {
 	UserID       int
 	Username     string
 	PasswordHash string
+	BotLevel     *string // Difficulty of a computer player, nil for people
 }
 
 // ============================================================================
@@ -52,11 +56,38 @@ type Game struct {
 	CurrentTurn  int
 	GameStatus   string
 	WinnerID     *int
+	ResultType   *string // "single", "gammon" or "backgammon" once the game has ended
+	PointsWon    *int    // Result multiplier times the cube value
 	CreatedAt    time.Time
 	StartedAt    *time.Time
 	EndedAt      *time.Time
 	Player1Color string
 	Player2Color string
+	MatchID      *int // Match this game belongs to, nil for a single game
+	GameNumber   int  // Position of the game within its match
+	Crawford     bool // This is the Crawford game of its match (no doubling)
+	// SHA-256 of a fair game's dice seed in hex, published from the start
+	DiceCommitment *string
+	// Checkers on the bar and borne off when the game began, set for games
+	// created from a position
+	StartingBarWhite       int
+	StartingBarBlack       int
+	StartingBornedOffWhite int
+	StartingBornedOffBlack int
+	GameOptions
+}
+
+// GameOptions holds the rule options chosen when a game is created
+type GameOptions struct {
+	AllowBeavers   bool   // Beavers and raccoons are allowed on doubles
+	AllowHints     bool   // Players may ask for hints during the game
+	DelaySeconds   int    // Time each turn may use before the reserve runs down
+	ReserveSeconds int    // Time bank per player for the whole game (0 with no delay is untimed)
+	DiceMode       string // DiceModeRandom, DiceModeSeeded or DiceModeFair (default random)
+	DiceSeed       []byte // Seed of a seeded or fair game's dice; a fair game's is kept secret until it ends
+	Variant        string // Starting layout the game is played from (default standard)
+	StartingBoard  []int  // The variant's starting board, 24 integers like GameState.BoardState
+	RuleSet        string // Rules the game is played under (default backgammon); tavli matches rotate them
 }
 
 type GameWithPlayers struct {
@@ -70,11 +101,23 @@ type GameWithPlayers struct {
 	CurrentTurn     int
 	GameStatus      string
 	WinnerID        *int
+	ResultType      *string
+	PointsWon       *int
 	CreatedAt       time.Time
 	StartedAt       *time.Time
 	EndedAt         *time.Time
+	MatchID         *int
+	GameNumber      int
+	Crawford        bool
+	GameOptions
 }
 
+// Stages of the Acey-Deucey bonus a fully played 1-2 earns
+const (
+	BonusChoose  = "choose"  // The player is to choose the doubles to play
+	BonusDoubles = "doubles" // The chosen doubles are being played; another roll follows
+)
+
 type GameState struct {
 	StateID        int
 	GameID         int
@@ -83,11 +126,36 @@ type GameState struct {
 	BarBlack       int
 	BornedOffWhite int
 	BornedOffBlack int
+	HandWhite      int // Acey-Deucey checkers not yet entered
+	HandBlack      int
+	Pinned         []int  // Plakoto checkers pinned under the opponent's, per point like BoardState, or nil
 	DiceRoll       []int  // [die1, die2] or nil
 	DiceUsed       []bool // [used1, used2] or nil
+	TurnNumber     int    // Number of turns whose dice have been rolled
+	DiceCount      int    // Number of dice rolled in the game, opening dice included
+	BonusStage     string // Acey-Deucey bonus after a 1-2: BonusChoose, BonusDoubles, or "" for none
+	CubeValue      int
+	CubeOwner      *int       // Player who owns the cube, nil when centered
+	CubeOfferedBy  *int       // Player whose double awaits a response, nil when none
+	CubeBeavered   bool       // A beaver was just made; the original doubler may raccoon
+	Version        int        // Incremented on every write, for optimistic concurrency
+	OpeningDie1    *int       // Player 1's opening roll die, nil until rolled (cleared on a tie)
+	OpeningDie2    *int       // Player 2's opening roll die
+	ClockPlayer1   *int64     // Player 1's reserve in milliseconds when the running clock started, nil when untimed
+	ClockPlayer2   *int64     // Player 2's reserve in milliseconds
+	ClockRunning   *int       // Player whose clock is running, nil when stopped
+	ClockStartedAt *time.Time // When the running clock started
 	LastUpdated    time.Time
 }
 
+// OpeningRoll is the outcome of one player's opening roll die
+type OpeningRoll struct {
+	Die            int   // The die just rolled
+	Tie            bool  // Both dice matched and were cleared for a re-roll
+	StartingPlayer int   // Player who won the opening roll, or 0 while undecided
+	Dice           []int // The first turn's dice once decided (winner's die first)
+}
+
 type Move struct {
 	MoveID      int
 	GameID      int
@@ -97,9 +165,71 @@ type Move struct {
 	ToPoint     int
 	DieUsed     int
 	HitOpponent bool
+	TurnNumber  int   // Turn the move was played in
+	Dice        []int // The whole roll of that turn, nil for moves recorded before rolls were kept
 	Timestamp   time.Time
 }
 
+// DiceRoll is one logged roll: a turn's two dice or a single opening die
+type DiceRoll struct {
+	RollID             int
+	GameID             int
+	PlayerID           int
+	Kind               string // "opening" or "turn"
+	Die1               int
+	Die2               *int  // Nil for an opening die
+	DiceIndex          *int  // Number of the first die among the game's dice, nil when recovered from history
+	BarCheckers        *int  // Roller's checkers on the bar, nil when not known
+	BlockedEntryPoints *int  // Entry points the opponent held, nil when not known
+	Entered            *bool // A die let a checker in from the bar, nil when none was on it
+	RolledAt           time.Time
+}
+
+// DiceRollCounts sums up logged rolls for fairness statistics
+type DiceRollCounts struct {
+	TurnRolls   [6][6]int // Turn rolls by the lower and higher die, less one
+	OpeningDice [6]int    // Opening dice by face, less one
+	BarRolls    int       // Turn rolls made with checkers on the bar
+	BarEntered  int       // Bar rolls that let a checker in
+	BarExpected float64   // Bar rolls expected to let a checker in, given the points held
+}
+
+// TurnStep holds the changes produced by one validated game action
+type TurnStep struct {
+	State       *GameState // Updated state to persist
+	Moves       []Move     // Move records to append (game and move numbers are assigned on insert)
+	NextTurn    int        // Player to hand the turn to (dice are cleared), or 0 to keep the turn
+	RollAgain   bool       // Keep the turn but clear the dice for another roll
+	WinnerID    int        // Winner if the move ended the game, or 0
+	Result      GameResult // How the game was won, when WinnerID is set
+	ForfeitedBy int        // Player who forfeited, leaving the game abandoned rather than completed, or 0
+}
+
+// GameResult records how decisively a finished game was won
+type GameResult struct {
+	ResultType string // "single", "gammon" or "backgammon"
+	PointsWon  int
+}
+
+// ============================================================================
+// Match Types
+// ============================================================================
+
+type Match struct {
+	MatchID      int
+	Player1ID    int
+	Player2ID    int
+	MatchLength  int // Points needed to win the match
+	Player1Score int
+	Player2Score int
+	Crawford     bool // The current game is the Crawford game
+	PostCrawford bool // The Crawford game has been played
+	MatchStatus  string
+	WinnerID     *int
+	CreatedAt    time.Time
+	EndedAt      *time.Time
+}
+
 // ============================================================================
 // Invitation Types
 // ============================================================================
@@ -110,7 +240,9 @@ type Invitation struct {
 	ChallengedID int
 	Status       string
 	GameID       *int
+	MatchLength  int // Points to play to, or 0 for a single game
 	CreatedAt    time.Time
+	GameOptions
 	// Extended fields for joined queries
 	ChallengerUsername string
 	ChallengedUsername string
@@ -124,7 +256,9 @@ type InvitationWithUsers struct {
 	ChallengedUsername string
 	Status             string
 	GameID             *int
+	MatchLength        int
 	CreatedAt          time.Time
+	GameOptions
 }
 
 // ============================================================================
@@ -137,3 +271,60 @@ type LobbyUser struct {
 	JoinedAt      time.Time
 	LastHeartbeat time.Time
 }
+
+// ============================================================================
+// Bot Types
+// ============================================================================
+
+// BotUser is a computer player's account
+type BotUser struct {
+	UserID   int
+	Username string
+	Level    string
+}
+
+// ============================================================================
+// Analysis Types
+// ============================================================================
+
+// GameAnalysis is the stored review of a finished game
+type GameAnalysis struct {
+	GameID           int
+	Evaluator        string   // Evaluator the game was judged with
+	Player1ErrorRate *float64 // nil when the player made no decisions
+	Player2ErrorRate *float64
+	Analysis         json.RawMessage // Turn-by-turn review
+	AnalyzedAt       time.Time
+}
+
+// ============================================================================
+// Rollout Types
+// ============================================================================
+
+// Rollout is a queued or finished Monte Carlo rollout of a position
+type Rollout struct {
+	RolloutID         int
+	UserID            int
+	GameID            *int  // Game the position was taken from, nil for a pasted position
+	BoardState        []int // 24 integers: positive=white, negative=black, 0=empty
+	BarWhite          int
+	BarBlack          int
+	BornedOffWhite    int
+	BornedOffBlack    int
+	OnRoll            string // Color of the player on roll
+	DiceRoll          []int  // Roll whose candidate plays are rolled out, nil to roll out the position before the roll
+	DiceUsed          []bool
+	Trials            int
+	Truncation        int
+	BotLevel          string
+	VarianceReduction bool
+	Seed              int64
+	Candidates        int    // Number of candidate plays rolled out when dice are given
+	Status            string // queued, running, completed or failed
+	TrialsDone        int
+	Result            json.RawMessage // nil until completed
+	ErrorMessage      *string
+	CreatedAt         time.Time
+	StartedAt         *time.Time
+	CompletedAt       *time.Time
+}
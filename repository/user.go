@@ -25,7 +25,7 @@ func (pg *Postgres) CreateUser(ctx context.Context, username, passwordHash strin
 // GetUserByUsername retrieves a user by username
 func (pg *Postgres) GetUserByUsername(ctx context.Context, username string) (*User, error) {
 	query := `
-		SELECT user_id, username, password_hash
+		SELECT user_id, username, password_hash, bot_level
 		FROM "USER"
 		WHERE username = $1
 	`
@@ -35,6 +35,7 @@ func (pg *Postgres) GetUserByUsername(ctx context.Context, username string) (*Us
 		&user.UserID,
 		&user.Username,
 		&user.PasswordHash,
+		&user.BotLevel,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("user not found: %w", err)
@@ -46,7 +47,7 @@ func (pg *Postgres) GetUserByUsername(ctx context.Context, username string) (*Us
 // GetUserByID retrieves a user by ID
 func (pg *Postgres) GetUserByID(ctx context.Context, userID int) (*User, error) {
 	query := `
-		SELECT user_id, username, password_hash
+		SELECT user_id, username, password_hash, bot_level
 		FROM "USER"
 		WHERE user_id = $1
 	`
@@ -56,6 +57,7 @@ func (pg *Postgres) GetUserByID(ctx context.Context, userID int) (*User, error)
 		&user.UserID,
 		&user.Username,
 		&user.PasswordHash,
+		&user.BotLevel,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("user not found: %w", err)
@@ -4,11 +4,16 @@
 -- Drop tables in reverse order of dependencies
 DROP TABLE IF EXISTS CHAT_MESSAGE CASCADE;
 DROP TABLE IF EXISTS CHAT_ROOM CASCADE;
+DROP TABLE IF EXISTS ROLLOUT CASCADE;
+DROP TABLE IF EXISTS GAME_ANALYSIS CASCADE;
+DROP TABLE IF EXISTS DATA_BACKFILL CASCADE;
+DROP TABLE IF EXISTS DICE_ROLL CASCADE;
 DROP TABLE IF EXISTS MOVE CASCADE;
 DROP TABLE IF EXISTS GAME_STATE CASCADE;
 DROP TABLE IF EXISTS GAME_INVITATION CASCADE;
 DROP TABLE IF EXISTS LOBBY_PRESENCE CASCADE;
 DROP TABLE IF EXISTS GAME CASCADE;
+DROP TABLE IF EXISTS MATCH CASCADE;
 DROP TABLE IF EXISTS SESSIONS CASCADE;
 DROP TABLE IF EXISTS REGISTRATION_TOKEN CASCADE;
 DROP TABLE IF EXISTS "USER" CASCADE;
@@ -16,11 +21,16 @@ DROP TABLE IF EXISTS "USER" CASCADE;
 -- ============================================================================
 -- USER table
 -- Store player account information and credentials
+-- Computer players are users with a bot level and a password hash that never
+-- matches, so they cannot log in
 -- ============================================================================
+CREATE TYPE bot_level_enum AS ENUM ('easy', 'medium', 'hard');
+
 CREATE TABLE "USER" (
     user_id SERIAL PRIMARY KEY,
     username VARCHAR(255) NOT NULL UNIQUE,
     password_hash TEXT NOT NULL,
+    bot_level bot_level_enum NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     last_login TIMESTAMP NULL,
     -- Constraints
@@ -65,12 +75,57 @@ CREATE INDEX idx_session_user_id ON SESSIONS(user_id);
 CREATE INDEX idx_session_expires_at ON SESSIONS(expires_at);
 CREATE INDEX idx_session_is_active ON SESSIONS(is_active);
 
+-- ============================================================================
+-- MATCH table
+-- Group consecutive games played to a target number of points
+-- ============================================================================
+CREATE TYPE match_status_enum AS ENUM ('in_progress', 'completed', 'abandoned');
+
+CREATE TABLE MATCH (
+    match_id SERIAL PRIMARY KEY,
+    player1_id INT NOT NULL,
+    player2_id INT NOT NULL,
+    match_length INT NOT NULL,
+    player1_score INT NOT NULL DEFAULT 0,
+    player2_score INT NOT NULL DEFAULT 0,
+    crawford BOOLEAN NOT NULL DEFAULT FALSE,
+    post_crawford BOOLEAN NOT NULL DEFAULT FALSE,
+    match_status match_status_enum NOT NULL DEFAULT 'in_progress',
+    winner_id INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    ended_at TIMESTAMP NULL,
+    -- Foreign keys
+    CONSTRAINT fk_match_player1 FOREIGN KEY (player1_id) REFERENCES "USER" (user_id) ON DELETE CASCADE,
+    CONSTRAINT fk_match_player2 FOREIGN KEY (player2_id) REFERENCES "USER" (user_id) ON DELETE CASCADE,
+    CONSTRAINT fk_match_winner FOREIGN KEY (winner_id) REFERENCES "USER" (user_id) ON DELETE SET NULL,
+    -- Constraints
+    CONSTRAINT chk_match_different_players CHECK (player1_id != player2_id),
+    CONSTRAINT chk_match_length_positive CHECK (match_length > 0)
+);
+
+CREATE INDEX idx_match_player1_id ON MATCH(player1_id);
+CREATE INDEX idx_match_player2_id ON MATCH(player2_id);
+
 -- ============================================================================
 -- GAME table
 -- Represent backgammon matches between two players
+-- Fair games draw their dice from a secret seed whose SHA-256 is published
+-- as dice_commitment when the game is created and revealed when it ends
+-- starting_board holds the variant's starting layout as 24 integers, like
+-- GAME_STATE.board_state, so custom layouts need no table of their own
+-- Games set up from a position (practice games from an XGID) may also start
+-- with checkers on the bar or borne off, kept in the starting_* counts
+-- ruleset picks the rules: backgammon, or one of the Tavli games, which start
+-- from their own layout and so only take the standard variant. A tavli match
+-- plays Portes, Plakoto and Fevga in turn, so it needs a match. Acey-Deucey
+-- starts from an empty board with every checker in hand.
 -- ============================================================================
 CREATE TYPE game_status_enum AS ENUM ('pending', 'in_progress', 'completed', 'abandoned');
 CREATE TYPE color_enum AS ENUM ('white', 'black');
+CREATE TYPE result_type_enum AS ENUM ('single', 'gammon', 'backgammon');
+CREATE TYPE dice_mode_enum AS ENUM ('random', 'seeded', 'fair');
+CREATE TYPE variant_enum AS ENUM ('standard', 'nackgammon', 'hypergammon', 'longgammon', 'custom');
+CREATE TYPE ruleset_enum AS ENUM ('backgammon', 'portes', 'plakoto', 'fevga', 'tavli', 'aceydeucey');
 
 CREATE TABLE GAME (
     game_id SERIAL PRIMARY KEY,
@@ -79,12 +134,32 @@ CREATE TABLE GAME (
     current_turn INT NOT NULL,
     game_status game_status_enum NOT NULL DEFAULT 'pending',
     winner_id INT NULL,
+    result_type result_type_enum NULL,
+    points_won INT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     started_at TIMESTAMP NULL,
     ended_at TIMESTAMP NULL,
     player1_color color_enum NOT NULL,
     player2_color color_enum NOT NULL,
+    allow_beavers BOOLEAN NOT NULL DEFAULT FALSE,
+    allow_hints BOOLEAN NOT NULL DEFAULT TRUE,
+    time_delay_seconds INT NOT NULL DEFAULT 0,
+    time_reserve_seconds INT NOT NULL DEFAULT 0,
+    match_id INT NULL,
+    game_number INT NOT NULL DEFAULT 1,
+    crawford BOOLEAN NOT NULL DEFAULT FALSE,
+    dice_mode dice_mode_enum NOT NULL DEFAULT 'random',
+    dice_seed BYTEA NULL,
+    dice_commitment CHAR(64) NULL,
+    variant variant_enum NOT NULL DEFAULT 'standard',
+    starting_board JSONB NOT NULL,
+    starting_bar_white INT NOT NULL DEFAULT 0,
+    starting_bar_black INT NOT NULL DEFAULT 0,
+    starting_borne_off_white INT NOT NULL DEFAULT 0,
+    starting_borne_off_black INT NOT NULL DEFAULT 0,
+    ruleset ruleset_enum NOT NULL DEFAULT 'backgammon',
     -- Foreign keys
+    CONSTRAINT fk_game_match FOREIGN KEY (match_id) REFERENCES MATCH (match_id) ON DELETE CASCADE,
     CONSTRAINT fk_game_player1 FOREIGN KEY (player1_id) REFERENCES "USER" (user_id) ON DELETE CASCADE,
     CONSTRAINT fk_game_player2 FOREIGN KEY (player2_id) REFERENCES "USER" (user_id) ON DELETE CASCADE,
     CONSTRAINT fk_game_current_turn FOREIGN KEY (current_turn) REFERENCES "USER" (user_id) ON DELETE CASCADE,
@@ -92,17 +167,28 @@ CREATE TABLE GAME (
     -- Constraints
     CONSTRAINT chk_different_players CHECK (player1_id != player2_id),
     CONSTRAINT chk_different_colors CHECK (player1_color != player2_color),
-    CONSTRAINT chk_valid_turn CHECK (current_turn IN (player1_id, player2_id))
+    CONSTRAINT chk_valid_turn CHECK (current_turn IN (player1_id, player2_id)),
+    CONSTRAINT chk_time_control CHECK (time_delay_seconds >= 0 AND time_reserve_seconds >= 0),
+    CONSTRAINT chk_dice_seed CHECK ((dice_mode = 'random') = (dice_seed IS NULL)),
+    CONSTRAINT chk_dice_commitment CHECK ((dice_mode = 'fair') = (dice_commitment IS NOT NULL)),
+    CONSTRAINT chk_ruleset_variant CHECK (ruleset = 'backgammon' OR variant = 'standard'),
+    CONSTRAINT chk_tavli_match CHECK (ruleset != 'tavli' OR match_id IS NOT NULL)
 );
 
 CREATE INDEX idx_game_player1_id ON GAME(player1_id);
 CREATE INDEX idx_game_player2_id ON GAME(player2_id);
 CREATE INDEX idx_game_status ON GAME(game_status);
 CREATE INDEX idx_game_created_at ON GAME(created_at);
+CREATE INDEX idx_game_match_id ON GAME(match_id, game_number);
 
 -- ============================================================================
 -- GAME_STATE table
 -- Store current board configuration and game state
+-- pinned holds Plakoto checkers trapped under an opponent's, per point like
+-- board_state, and is NULL when none are
+-- hand_white and hand_black count Acey-Deucey checkers not yet entered, and
+-- bonus_stage tracks the doubles a fully played 1-2 earns there: 'choose'
+-- until the player picks a number, then 'doubles' until they are played
 -- ============================================================================
 CREATE TABLE GAME_STATE (
     state_id SERIAL PRIMARY KEY,
@@ -112,12 +198,35 @@ CREATE TABLE GAME_STATE (
     bar_black INT NOT NULL DEFAULT 0,
     borne_off_white INT NOT NULL DEFAULT 0,
     borne_off_black INT NOT NULL DEFAULT 0,
+    hand_white INT NOT NULL DEFAULT 0,
+    hand_black INT NOT NULL DEFAULT 0,
+    pinned JSONB NULL,
     dice_roll JSONB NULL,
     dice_used JSONB NULL,
+    turn_number INT NOT NULL DEFAULT 0,
+    dice_count INT NOT NULL DEFAULT 0,
+    bonus_stage VARCHAR(10) NOT NULL DEFAULT '',
+    cube_value INT NOT NULL DEFAULT 1,
+    cube_owner INT NULL,
+    cube_offered_by INT NULL,
+    cube_beavered BOOLEAN NOT NULL DEFAULT FALSE,
+    version INT NOT NULL DEFAULT 1,
+    opening_die_player1 INT NULL,
+    opening_die_player2 INT NULL,
+    clock_player1_ms BIGINT NULL,
+    clock_player2_ms BIGINT NULL,
+    clock_running INT NULL,
+    clock_started_at TIMESTAMP NULL,
     last_updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     -- Foreign keys
     CONSTRAINT fk_gamestate_game FOREIGN KEY (game_id) REFERENCES GAME (game_id) ON DELETE CASCADE,
+    CONSTRAINT fk_gamestate_cube_owner FOREIGN KEY (cube_owner) REFERENCES "USER" (user_id) ON DELETE SET NULL,
+    CONSTRAINT fk_gamestate_cube_offered_by FOREIGN KEY (cube_offered_by) REFERENCES "USER" (user_id) ON DELETE SET NULL,
+    CONSTRAINT fk_gamestate_clock_running FOREIGN KEY (clock_running) REFERENCES "USER" (user_id) ON DELETE SET NULL,
     -- Constraints
+    CONSTRAINT chk_cube_value_positive CHECK (cube_value >= 1),
+    CONSTRAINT chk_opening_die_player1 CHECK (opening_die_player1 BETWEEN 1 AND 6),
+    CONSTRAINT chk_opening_die_player2 CHECK (opening_die_player2 BETWEEN 1 AND 6),
     CONSTRAINT chk_bar_white_range CHECK (
         bar_white >= 0
         AND bar_white <= 15
@@ -133,10 +242,16 @@ CREATE TABLE GAME_STATE (
     CONSTRAINT chk_borne_black_range CHECK (
         borne_off_black >= 0
         AND borne_off_black <= 15
-    )
+    ),
+    CONSTRAINT chk_hand_range CHECK (
+        hand_white BETWEEN 0 AND 15
+        AND hand_black BETWEEN 0 AND 15
+    ),
+    CONSTRAINT chk_bonus_stage CHECK (bonus_stage IN ('', 'choose', 'doubles'))
 );
 
 CREATE INDEX idx_gamestate_last_updated ON GAME_STATE(last_updated);
+CREATE INDEX idx_gamestate_clock_running ON GAME_STATE(clock_running) WHERE clock_running IS NOT NULL;
 
 -- ============================================================================
 -- MOVE table
@@ -151,6 +266,8 @@ CREATE TABLE MOVE (
     to_point INT NOT NULL,
     die_used INT NOT NULL,
     hit_opponent BOOLEAN DEFAULT FALSE,
+    turn_number INT NOT NULL DEFAULT 0,
+    dice_roll JSONB NULL,
     timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     -- Foreign keys
     CONSTRAINT fk_move_game FOREIGN KEY (game_id) REFERENCES GAME (game_id) ON DELETE CASCADE,
@@ -175,6 +292,109 @@ CREATE INDEX idx_move_game_move ON MOVE(game_id, move_number);
 CREATE INDEX idx_move_player_id ON MOVE(player_id);
 CREATE INDEX idx_move_timestamp ON MOVE(timestamp);
 
+-- ============================================================================
+-- DICE_ROLL table
+-- Log every roll of the dice for fairness statistics, opening dice included
+-- (one row per die). Rolls of games played before the log existed are
+-- recovered from their move history, without their index or bar details.
+-- ============================================================================
+CREATE TYPE dice_roll_kind_enum AS ENUM ('opening', 'turn');
+
+CREATE TABLE DICE_ROLL (
+    roll_id SERIAL PRIMARY KEY,
+    game_id INT NOT NULL,
+    player_id INT NOT NULL,
+    kind dice_roll_kind_enum NOT NULL,
+    die1 INT NOT NULL,
+    die2 INT NULL,
+    dice_index INT NULL,
+    bar_checkers INT NULL,
+    blocked_entry_points INT NULL,
+    entered BOOLEAN NULL,
+    rolled_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    -- Foreign keys
+    CONSTRAINT fk_dice_roll_game FOREIGN KEY (game_id) REFERENCES GAME (game_id) ON DELETE CASCADE,
+    CONSTRAINT fk_dice_roll_player FOREIGN KEY (player_id) REFERENCES "USER" (user_id) ON DELETE CASCADE,
+    -- Constraints
+    CONSTRAINT chk_dice_roll_die1 CHECK (die1 BETWEEN 1 AND 6),
+    CONSTRAINT chk_dice_roll_die2 CHECK (die2 BETWEEN 1 AND 6),
+    CONSTRAINT chk_dice_roll_kind CHECK ((kind = 'opening') = (die2 IS NULL)),
+    CONSTRAINT chk_dice_roll_blocked CHECK (blocked_entry_points BETWEEN 0 AND 6)
+);
+
+CREATE INDEX idx_dice_roll_game_id ON DICE_ROLL(game_id);
+CREATE INDEX idx_dice_roll_player_id ON DICE_ROLL(player_id);
+
+-- ============================================================================
+-- DATA_BACKFILL table
+-- Record each one-time recovery of historic data once it has run, so it is
+-- never run again
+-- ============================================================================
+CREATE TABLE DATA_BACKFILL (
+    name VARCHAR(64) PRIMARY KEY,
+    completed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ============================================================================
+-- GAME_ANALYSIS table
+-- Store the review of a finished game's checker play
+-- ============================================================================
+CREATE TABLE GAME_ANALYSIS (
+    game_id INT PRIMARY KEY,
+    evaluator VARCHAR(32) NOT NULL,
+    player1_error_rate DOUBLE PRECISION NULL,
+    player2_error_rate DOUBLE PRECISION NULL,
+    analysis JSONB NOT NULL,
+    analyzed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    -- Foreign keys
+    CONSTRAINT fk_analysis_game FOREIGN KEY (game_id) REFERENCES GAME (game_id) ON DELETE CASCADE
+);
+
+-- ============================================================================
+-- ROLLOUT table
+-- Queue Monte Carlo rollouts of a position and store their results
+-- The position is copied when the rollout is requested, so later moves in the
+-- game do not change it
+-- ============================================================================
+CREATE TYPE rollout_status_enum AS ENUM ('queued', 'running', 'completed', 'failed');
+
+CREATE TABLE ROLLOUT (
+    rollout_id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    game_id INT NULL,
+    board_state JSONB NOT NULL,
+    bar_white INT NOT NULL DEFAULT 0,
+    bar_black INT NOT NULL DEFAULT 0,
+    borne_off_white INT NOT NULL DEFAULT 0,
+    borne_off_black INT NOT NULL DEFAULT 0,
+    on_roll color_enum NOT NULL,
+    dice_roll JSONB NULL,
+    dice_used JSONB NULL,
+    trials INT NOT NULL,
+    truncation INT NOT NULL DEFAULT 0,
+    bot_level bot_level_enum NOT NULL DEFAULT 'hard',
+    variance_reduction BOOLEAN NOT NULL DEFAULT FALSE,
+    seed BIGINT NOT NULL,
+    candidates INT NOT NULL DEFAULT 1,
+    status rollout_status_enum NOT NULL DEFAULT 'queued',
+    trials_done INT NOT NULL DEFAULT 0,
+    result JSONB NULL,
+    error_message TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    -- Foreign keys
+    CONSTRAINT fk_rollout_user FOREIGN KEY (user_id) REFERENCES "USER" (user_id) ON DELETE CASCADE,
+    CONSTRAINT fk_rollout_game FOREIGN KEY (game_id) REFERENCES GAME (game_id) ON DELETE SET NULL,
+    -- Constraints
+    CONSTRAINT chk_rollout_trials_positive CHECK (trials > 0),
+    CONSTRAINT chk_rollout_truncation CHECK (truncation >= 0),
+    CONSTRAINT chk_rollout_candidates_positive CHECK (candidates > 0)
+);
+
+CREATE INDEX idx_rollout_status ON ROLLOUT(status, created_at);
+CREATE INDEX idx_rollout_user_id ON ROLLOUT(user_id);
+
 -- ============================================================================
 -- CHAT_ROOM table
 -- Separate chat contexts for lobby and individual game rooms
@@ -254,6 +474,15 @@ CREATE TABLE GAME_INVITATION (
     challenged_id INT NOT NULL,
     status invitation_status_enum NOT NULL DEFAULT 'pending',
     game_id INT NULL,
+    allow_beavers BOOLEAN NOT NULL DEFAULT FALSE,
+    allow_hints BOOLEAN NOT NULL DEFAULT TRUE,
+    time_delay_seconds INT NOT NULL DEFAULT 0,
+    time_reserve_seconds INT NOT NULL DEFAULT 0,
+    match_length INT NOT NULL DEFAULT 0,
+    dice_mode dice_mode_enum NOT NULL DEFAULT 'random',
+    variant variant_enum NOT NULL DEFAULT 'standard',
+    starting_board JSONB NOT NULL,
+    ruleset ruleset_enum NOT NULL DEFAULT 'backgammon',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     -- Foreign keys
     CONSTRAINT fk_invitation_challenger FOREIGN KEY (challenger_id) REFERENCES "USER" (user_id) ON DELETE CASCADE,
@@ -261,6 +490,9 @@ CREATE TABLE GAME_INVITATION (
     CONSTRAINT fk_invitation_game FOREIGN KEY (game_id) REFERENCES GAME (game_id) ON DELETE SET NULL,
     -- Constraints
     CONSTRAINT chk_different_users CHECK (challenger_id != challenged_id),
+    CONSTRAINT chk_invitation_match_length CHECK (match_length >= 0),
+    CONSTRAINT chk_invitation_ruleset_variant CHECK (ruleset = 'backgammon' OR variant = 'standard'),
+    CONSTRAINT chk_invitation_tavli_match CHECK (ruleset != 'tavli' OR match_length > 0),
     CONSTRAINT chk_game_only_when_accepted CHECK (
         (
             status = 'accepted'
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"backgammon/business"
+	"backgammon/repository"
+	"backgammon/util"
+)
+
+// Choose the doubles an Acey-Deucey player plays after a fully played 1-2
+func ChooseDoublesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Parse game ID from URL path
+	gameID, err := parseGameIDFromPath(strings.TrimSuffix(r.URL.Path, "/choose-doubles"))
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid game ID")
+		return
+	}
+
+	var req ChooseDoublesRequest
+	if err := util.ParseJSONBody(r, &req); err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Get game details
+	game, err := db.GetGameByID(r.Context(), gameID)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	// Verify user is a player in this game
+	if game.Player1ID != userID && game.Player2ID != userID {
+		util.ErrorResponse(w, http.StatusForbidden, "You are not a player in this game")
+		return
+	}
+
+	expectedVersion, err := parseExpectedVersion(r, req.ExpectedVersion)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var step *repository.TurnStep
+	state, err := db.ApplyTurnStep(r.Context(), gameID, func(game *repository.Game, state *repository.GameState) (*repository.TurnStep, error) {
+		if err := checkExpectedVersion(state, expectedVersion); err != nil {
+			return nil, err
+		}
+		built, err := buildChooseDoublesStep(game, state, userID, req.Value)
+		step = built
+		return built, err
+	})
+	if err != nil {
+		writeGameActionError(w, err, "Failed to choose doubles")
+		return
+	}
+
+	// The chosen doubles are announced like a roll
+	publishDiceRolled(r.Context(), gameID, userID, business.TurnDice(req.Value, req.Value), state.Version)
+	publishTurnStep(r.Context(), game, userID, step, state.Version)
+	triggerBots(gameID)
+
+	writeGameState(w, game, state)
+}
+
+// Check a choice of doubles against the bonus the player has earned and set
+// them up to be played. Doubles with no legal move go straight to the next roll.
+func buildChooseDoublesStep(game *repository.Game, state *repository.GameState, userID, value int) (*repository.TurnStep, error) {
+	if game.GameStatus != "in_progress" || game.CurrentTurn != userID {
+		return nil, rejectGameAction(http.StatusBadRequest, "Not your turn")
+	}
+	if state.BonusStage != repository.BonusChoose {
+		return nil, rejectGameAction(http.StatusBadRequest, "No doubles to choose")
+	}
+	if value < 1 || value > 6 {
+		return nil, rejectGameAction(http.StatusBadRequest, "value must be between 1 and 6")
+	}
+
+	now := time.Now()
+	if err := checkClock(game, state, now); err != nil {
+		return nil, err
+	}
+
+	state.DiceRoll = business.TurnDice(value, value)
+	state.DiceUsed = make([]bool, len(state.DiceRoll))
+	state.BonusStage = repository.BonusDoubles
+
+	step := &repository.TurnStep{State: state}
+	rules := gameRules(game)
+	color := playerColor(game, userID)
+	if len(rules.LegalMoves(positionFromState(state), color, state.DiceRoll, state.DiceUsed)) == 0 {
+		endTurn(game, state, rules, step)
+	}
+
+	advanceClock(game, step, now)
+	return step, nil
+}
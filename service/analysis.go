@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"backgammon/business"
+	"backgammon/repository"
+	"backgammon/util"
+)
+
+// Number of finished games analyzed per run of the analysis job
+const analysisBatchSize = 10
+
+// Games whose moves could not be replayed, skipped until the server restarts
+var (
+	analysisFailedMu sync.Mutex
+	analysisFailed   = map[int]bool{}
+)
+
+// Analyze finished games that have not been reviewed yet
+func AnalyzeFinishedGames(ctx context.Context) {
+	db := repository.GetDB()
+	if db == nil {
+		return
+	}
+
+	gameIDs, err := db.GetGamesAwaitingAnalysis(ctx, analysisBatchSize)
+	if err != nil {
+		log.Printf("Failed to get games awaiting analysis: %v", err)
+		return
+	}
+
+	for _, gameID := range gameIDs {
+		analysisFailedMu.Lock()
+		failed := analysisFailed[gameID]
+		analysisFailedMu.Unlock()
+		if failed {
+			continue
+		}
+
+		if err := analyzeGame(ctx, db, gameID); err != nil {
+			log.Printf("Failed to analyze game %d: %v", gameID, err)
+			analysisFailedMu.Lock()
+			analysisFailed[gameID] = true
+			analysisFailedMu.Unlock()
+			continue
+		}
+		log.Printf("Game %d analyzed", gameID)
+	}
+}
+
+// Replay a finished game's moves, judge every turn and store the result
+func analyzeGame(ctx context.Context, db *repository.Postgres, gameID int) error {
+	game, err := db.GetGameByID(ctx, gameID)
+	if err != nil {
+		return err
+	}
+
+	moves, err := db.GetMoveHistory(ctx, gameID)
+	if err != nil {
+		return err
+	}
+
+	start := business.Position{
+		Board:          game.StartingBoard,
+		BarWhite:       game.StartingBarWhite,
+		BarBlack:       game.StartingBarBlack,
+		BornedOffWhite: game.StartingBornedOffWhite,
+		BornedOffBlack: game.StartingBornedOffBlack,
+	}
+	analysis, err := business.AnalyzeGame(positionEvaluator, start, turnRecords(game, moves))
+	if err != nil {
+		return err
+	}
+
+	analysisJSON, err := json.Marshal(analysis)
+	if err != nil {
+		return fmt.Errorf("failed to encode analysis: %w", err)
+	}
+
+	return db.SaveGameAnalysis(ctx, &repository.GameAnalysis{
+		GameID:           gameID,
+		Evaluator:        evaluatorName(),
+		Player1ErrorRate: errorRate(analysis.Players[business.Color(game.Player1Color)]),
+		Player2ErrorRate: errorRate(analysis.Players[business.Color(game.Player2Color)]),
+		Analysis:         analysisJSON,
+	})
+}
+
+// Group a game's move history into turns. Moves recorded before turns were
+// numbered are grouped by runs of the same player, without their dice.
+func turnRecords(game *repository.Game, moves []repository.Move) []business.TurnRecord {
+	turns := []business.TurnRecord{}
+	for i, move := range moves {
+		// Acey-Deucey doubles chosen after a 1-2 share its turn number but not its dice
+		newTurn := i == 0 || move.PlayerID != moves[i-1].PlayerID || move.TurnNumber != moves[i-1].TurnNumber ||
+			!slices.Equal(move.Dice, moves[i-1].Dice)
+		if newTurn {
+			turns = append(turns, business.TurnRecord{
+				Color: playerColor(game, move.PlayerID),
+				Dice:  move.Dice,
+			})
+		}
+
+		turn := &turns[len(turns)-1]
+		turn.Moves = append(turn.Moves, business.MoveStep{
+			FromPoint:   move.FromPoint,
+			ToPoint:     move.ToPoint,
+			DieUsed:     move.DieUsed,
+			HitOpponent: move.HitOpponent,
+		})
+	}
+	return turns
+}
+
+// Return a player's error rate, or nil when they made no decisions
+func errorRate(player business.PlayerAnalysis) *float64 {
+	if player.Decisions == 0 {
+		return nil
+	}
+	return &player.ErrorRate
+}
+
+// Serve the post-game analysis of a finished game to its players
+func GameAnalysisHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Parse game ID from URL path
+	gameID, err := parseGameIDFromPath(strings.TrimSuffix(r.URL.Path, "/analysis"))
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid game ID")
+		return
+	}
+
+	// Get game details
+	game, err := db.GetGameByID(r.Context(), gameID)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	// Verify user is a player in this game
+	if game.Player1ID != userID && game.Player2ID != userID {
+		util.ErrorResponse(w, http.StatusForbidden, "You are not a player in this game")
+		return
+	}
+
+	// Verify game is finished
+	if game.GameStatus != "completed" && game.GameStatus != "abandoned" {
+		util.ErrorResponse(w, http.StatusBadRequest, "Game is not finished")
+		return
+	}
+
+	stored, err := db.GetGameAnalysis(r.Context(), gameID)
+	if err != nil {
+		log.Printf("Failed to get game analysis: %v", err)
+		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get game analysis")
+		return
+	}
+
+	// The analysis job has not reached this game yet
+	if stored == nil {
+		util.JSONResponse(w, http.StatusAccepted, map[string]interface{}{
+			"gameId": gameID,
+			"status": "pending",
+		})
+		return
+	}
+
+	var analysis business.GameAnalysis
+	if err := json.Unmarshal(stored.Analysis, &analysis); err != nil {
+		log.Printf("Failed to decode game analysis: %v", err)
+		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get game analysis")
+		return
+	}
+
+	// Label each turn with the player who made it
+	playerIDs := map[business.Color]int{
+		business.Color(game.Player1Color): game.Player1ID,
+		business.Color(game.Player2Color): game.Player2ID,
+	}
+	rules := gameRules(game)
+	turns := []map[string]interface{}{}
+	for _, turn := range analysis.Turns {
+		turns = append(turns, map[string]interface{}{
+			"turn":         turn.Turn,
+			"playerId":     playerIDs[turn.Color],
+			"color":        turn.Color,
+			"dice":         turn.Dice,
+			"moves":        turn.Moves,
+			"notation":     business.FormatPlay(rules, turn.Moves, turn.Color),
+			"bestMoves":    turn.BestMoves,
+			"bestNotation": business.FormatPlay(rules, turn.BestMoves, turn.Color),
+			"equity":       turn.Equity,
+			"bestEquity":   turn.BestEquity,
+			"equityLoss":   turn.EquityLoss,
+			"forced":       turn.Forced,
+			"judgement":    turn.Judgement,
+		})
+	}
+
+	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"gameId":     gameID,
+		"status":     "complete",
+		"evaluator":  stored.Evaluator,
+		"analyzedAt": stored.AnalyzedAt,
+		"player1":    playerSummary(game.Player1ID, business.Color(game.Player1Color), analysis.Players),
+		"player2":    playerSummary(game.Player2ID, business.Color(game.Player2Color), analysis.Players),
+		"turns":      turns,
+	})
+}
+
+// Summarize one player's checker play for the analysis response
+func playerSummary(playerID int, color business.Color, players map[business.Color]business.PlayerAnalysis) map[string]interface{} {
+	player := players[color]
+	return map[string]interface{}{
+		"userId":     playerID,
+		"color":      color,
+		"decisions":  player.Decisions,
+		"doubtful":   player.Doubtful,
+		"errors":     player.Errors,
+		"blunders":   player.Blunders,
+		"equityLoss": player.EquityLoss,
+		"errorRate":  player.ErrorRate,
+	}
+}
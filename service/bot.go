@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"backgammon/business"
+	"backgammon/repository"
+)
+
+// Pause before each bot action so players can follow the game
+const botThinkTime = 800 * time.Millisecond
+
+// Most actions a bot takes in one run, a guard against looping forever
+const maxBotActions = 100
+
+// Computer players created at startup, one per level
+var defaultBots = []struct {
+	Username string
+	Level    business.BotLevel
+}{
+	{"bot_easy", business.BotEasy},
+	{"bot_medium", business.BotMedium},
+	{"bot_hard", business.BotHard},
+}
+
+var (
+	botMu     sync.RWMutex
+	botLevels = map[int]business.BotLevel{} // Level of each computer player by user ID
+
+	botRunMu   sync.Mutex
+	botRunning = map[int]bool{} // Games a bot is currently playing in
+	botRerun   = map[int]bool{} // Games that changed while a bot was playing
+)
+
+// Create the computer players and remember their levels
+func InitBots(ctx context.Context) error {
+	db := repository.GetDB()
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+
+	for _, bot := range defaultBots {
+		if _, err := db.EnsureBotUser(ctx, bot.Username, string(bot.Level)); err != nil {
+			return err
+		}
+	}
+
+	bots, err := db.GetBotUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	botMu.Lock()
+	defer botMu.Unlock()
+	for _, bot := range bots {
+		level, err := business.ParseBotLevel(bot.Level)
+		if err != nil {
+			return err
+		}
+		botLevels[bot.UserID] = level
+	}
+
+	return nil
+}
+
+// Return a computer player's level. Returns false for people.
+func botLevel(userID int) (business.BotLevel, bool) {
+	botMu.RLock()
+	defer botMu.RUnlock()
+	level, ok := botLevels[userID]
+	return level, ok
+}
+
+// Return the computer player of a level. Returns false when there is none.
+func botForLevel(level business.BotLevel) (int, bool) {
+	botMu.RLock()
+	defer botMu.RUnlock()
+	for userID, userLevel := range botLevels {
+		if userLevel == level {
+			return userID, true
+		}
+	}
+	return 0, false
+}
+
+// Let any computer player in a game act. Runs in the background; a game that
+// changes while its bot is playing is looked at again once the bot is done.
+func triggerBots(gameID int) {
+	runBots(gameID, playBots)
+}
+
+// Run play for a game in the background unless it is already running, in
+// which case it runs once more when the current run ends
+func runBots(gameID int, play func(ctx context.Context, gameID int)) {
+	botRunMu.Lock()
+	defer botRunMu.Unlock()
+
+	if botRunning[gameID] {
+		botRerun[gameID] = true
+		return
+	}
+	botRunning[gameID] = true
+
+	go func() {
+		for {
+			play(context.Background(), gameID)
+
+			botRunMu.Lock()
+			if !botRerun[gameID] {
+				delete(botRunning, gameID)
+				botRunMu.Unlock()
+				return
+			}
+			delete(botRerun, gameID)
+			botRunMu.Unlock()
+		}
+	}()
+}
+
+// Take bot actions until a person has to act or the game is over
+func playBots(ctx context.Context, gameID int) {
+	db := repository.GetDB()
+	if db == nil {
+		return
+	}
+
+	for range maxBotActions {
+		acted, err := botAct(ctx, db, gameID)
+		if err != nil {
+			log.Printf("Bot failed to act in game %d: %v", gameID, err)
+			return
+		}
+		if !acted {
+			return
+		}
+	}
+	log.Printf("Bot stopped after %d actions in game %d", maxBotActions, gameID)
+}
+
+// Take the next action a computer player owes in a game. Returns false when
+// it is not a bot's move.
+func botAct(ctx context.Context, db *repository.Postgres, gameID int) (bool, error) {
+	game, err := db.GetGameByID(ctx, gameID)
+	if err != nil {
+		return false, err
+	}
+	state, err := db.GetGameState(ctx, gameID)
+	if err != nil {
+		return false, err
+	}
+
+	switch game.GameStatus {
+	case "pending":
+		// Each bot rolls its opening die
+		for _, playerID := range []int{game.Player1ID, game.Player2ID} {
+			ownDie := state.OpeningDie1
+			if playerID == game.Player2ID {
+				ownDie = state.OpeningDie2
+			}
+			if _, isBot := botLevel(playerID); isBot && ownDie == nil {
+				return true, botRollOpeningDie(ctx, db, game, playerID)
+			}
+		}
+		return false, nil
+
+	case "in_progress":
+		// A pending double is answered by the doubler's opponent
+		if state.CubeOfferedBy != nil {
+			responder := opponentOf(game, *state.CubeOfferedBy)
+			level, isBot := botLevel(responder)
+			if !isBot {
+				return false, nil
+			}
+			action := "drop"
+			if newBot(game, level).ShouldTake(positionFromState(state), playerColor(game, responder)) {
+				action = "take"
+			}
+			return true, botCubeAction(ctx, db, game, responder, action)
+		}
+
+		level, isBot := botLevel(game.CurrentTurn)
+		if !isBot {
+			return false, nil
+		}
+		if state.BonusStage == repository.BonusChoose {
+			return true, botChooseDoubles(ctx, db, game, state, level)
+		}
+		if state.DiceRoll == nil {
+			if botWantsToDouble(game, state, level) {
+				return true, botCubeAction(ctx, db, game, game.CurrentTurn, "double")
+			}
+			return true, botRollDice(ctx, db, game, game.CurrentTurn)
+		}
+		return true, botPlayTurn(ctx, db, game, state, level)
+	}
+
+	return false, nil
+}
+
+// Return a bot of the given level playing by a game's rules
+func newBot(game *repository.Game, level business.BotLevel) *business.Bot {
+	rules := gameRules(game)
+	bot := business.NewBot(level, gameEvaluator(rules), nil)
+	bot.Rules = rules
+	return bot
+}
+
+// Return the color a player has in a game
+func playerColor(game *repository.Game, playerID int) business.Color {
+	if playerID == game.Player1ID {
+		return business.Color(game.Player1Color)
+	}
+	return business.Color(game.Player2Color)
+}
+
+// Check whether a bot on roll may and wants to double
+func botWantsToDouble(game *repository.Game, state *repository.GameState, level business.BotLevel) bool {
+	err := business.CanOfferDouble(cubeFromState(state), game.CurrentTurn, true, false, cubeInPlay(game))
+	if err != nil {
+		return false
+	}
+	return newBot(game, level).ShouldDouble(positionFromState(state), playerColor(game, game.CurrentTurn))
+}
+
+// Roll a bot's opening die
+func botRollOpeningDie(ctx context.Context, db *repository.Postgres, game *repository.Game, botID int) error {
+	time.Sleep(botThinkTime)
+
+	roll, err := db.RollOpeningDie(ctx, game.GameID, botID, checkOpeningRoll(botID, nil))
+	if err != nil {
+		return err
+	}
+
+	state, err := db.GetGameState(ctx, game.GameID)
+	if err != nil {
+		return err
+	}
+
+	publishOpeningRoll(ctx, game.GameID, botID, roll, state.Version)
+	return nil
+}
+
+// Roll the dice for a bot's turn
+func botRollDice(ctx context.Context, db *repository.Postgres, game *repository.Game, botID int) error {
+	time.Sleep(botThinkTime)
+
+	dice, err := db.RollDice(ctx, game.GameID, checkRollDice(botID, nil))
+	if err != nil {
+		return err
+	}
+
+	state, err := db.GetGameState(ctx, game.GameID)
+	if err != nil {
+		return err
+	}
+
+	publishDiceRolled(ctx, game.GameID, botID, dice, state.Version)
+	return nil
+}
+
+// Offer, take or drop a double for a bot
+func botCubeAction(ctx context.Context, db *repository.Postgres, game *repository.Game, botID int, action string) error {
+	time.Sleep(botThinkTime)
+
+	var step *repository.TurnStep
+	state, err := db.ApplyTurnStep(ctx, game.GameID, func(game *repository.Game, state *repository.GameState) (*repository.TurnStep, error) {
+		built, err := buildCubeStep(game, state, botID, action)
+		step = built
+		return built, err
+	})
+	if err != nil {
+		return err
+	}
+
+	publishCubeAction(ctx, game.GameID, botID, action, state)
+	publishTurnStep(ctx, game, botID, step, state.Version)
+	if step.WinnerID != 0 {
+		advanceMatch(ctx, game)
+	}
+	return nil
+}
+
+// Choose a play for a bot's roll and make it one checker at a time through
+// the same validation as a player's moves
+func botPlayTurn(ctx context.Context, db *repository.Postgres, game *repository.Game, state *repository.GameState, level business.BotLevel) error {
+	botID := game.CurrentTurn
+	bot := newBot(game, level)
+	play, ok := bot.ChoosePlay(positionFromState(state), playerColor(game, botID), state.DiceRoll, state.DiceUsed)
+	if !ok {
+		return botPass(ctx, db, game, botID)
+	}
+
+	for _, move := range play.Moves {
+		time.Sleep(botThinkTime)
+
+		req := &MoveRequest{FromPoint: move.FromPoint, ToPoint: move.ToPoint, DieUsed: move.DieUsed}
+		var step *repository.TurnStep
+		state, err := db.ApplyTurnStep(ctx, game.GameID, func(game *repository.Game, state *repository.GameState) (*repository.TurnStep, error) {
+			built, err := buildMoveStep(game, state, botID, req)
+			step = built
+			return built, err
+		})
+		if err != nil {
+			return err
+		}
+
+		publishTurnStep(ctx, game, botID, step, state.Version)
+		if step.WinnerID != 0 {
+			advanceMatch(ctx, game)
+			return nil
+		}
+	}
+	return nil
+}
+
+// Choose the doubles a bot's Acey-Deucey 1-2 has earned
+func botChooseDoubles(ctx context.Context, db *repository.Postgres, game *repository.Game, state *repository.GameState, level business.BotLevel) error {
+	time.Sleep(botThinkTime)
+
+	botID := game.CurrentTurn
+	bot := newBot(game, level)
+	value := bot.ChooseDoubles(positionFromState(state), playerColor(game, botID))
+
+	var step *repository.TurnStep
+	state, err := db.ApplyTurnStep(ctx, game.GameID, func(game *repository.Game, state *repository.GameState) (*repository.TurnStep, error) {
+		built, err := buildChooseDoublesStep(game, state, botID, value)
+		step = built
+		return built, err
+	})
+	if err != nil {
+		return err
+	}
+
+	publishDiceRolled(ctx, game.GameID, botID, business.TurnDice(value, value), state.Version)
+	publishTurnStep(ctx, game, botID, step, state.Version)
+	return nil
+}
+
+// Pass a bot's turn when its roll cannot be played
+func botPass(ctx context.Context, db *repository.Postgres, game *repository.Game, botID int) error {
+	time.Sleep(botThinkTime)
+
+	var step *repository.TurnStep
+	state, err := db.ApplyTurnStep(ctx, game.GameID, func(game *repository.Game, state *repository.GameState) (*repository.TurnStep, error) {
+		built, err := buildPassStep(game, state, botID)
+		step = built
+		return built, err
+	})
+	if err != nil {
+		return err
+	}
+
+	publishTurnStep(ctx, game, botID, step, state.Version)
+	return nil
+}
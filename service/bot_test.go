@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Wait until no bot run is left for a game
+func waitForBotsIdle(t *testing.T, gameID int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		botRunMu.Lock()
+		running := botRunning[gameID] || botRerun[gameID]
+		botRunMu.Unlock()
+		if !running {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("bot run never ended")
+}
+
+func TestRunBotsEnds(t *testing.T) {
+	const gameID = 1
+	calls := 0
+	runBots(gameID, func(context.Context, int) {
+		calls++
+	})
+
+	waitForBotsIdle(t, gameID)
+	if calls != 1 {
+		t.Fatalf("played %d times, want 1", calls)
+	}
+}
+
+func TestRunBotsRerunsOnceAfterChange(t *testing.T) {
+	const gameID = 2
+	calls := 0
+	release := make(chan struct{})
+	play := func(context.Context, int) {
+		calls++
+		if calls == 1 {
+			<-release
+		}
+	}
+
+	runBots(gameID, play)
+	// Changes while the bot is playing collapse into a single rerun
+	runBots(gameID, play)
+	runBots(gameID, play)
+	close(release)
+
+	waitForBotsIdle(t, gameID)
+	if calls != 2 {
+		t.Fatalf("played %d times, want 2", calls)
+	}
+
+	runBots(gameID, play)
+	waitForBotsIdle(t, gameID)
+	if calls != 3 {
+		t.Fatalf("played %d times after the run ended, want 3", calls)
+	}
+}
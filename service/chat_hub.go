@@ -67,6 +67,12 @@ type Hub struct {
 
 	// Mutex for thread-safe access to clients map
 	mu sync.RWMutex
+
+	// Chat room of each game with events being broadcast (map[gameID])
+	gameRooms map[int]int
+
+	// Mutex for thread-safe access to the game rooms map
+	gameMu sync.Mutex
 }
 
 // NewHub creates a new Hub instance
@@ -77,6 +83,7 @@ func NewHub() *Hub {
 		unregister: make(chan *ClientRegistration),
 		clients:    make(map[int][]*Client),
 		rooms:      make(map[int]map[*Client]bool),
+		gameRooms:  make(map[int]int),
 	}
 }
 
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"backgammon/business"
+	"backgammon/repository"
+)
+
+// Longest delay and reserve an invitation may ask for
+const (
+	maxDelaySeconds   = 5 * 60
+	maxReserveSeconds = 4 * 60 * 60
+)
+
+// errClockNotExpired skips a timed game whose running player still has time
+var errClockNotExpired = errors.New("clock has not expired")
+
+// Return the time control of a game
+func timeControl(game *repository.Game) business.TimeControl {
+	return business.TimeControl{
+		Delay:   time.Duration(game.DelaySeconds) * time.Second,
+		Reserve: time.Duration(game.ReserveSeconds) * time.Second,
+	}
+}
+
+// Build the business clock from a stored game state. Returns false for an
+// untimed game.
+func clockFromState(game *repository.Game, state *repository.GameState) (business.Clock, bool) {
+	if state.ClockPlayer1 == nil || state.ClockPlayer2 == nil {
+		return business.Clock{}, false
+	}
+
+	clock := business.Clock{
+		Player1ID:      game.Player1ID,
+		Player2ID:      game.Player2ID,
+		Player1Reserve: time.Duration(*state.ClockPlayer1) * time.Millisecond,
+		Player2Reserve: time.Duration(*state.ClockPlayer2) * time.Millisecond,
+	}
+	if state.ClockRunning != nil && state.ClockStartedAt != nil {
+		clock.Running = *state.ClockRunning
+		clock.StartedAt = *state.ClockStartedAt
+	}
+	return clock, true
+}
+
+// Copy a business clock onto a stored game state
+func applyClockToState(state *repository.GameState, clock business.Clock) {
+	player1 := clock.Player1Reserve.Milliseconds()
+	player2 := clock.Player2Reserve.Milliseconds()
+	state.ClockPlayer1 = &player1
+	state.ClockPlayer2 = &player2
+	state.ClockRunning = nil
+	state.ClockStartedAt = nil
+	if clock.Running != 0 {
+		running := clock.Running
+		startedAt := clock.StartedAt.UTC()
+		state.ClockRunning = &running
+		state.ClockStartedAt = &startedAt
+	}
+}
+
+// Reject game actions once the running player's time has run out; the
+// clock sweep settles the game
+func checkClock(game *repository.Game, state *repository.GameState, now time.Time) error {
+	clock, timed := clockFromState(game, state)
+	if timed && clock.Expired(timeControl(game), now) {
+		return rejectGameAction(http.StatusConflict, "Time has expired")
+	}
+	return nil
+}
+
+// Move the clock to whoever must act after a turn step: nobody once the game
+// is over, the opponent while a double awaits an answer, otherwise the player
+// whose turn it is
+func advanceClock(game *repository.Game, step *repository.TurnStep, now time.Time) {
+	clock, timed := clockFromState(game, step.State)
+	if !timed {
+		return
+	}
+
+	next := game.CurrentTurn
+	switch {
+	case step.WinnerID != 0:
+		next = 0
+	case step.State.CubeOfferedBy != nil:
+		next = opponentOf(game, *step.State.CubeOfferedBy)
+	case step.NextTurn != 0:
+		next = step.NextTurn
+	}
+
+	applyClockToState(step.State, clock.Switch(timeControl(game), next, now))
+}
+
+// Return the other player in a game
+func opponentOf(game *repository.Game, playerID int) int {
+	if playerID == game.Player1ID {
+		return game.Player2ID
+	}
+	return game.Player1ID
+}
+
+// Return the clock fields of a game state response, or nil for an untimed game
+func clockResponse(game *repository.Game, state *repository.GameState, now time.Time) map[string]interface{} {
+	clock, timed := clockFromState(game, state)
+	if !timed {
+		return nil
+	}
+
+	tc := timeControl(game)
+	var running *int
+	if clock.Running != 0 {
+		running = &clock.Running
+	}
+	return map[string]interface{}{
+		"player1RemainingMs": max(clock.Remaining(tc, game.Player1ID, now), 0).Milliseconds(),
+		"player2RemainingMs": max(clock.Remaining(tc, game.Player2ID, now), 0).Milliseconds(),
+		"delayRemainingMs":   clock.DelayRemaining(tc, now).Milliseconds(),
+		"running":            running,
+		"delaySeconds":       game.DelaySeconds,
+		"reserveSeconds":     game.ReserveSeconds,
+	}
+}
+
+// ============================================================================
+// Clock Sweep
+// ============================================================================
+
+// Settle every timed game whose running clock has run out
+func SweepExpiredClocks(ctx context.Context) {
+	db := repository.GetDB()
+	if db == nil {
+		return
+	}
+
+	gameIDs, err := db.GetGamesWithClocksDue(ctx, time.Now())
+	if err != nil {
+		log.Printf("Failed to get timed games: %v", err)
+		return
+	}
+
+	for _, gameID := range gameIDs {
+		var game *repository.Game
+		var step *repository.TurnStep
+		var actor int
+		state, err := db.ApplyTurnStep(ctx, gameID, func(locked *repository.Game, state *repository.GameState) (*repository.TurnStep, error) {
+			game = locked
+			if state.ClockRunning != nil {
+				actor = *state.ClockRunning
+			}
+			built, err := buildTimeoutStep(locked, state, time.Now())
+			step = built
+			return built, err
+		})
+		if errors.Is(err, errClockNotExpired) {
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to settle clock for game %d: %v", gameID, err)
+			continue
+		}
+
+		publishTurnStep(ctx, game, actor, step, state.Version)
+		if step.WinnerID != 0 {
+			log.Printf("Game %d: player %d lost on time", gameID, actor)
+			advanceMatch(ctx, game)
+		}
+		triggerBots(gameID)
+	}
+}
+
+// Work out what happens to a player whose time has run out. A player who has
+// rolled but cannot move is passed as soon as their delay is used, so a
+// forced pass never costs reserve time; anyone else who runs out of time
+// loses the game, scored from the position as it stands.
+func buildTimeoutStep(game *repository.Game, state *repository.GameState, now time.Time) (*repository.TurnStep, error) {
+	clock, timed := clockFromState(game, state)
+	if !timed || clock.Running == 0 || game.GameStatus != "in_progress" {
+		return nil, errClockNotExpired
+	}
+
+	tc := timeControl(game)
+	actor := clock.Running
+	step := &repository.TurnStep{State: state}
+
+	color := business.Color(game.Player1Color)
+	if actor == game.Player2ID {
+		color = business.Color(game.Player2Color)
+	}
+	position := positionFromState(state)
+
+	rules := gameRules(game)
+	stuck := actor == game.CurrentTurn && state.DiceRoll != nil && state.CubeOfferedBy == nil &&
+		state.BonusStage != repository.BonusChoose &&
+		len(rules.LegalMoves(position, color, state.DiceRoll, state.DiceUsed)) == 0
+
+	switch {
+	case stuck && clock.DelayRemaining(tc, now) == 0:
+		endTurn(game, state, rules, step)
+	case clock.Expired(tc, now):
+		step.WinnerID = opponentOf(game, actor)
+		step.Result = scoreGame(rules, position, color.Opponent(), state.CubeValue)
+		state.CubeOfferedBy = nil
+	default:
+		return nil, errClockNotExpired
+	}
+
+	advanceClock(game, step, now)
+	return step, nil
+}
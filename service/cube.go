@@ -0,0 +1,167 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"backgammon/business"
+	"backgammon/repository"
+	"backgammon/util"
+)
+
+// Cube actions, each served at /api/v1/games/{id}/{action}
+var cubeActions = []string{"double", "take", "drop", "beaver", "raccoon"}
+
+// Offer, take, drop, beaver or raccoon a double
+func CubeActionHandler(w http.ResponseWriter, r *http.Request, action string) {
+	if r.Method != http.MethodPost {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Parse game ID from URL path
+	gameID, err := parseGameIDFromPath(strings.TrimSuffix(r.URL.Path, "/"+action))
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid game ID")
+		return
+	}
+
+	// Get game details
+	game, err := db.GetGameByID(r.Context(), gameID)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	// Verify user is a player in this game
+	if game.Player1ID != userID && game.Player2ID != userID {
+		util.ErrorResponse(w, http.StatusForbidden, "You are not a player in this game")
+		return
+	}
+
+	// Apply the cube action against the locked game state
+	var step *repository.TurnStep
+	state, err := db.ApplyTurnStep(r.Context(), gameID, func(game *repository.Game, state *repository.GameState) (*repository.TurnStep, error) {
+		built, err := buildCubeStep(game, state, userID, action)
+		step = built
+		return built, err
+	})
+	if err != nil {
+		writeGameActionError(w, err, "Failed to update cube")
+		return
+	}
+
+	// Notify both players
+	publishCubeAction(r.Context(), gameID, userID, action, state)
+	publishTurnStep(r.Context(), game, userID, step, state.Version)
+	if step.WinnerID != 0 {
+		advanceMatch(r.Context(), game)
+	}
+	triggerBots(gameID)
+
+	// Format response
+	writeGameState(w, game, state)
+}
+
+// Validate a cube action against the current game state and work out the new
+// cube, or the end of the game when a double is dropped
+func buildCubeStep(game *repository.Game, state *repository.GameState, userID int, action string) (*repository.TurnStep, error) {
+	// Verify game is in progress
+	if game.GameStatus != "in_progress" {
+		return nil, rejectGameAction(http.StatusBadRequest, "Game is not in progress")
+	}
+
+	// Verify the player still has time
+	now := time.Now()
+	if err := checkClock(game, state, now); err != nil {
+		return nil, err
+	}
+
+	cube := cubeFromState(state)
+	isPlayersTurn := game.CurrentTurn == userID
+	diceRolled := state.DiceRoll != nil
+
+	var err error
+	step := &repository.TurnStep{State: state}
+
+	switch action {
+	case "double":
+		cube, err = business.OfferDouble(cube, userID, isPlayersTurn, diceRolled, cubeInPlay(game))
+	case "take":
+		cube, err = business.TakeDouble(cube, userID)
+	case "drop":
+		// Dropping concedes a single game to the doubler at the current stake
+		var stake int
+		stake, err = business.DropDouble(cube, userID)
+		step.WinnerID = cube.OfferedBy
+		step.Result = repository.GameResult{
+			ResultType: string(business.ResultSingle),
+			PointsWon:  business.PointsWon(business.ResultSingle, stake),
+		}
+		cube.OfferedBy = 0
+	case "beaver":
+		cube, err = business.BeaverDouble(cube, userID, game.AllowBeavers)
+	case "raccoon":
+		cube, err = business.RaccoonDouble(cube, userID, game.AllowBeavers, isPlayersTurn, diceRolled)
+	default:
+		return nil, rejectGameAction(http.StatusNotFound, "Unknown cube action")
+	}
+	if err != nil {
+		return nil, rejectGameAction(http.StatusBadRequest, err.Error())
+	}
+
+	applyCubeToState(state, cube)
+	advanceClock(game, step, now)
+	return step, nil
+}
+
+// Check whether a game may be doubled: the cube is out of play for the
+// Crawford game of a match and in rule sets played without it
+func cubeInPlay(game *repository.Game) bool {
+	return !game.Crawford && gameRules(game).UsesCube()
+}
+
+// Build the business cube state from a stored game state
+func cubeFromState(state *repository.GameState) business.CubeState {
+	cube := business.CubeState{
+		Value:    state.CubeValue,
+		Beavered: state.CubeBeavered,
+	}
+	if state.CubeOwner != nil {
+		cube.Owner = *state.CubeOwner
+	}
+	if state.CubeOfferedBy != nil {
+		cube.OfferedBy = *state.CubeOfferedBy
+	}
+	return cube
+}
+
+// Copy a business cube state onto a stored game state
+func applyCubeToState(state *repository.GameState, cube business.CubeState) {
+	state.CubeValue = cube.Value
+	state.CubeBeavered = cube.Beavered
+	state.CubeOwner = nil
+	state.CubeOfferedBy = nil
+	if cube.Owner != 0 {
+		owner := cube.Owner
+		state.CubeOwner = &owner
+	}
+	if cube.OfferedBy != 0 {
+		offeredBy := cube.OfferedBy
+		state.CubeOfferedBy = &offeredBy
+	}
+}
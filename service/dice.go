@@ -0,0 +1,213 @@
+package service
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backgammon/business"
+	"backgammon/repository"
+	"backgammon/util"
+)
+
+// Check a requested dice mode, defaulting to random. Seeded dice are only
+// offered where allowed, since anyone who knows the seed knows every roll.
+func parseDiceMode(mode string, allowSeeded bool) (string, error) {
+	switch mode {
+	case "", repository.DiceModeRandom:
+		return repository.DiceModeRandom, nil
+	case repository.DiceModeFair:
+		return mode, nil
+	case repository.DiceModeSeeded:
+		if allowSeeded {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown dice mode: %s", mode)
+}
+
+// Show how a game's dice are rolled. A fair game's commitment is shown from
+// the start and its seed once the game is over, so players can check every
+// roll; a seeded game's seed is never secret.
+func GameDiceHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Parse game ID from URL path
+	gameID, err := parseGameIDFromPath(strings.TrimSuffix(r.URL.Path, "/dice"))
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid game ID")
+		return
+	}
+
+	// Get game details to verify user is a player
+	game, err := db.GetGameByID(r.Context(), gameID)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	// Verify user is a player in this game
+	if game.Player1ID != userID && game.Player2ID != userID {
+		util.ErrorResponse(w, http.StatusForbidden, "You are not a player in this game")
+		return
+	}
+
+	state, err := db.GetGameState(r.Context(), gameID)
+	if err != nil {
+		log.Printf("Failed to get game state: %v", err)
+		util.ErrorResponse(w, http.StatusNotFound, "Game state not found")
+		return
+	}
+
+	revealed := game.DiceMode == repository.DiceModeSeeded ||
+		(game.DiceMode == repository.DiceModeFair && (game.GameStatus == "completed" || game.GameStatus == "abandoned"))
+	var seed interface{}
+	if revealed {
+		seed = hex.EncodeToString(game.DiceSeed)
+	}
+
+	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"gameId":     gameID,
+		"mode":       game.DiceMode,
+		"commitment": game.DiceCommitment,
+		"seed":       seed,
+		"diceRolled": state.DiceCount,
+	})
+}
+
+// Return part over whole, or zero when whole is
+func rate(part, whole float64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return part / whole
+}
+
+// Report how logged dice compare with fair dice, for one player given by the
+// userId query parameter or for everyone. Each face, the doubles and the 21
+// distinct rolls are tested with chi-square, as is how often checkers came in
+// from the bar against the chance the points held allowed.
+func DiceStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	var userID *int
+	if value := r.URL.Query().Get("userId"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			util.ErrorResponse(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+		userID = &id
+	}
+
+	counts, err := db.GetDiceRollCounts(r.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to get dice roll counts: %v", err)
+		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get dice statistics")
+		return
+	}
+
+	// Tally the turn rolls by face, by distinct roll and by doubles
+	rolls, doubles := 0, 0
+	faces := make([]int, 6)
+	combinations := []int{}
+	combinationsExpected := []float64{}
+	for low := range 6 {
+		for high := low; high < 6; high++ {
+			count := counts.TurnRolls[low][high]
+			rolls += count
+			faces[low] += count
+			faces[high] += count
+			combinations = append(combinations, count)
+
+			// A double comes up one way in 36, any other roll two ways
+			chance := 2.0 / 36
+			if low == high {
+				doubles += count
+				chance = 1.0 / 36
+			}
+			combinationsExpected = append(combinationsExpected, chance)
+		}
+	}
+	for i := range combinationsExpected {
+		combinationsExpected[i] *= float64(rolls)
+	}
+
+	// Opening dice are single dice, so they only count toward the faces
+	dice := 2 * rolls
+	for face, count := range counts.OpeningDice {
+		faces[face] += count
+		dice += count
+	}
+	facesExpected := make([]float64, 6)
+	for face := range facesExpected {
+		facesExpected[face] = float64(dice) / 6
+	}
+
+	doublesExpected := float64(rolls) / 6
+	barMissed := counts.BarRolls - counts.BarEntered
+	barMissedExpected := float64(counts.BarRolls) - counts.BarExpected
+
+	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"userId": userID,
+		"rolls":  rolls,
+		"dice":   dice,
+		"doubles": map[string]interface{}{
+			"count":        doubles,
+			"expected":     doublesExpected,
+			"rate":         rate(float64(doubles), float64(rolls)),
+			"expectedRate": 1.0 / 6,
+			"test": business.ChiSquareTest(
+				[]int{doubles, rolls - doubles},
+				[]float64{doublesExpected, float64(rolls) - doublesExpected},
+			),
+		},
+		"faces": map[string]interface{}{
+			"counts":   faces,
+			"expected": float64(dice) / 6,
+			"test":     business.ChiSquareTest(faces, facesExpected),
+		},
+		"combinations": map[string]interface{}{
+			"test": business.ChiSquareTest(combinations, combinationsExpected),
+		},
+		"barEntry": map[string]interface{}{
+			"attempts":     counts.BarRolls,
+			"entered":      counts.BarEntered,
+			"expected":     counts.BarExpected,
+			"rate":         rate(float64(counts.BarEntered), float64(counts.BarRolls)),
+			"expectedRate": rate(counts.BarExpected, float64(counts.BarRolls)),
+			"test": business.ChiSquareTest(
+				[]int{counts.BarEntered, barMissed},
+				[]float64{counts.BarExpected, barMissedExpected},
+			),
+		},
+	})
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"backgammon/business"
+)
+
+// positionEvaluator judges positions for computer players, hints and
+// analysis. The heuristic is used until a trained network is loaded.
+var positionEvaluator business.Evaluator = business.HeuristicEvaluator{}
+
+// Return the evaluator for a game's rules. The network and bear-off database
+// know backgammon's board, so Plakoto and Fevga use the heuristic.
+func gameEvaluator(rules business.RuleSet) business.Evaluator {
+	switch rules.(type) {
+	case business.Plakoto, business.Fevga:
+		return business.HeuristicEvaluator{Rules: rules}
+	}
+	return positionEvaluator
+}
+
+// Load the neural network evaluator from a weights file written by the
+// train-evaluator command
+func InitEvaluator(path string) error {
+	network, err := business.LoadNetwork(path)
+	if err != nil {
+		return err
+	}
+	positionEvaluator = network
+	return nil
+}
+
+// Load the bear-off database written by the bearoff-db command and use it
+// for positions where both sides are bearing off
+func InitBearoffDatabase(path string) error {
+	db, err := business.LoadBearoffDatabase(path)
+	if err != nil {
+		return err
+	}
+	positionEvaluator = business.BearoffEvaluator{Database: db, Fallback: positionEvaluator}
+	return nil
+}
+
+// Name the evaluator in use, as recorded with game analyses
+func evaluatorName() string {
+	evaluator := positionEvaluator
+	suffix := ""
+	if bearoff, ok := evaluator.(business.BearoffEvaluator); ok {
+		evaluator = bearoff.Fallback
+		suffix = "+bearoff"
+	}
+	if _, ok := evaluator.(*business.Network); ok {
+		return "neural" + suffix
+	}
+	return "heuristic" + suffix
+}
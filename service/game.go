@@ -2,10 +2,13 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"backgammon/business"
 	"backgammon/repository"
@@ -16,6 +19,12 @@ import (
 func GameRouterHandler(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
 
+	// /api/v1/games/practice - POST
+	if path == "/api/v1/games/practice" && r.Method == http.MethodPost {
+		PracticeGameHandler(w, r)
+		return
+	}
+
 	// /api/v1/games/{id}/state - GET
 	if strings.HasSuffix(path, "/state") && r.Method == http.MethodGet {
 		GetGameStateHandler(w, r)
@@ -34,12 +43,68 @@ func GameRouterHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// /api/v1/games/{id}/pass - POST
+	if strings.HasSuffix(path, "/pass") && r.Method == http.MethodPost {
+		PassHandler(w, r)
+		return
+	}
+
+	// /api/v1/games/{id}/choose-doubles - POST
+	if strings.HasSuffix(path, "/choose-doubles") && r.Method == http.MethodPost {
+		ChooseDoublesHandler(w, r)
+		return
+	}
+
+	// /api/v1/games/{id}/double|take|drop|beaver|raccoon - POST
+	for _, action := range cubeActions {
+		if strings.HasSuffix(path, "/"+action) && r.Method == http.MethodPost {
+			CubeActionHandler(w, r, action)
+			return
+		}
+	}
+
+	// /api/v1/games/{id}/hint - GET
+	if strings.HasSuffix(path, "/hint") && r.Method == http.MethodGet {
+		HintHandler(w, r)
+		return
+	}
+
+	// /api/v1/games/{id}/dice - GET
+	if strings.HasSuffix(path, "/dice") && r.Method == http.MethodGet {
+		GameDiceHandler(w, r)
+		return
+	}
+
+	// /api/v1/games/{id}/xgid - GET
+	if strings.HasSuffix(path, "/xgid") && r.Method == http.MethodGet {
+		GameXGIDHandler(w, r)
+		return
+	}
+
+	// /api/v1/games/{id}/analysis - GET
+	if strings.HasSuffix(path, "/analysis") && r.Method == http.MethodGet {
+		GameAnalysisHandler(w, r)
+		return
+	}
+
+	// /api/v1/games/{id}/legal-plays - GET
+	if strings.HasSuffix(path, "/legal-plays") && r.Method == http.MethodGet {
+		GetLegalPlaysHandler(w, r)
+		return
+	}
+
 	// /api/v1/games/{id}/legal-moves - GET
 	if strings.HasSuffix(path, "/legal-moves") && r.Method == http.MethodGet {
 		GetLegalMovesHandler(w, r)
 		return
 	}
 
+	// /api/v1/games/{id}/moves - GET
+	if strings.HasSuffix(path, "/moves") && r.Method == http.MethodGet {
+		MoveHistoryHandler(w, r)
+		return
+	}
+
 	// /api/v1/games/{id}/forfeit - POST
 	if strings.HasSuffix(path, "/forfeit") && r.Method == http.MethodPost {
 		ForfeitHandler(w, r)
@@ -111,9 +176,23 @@ func GameHandler(w http.ResponseWriter, r *http.Request) {
 		"currentTurn": game.CurrentTurn,
 		"gameStatus":  game.GameStatus,
 		"winnerId":    game.WinnerID,
+		"resultType":  game.ResultType,
+		"pointsWon":   game.PointsWon,
 		"createdAt":   game.CreatedAt,
 		"startedAt":   game.StartedAt,
 		"endedAt":     game.EndedAt,
+		"matchId":     game.MatchID,
+		"gameNumber":  game.GameNumber,
+		"crawford":    game.Crawford,
+		"options": map[string]interface{}{
+			"allowBeavers":   game.AllowBeavers,
+			"allowHints":     game.AllowHints,
+			"delaySeconds":   game.DelaySeconds,
+			"reserveSeconds": game.ReserveSeconds,
+			"diceMode":       game.DiceMode,
+			"variant":        game.Variant,
+			"ruleSet":        game.RuleSet,
+		},
 	})
 }
 
@@ -157,25 +236,50 @@ func ForfeitHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verify game is not already finished
-	if game.GameStatus == "completed" || game.GameStatus == "abandoned" {
-		util.ErrorResponse(w, http.StatusBadRequest, "Game already finished")
-		return
-	}
-
-	// Forfeit the game
-	err = db.ForfeitGame(r.Context(), gameID, userID)
+	// Forfeit against the locked game, so a move finishing the game first wins
+	var step *repository.TurnStep
+	state, err := db.ApplyTurnStep(r.Context(), gameID, func(game *repository.Game, state *repository.GameState) (*repository.TurnStep, error) {
+		built, err := buildForfeitStep(game, state, userID)
+		step = built
+		return built, err
+	})
 	if err != nil {
-		log.Printf("Failed to forfeit game: %v", err)
-		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to forfeit game")
+		writeGameActionError(w, err, "Failed to forfeit game")
 		return
 	}
 
+	// Notify both players
+	publishGameForfeited(r.Context(), gameID, userID, step.WinnerID, step.Result, state.Version)
+	advanceMatch(r.Context(), game)
+
 	util.JSONResponse(w, http.StatusOK, map[string]string{
 		"message": "Game forfeited successfully",
 	})
 }
 
+// Work out a forfeit: the opponent wins a single game times the cube, however
+// the position stands
+func buildForfeitStep(game *repository.Game, state *repository.GameState, userID int) (*repository.TurnStep, error) {
+	if game.GameStatus != "pending" && game.GameStatus != "in_progress" {
+		return nil, rejectGameAction(http.StatusBadRequest, "Game already finished")
+	}
+
+	winnerID := game.Player1ID
+	if winnerID == userID {
+		winnerID = game.Player2ID
+	}
+
+	return &repository.TurnStep{
+		State:       state,
+		WinnerID:    winnerID,
+		ForfeitedBy: userID,
+		Result: repository.GameResult{
+			ResultType: string(business.ResultSingle),
+			PointsWon:  business.PointsWon(business.ResultSingle, state.CubeValue),
+		},
+	}, nil
+}
+
 // Return active games for the current user
 func ActiveGamesHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -222,9 +326,23 @@ func ActiveGamesHandler(w http.ResponseWriter, r *http.Request) {
 			"currentTurn": game.CurrentTurn,
 			"gameStatus":  game.GameStatus,
 			"winnerId":    game.WinnerID,
+			"resultType":  game.ResultType,
+			"pointsWon":   game.PointsWon,
 			"createdAt":   game.CreatedAt,
 			"startedAt":   game.StartedAt,
 			"endedAt":     game.EndedAt,
+			"matchId":     game.MatchID,
+			"gameNumber":  game.GameNumber,
+			"crawford":    game.Crawford,
+			"options": map[string]interface{}{
+				"allowBeavers":   game.AllowBeavers,
+				"allowHints":     game.AllowHints,
+				"delaySeconds":   game.DelaySeconds,
+				"reserveSeconds": game.ReserveSeconds,
+				"diceMode":       game.DiceMode,
+				"variant":        game.Variant,
+				"ruleSet":        game.RuleSet,
+			},
 		})
 	}
 
@@ -252,6 +370,108 @@ func parseGameIDFromPath(path string) (int, error) {
 	return id, nil
 }
 
+// gameActionError rejects a game action with a specific HTTP status
+type gameActionError struct {
+	status  int
+	message string
+}
+
+func (e *gameActionError) Error() string {
+	return e.message
+}
+
+// Create an error that is reported to the client with the given status
+func rejectGameAction(status int, message string) error {
+	return &gameActionError{status: status, message: message}
+}
+
+// Write the response for an error returned from a locked game action
+func writeGameActionError(w http.ResponseWriter, err error, fallback string) {
+	var actionErr *gameActionError
+	if errors.As(err, &actionErr) {
+		util.ErrorResponse(w, actionErr.status, actionErr.message)
+		return
+	}
+
+	log.Printf("%s: %v", fallback, err)
+	util.ErrorResponse(w, http.StatusInternalServerError, fallback)
+}
+
+// Return the state version the client expects, from the If-Match header or the
+// request body. Either may be omitted; if both are given they must agree.
+func parseExpectedVersion(r *http.Request, bodyVersion *int) (*int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return bodyVersion, nil
+	}
+
+	header = strings.TrimPrefix(header, "W/")
+	version, err := strconv.Atoi(strings.Trim(header, `"`))
+	if err != nil {
+		return nil, errors.New("invalid If-Match header")
+	}
+
+	if bodyVersion != nil && *bodyVersion != version {
+		return nil, errors.New("If-Match header does not match expectedVersion")
+	}
+
+	return &version, nil
+}
+
+// Reject the action with 409 Conflict if the state has moved past the expected version
+func checkExpectedVersion(state *repository.GameState, expected *int) error {
+	if expected != nil && *expected != state.Version {
+		return rejectGameAction(http.StatusConflict, fmt.Sprintf("Game state has changed (current version %d)", state.Version))
+	}
+	return nil
+}
+
+// Write a game state response, exposing the state version as an ETag so
+// clients can send it back in If-Match
+func writeGameState(w http.ResponseWriter, game *repository.Game, state *repository.GameState) {
+	writeStateResponse(w, state, gameStateResponse(game, state))
+}
+
+// Write a formatted game state, with the state version as its ETag
+func writeStateResponse(w http.ResponseWriter, state *repository.GameState, response map[string]interface{}) {
+	w.Header().Set("ETag", fmt.Sprintf(`"%d"`, state.Version))
+	util.JSONResponse(w, http.StatusOK, response)
+}
+
+// Format a game state for a response
+func gameStateResponse(game *repository.Game, state *repository.GameState) map[string]interface{} {
+	return map[string]interface{}{
+		"stateId":        state.StateID,
+		"gameId":         state.GameID,
+		"board":          state.BoardState,
+		"barWhite":       state.BarWhite,
+		"barBlack":       state.BarBlack,
+		"bornedOffWhite": state.BornedOffWhite,
+		"bornedOffBlack": state.BornedOffBlack,
+		"handWhite":      state.HandWhite,
+		"handBlack":      state.HandBlack,
+		"pinned":         state.Pinned,
+		"rules":          gameRules(game).Name(),
+		"diceRoll":       state.DiceRoll,
+		"diceUsed":       state.DiceUsed,
+		"bonusStage":     state.BonusStage,
+		"stats":          business.GetPositionStats(gameRules(game), positionFromState(state)),
+		"cube": map[string]interface{}{
+			"value":     state.CubeValue,
+			"owner":     state.CubeOwner,
+			"offeredBy": state.CubeOfferedBy,
+			"beavered":  state.CubeBeavered,
+		},
+		"openingRoll": map[string]interface{}{
+			"player1": state.OpeningDie1,
+			"player2": state.OpeningDie2,
+		},
+		"clock":       clockResponse(game, state, time.Now()),
+		"version":     state.Version,
+		"lastUpdated": state.LastUpdated,
+	}
+}
+
 // ============================================================================
 // Game State Handlers
 // ============================================================================
@@ -304,19 +524,33 @@ func GetGameStateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Format response
-	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
-		"stateId":        state.StateID,
-		"gameId":         state.GameID,
-		"board":          state.BoardState,
-		"barWhite":       state.BarWhite,
-		"barBlack":       state.BarBlack,
-		"bornedOffWhite": state.BornedOffWhite,
-		"bornedOffBlack": state.BornedOffBlack,
-		"diceRoll":       state.DiceRoll,
-		"diceUsed":       state.DiceUsed,
-		"lastUpdated":    state.LastUpdated,
-	})
+	// Get the match score for the Match ID
+	var match *repository.Match
+	if game.MatchID != nil {
+		match, err = db.GetMatchByID(r.Context(), *game.MatchID)
+		if err != nil {
+			log.Printf("Failed to get match: %v", err)
+			util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get match")
+			return
+		}
+	}
+
+	// Format response with the position in GNU Backgammon notation, which
+	// only describes backgammon boards
+	response := gameStateResponse(game, state)
+	response["positionId"] = nil
+	response["gnubgMatchId"] = nil
+	if _, ok := gameRules(game).(business.Backgammon); ok {
+		matchState := gameMatchState(game, state, match)
+		response["positionId"] = business.EncodePositionID(positionFromState(state), matchState.OnRoll)
+		response["gnubgMatchId"], err = business.EncodeMatchID(matchState)
+		if err != nil {
+			log.Printf("Failed to encode match ID: %v", err)
+			response["gnubgMatchId"] = nil
+		}
+	}
+
+	writeStateResponse(w, state, response)
 }
 
 // Roll dice for the current turn
@@ -359,61 +593,134 @@ func RollDiceHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verify it's the user's turn
-	if game.CurrentTurn != userID {
-		util.ErrorResponse(w, http.StatusBadRequest, "Not your turn")
+	// Parse optional request body
+	var req RollRequest
+	if err := util.ParseJSONBody(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	// Verify game is in progress
-	if game.GameStatus != "in_progress" {
-		util.ErrorResponse(w, http.StatusBadRequest, "Game is not in progress")
+	expectedVersion, err := parseExpectedVersion(r, req.ExpectedVersion)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Get game state to check if dice already rolled
-	state, err := db.GetGameState(r.Context(), gameID)
+	// A pending game starts with each player rolling one die
+	if game.GameStatus == "pending" {
+		rollOpeningDie(w, r, db, game, userID, expectedVersion)
+		return
+	}
+
+	// Roll dice while the game is locked so a second request sees the first roll
+	dice, err := db.RollDice(r.Context(), gameID, checkRollDice(userID, expectedVersion))
 	if err != nil {
-		log.Printf("Failed to get game state: %v", err)
-		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get game state")
+		writeGameActionError(w, err, "Failed to roll dice")
 		return
 	}
 
-	// Check if dice already rolled
-	if state.DiceRoll != nil {
-		util.ErrorResponse(w, http.StatusBadRequest, "Dice already rolled for this turn")
+	// Get updated state
+	state, err := db.GetGameState(r.Context(), gameID)
+	if err != nil {
+		log.Printf("Failed to get updated state: %v", err)
+		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get state")
 		return
 	}
 
-	// Roll dice
-	dice, err := db.RollDice(r.Context(), gameID)
+	// Notify both players
+	publishDiceRolled(r.Context(), gameID, userID, dice, state.Version)
+	triggerBots(gameID)
+
+	// Format response
+	writeGameState(w, game, state)
+}
+
+// Return the checks a dice roll must pass against the locked game
+func checkRollDice(userID int, expectedVersion *int) func(game *repository.Game, state *repository.GameState) error {
+	return func(game *repository.Game, state *repository.GameState) error {
+		// Reject rolls requested against an outdated state
+		if err := checkExpectedVersion(state, expectedVersion); err != nil {
+			return err
+		}
+
+		// Verify it's the user's turn
+		if game.CurrentTurn != userID {
+			return rejectGameAction(http.StatusBadRequest, "Not your turn")
+		}
+
+		// Verify game is in progress
+		if game.GameStatus != "in_progress" {
+			return rejectGameAction(http.StatusBadRequest, "Game is not in progress")
+		}
+
+		// Check if dice already rolled
+		if state.DiceRoll != nil {
+			return rejectGameAction(http.StatusBadRequest, "Dice already rolled for this turn")
+		}
+
+		// A pending double must be answered first
+		if state.CubeOfferedBy != nil {
+			return rejectGameAction(http.StatusBadRequest, "Double offer pending")
+		}
+
+		// Verify the player still has time
+		if err := checkClock(game, state, time.Now()); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// Roll the player's opening die for a pending game
+func rollOpeningDie(w http.ResponseWriter, r *http.Request, db *repository.Postgres, game *repository.Game, userID int, expectedVersion *int) {
+	gameID := game.GameID
+	roll, err := db.RollOpeningDie(r.Context(), gameID, userID, checkOpeningRoll(userID, expectedVersion))
 	if err != nil {
-		log.Printf("Failed to roll dice: %v", err)
-		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to roll dice")
+		writeGameActionError(w, err, "Failed to roll opening die")
 		return
 	}
 
 	// Get updated state
-	state, err = db.GetGameState(r.Context(), gameID)
+	state, err := db.GetGameState(r.Context(), gameID)
 	if err != nil {
 		log.Printf("Failed to get updated state: %v", err)
 		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get state")
 		return
 	}
 
+	// Notify both players
+	publishOpeningRoll(r.Context(), gameID, userID, roll, state.Version)
+	triggerBots(gameID)
+
 	// Format response
-	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
-		"stateId":        state.StateID,
-		"gameId":         state.GameID,
-		"board":          state.BoardState,
-		"barWhite":       state.BarWhite,
-		"barBlack":       state.BarBlack,
-		"bornedOffWhite": state.BornedOffWhite,
-		"bornedOffBlack": state.BornedOffBlack,
-		"diceRoll":       dice,
-		"diceUsed":       state.DiceUsed,
-		"lastUpdated":    state.LastUpdated,
-	})
+	writeGameState(w, game, state)
+}
+
+// Return the checks an opening roll must pass against the locked game
+func checkOpeningRoll(userID int, expectedVersion *int) func(game *repository.Game, state *repository.GameState) error {
+	return func(game *repository.Game, state *repository.GameState) error {
+		// Reject rolls requested against an outdated state
+		if err := checkExpectedVersion(state, expectedVersion); err != nil {
+			return err
+		}
+
+		// Verify the opening roll is still under way
+		if game.GameStatus != "pending" {
+			return rejectGameAction(http.StatusBadRequest, "Opening roll already decided")
+		}
+
+		// Each player rolls one die per attempt
+		ownDie := state.OpeningDie1
+		if userID == game.Player2ID {
+			ownDie = state.OpeningDie2
+		}
+		if ownDie != nil {
+			return rejectGameAction(http.StatusBadRequest, "Waiting for opponent's opening roll")
+		}
+
+		return nil
+	}
 }
 
 // Execute a checker move
@@ -463,30 +770,62 @@ func MoveHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	expectedVersion, err := parseExpectedVersion(r, req.ExpectedVersion)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Validate and apply the move against the locked game state
+	var step *repository.TurnStep
+	state, err := db.ApplyTurnStep(r.Context(), gameID, func(game *repository.Game, state *repository.GameState) (*repository.TurnStep, error) {
+		// Reject moves computed against an outdated state
+		if err := checkExpectedVersion(state, expectedVersion); err != nil {
+			return nil, err
+		}
+		built, err := buildMoveStep(game, state, userID, &req)
+		step = built
+		return built, err
+	})
+	if err != nil {
+		writeGameActionError(w, err, "Failed to apply move")
+		return
+	}
+
+	// Notify both players
+	publishTurnStep(r.Context(), game, userID, step, state.Version)
+	if step.WinnerID != 0 {
+		advanceMatch(r.Context(), game)
+	}
+	triggerBots(gameID)
+
+	// Format response
+	writeGameState(w, game, state)
+}
+
+// Validate a move request against the current game state and work out every
+// change it causes: the new board, one move record per die, and the turn switch
+// or win that follows.
+func buildMoveStep(game *repository.Game, state *repository.GameState, userID int, req *MoveRequest) (*repository.TurnStep, error) {
 	// Verify it's the user's turn
 	if game.CurrentTurn != userID {
-		util.ErrorResponse(w, http.StatusBadRequest, "Not your turn")
-		return
+		return nil, rejectGameAction(http.StatusBadRequest, "Not your turn")
 	}
 
 	// Verify game is in progress
 	if game.GameStatus != "in_progress" {
-		util.ErrorResponse(w, http.StatusBadRequest, "Game is not in progress")
-		return
+		return nil, rejectGameAction(http.StatusBadRequest, "Game is not in progress")
 	}
 
-	// Get game state
-	state, err := db.GetGameState(r.Context(), gameID)
-	if err != nil {
-		log.Printf("Failed to get game state: %v", err)
-		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get game state")
-		return
+	// Verify the player still has time
+	now := time.Now()
+	if err := checkClock(game, state, now); err != nil {
+		return nil, err
 	}
 
 	// Check if dice have been rolled
 	if state.DiceRoll == nil || len(state.DiceRoll) < 2 {
-		util.ErrorResponse(w, http.StatusBadRequest, "Dice not rolled yet")
-		return
+		return nil, rejectGameAction(http.StatusBadRequest, "Dice not rolled yet")
 	}
 
 	// Determine player color
@@ -497,12 +836,10 @@ func MoveHandler(w http.ResponseWriter, r *http.Request) {
 		color = business.Color(game.Player2Color)
 	}
 
-	// Determine bar count
-	var barCount int
-	if color == business.ColorWhite {
-		barCount = state.BarWhite
-	} else {
-		barCount = state.BarBlack
+	// A whole play written in standard notation
+	rules := gameRules(game)
+	if req.Notation != "" {
+		return buildNotationStep(game, state, rules, userID, color, req.Notation, now)
 	}
 
 	// Handle combined moves vs single moves
@@ -512,20 +849,17 @@ func MoveHandler(w http.ResponseWriter, r *http.Request) {
 		// Combined move: verify all dice are available and mark them
 		for _, idx := range req.DiceIndices {
 			if idx < 0 || idx >= len(state.DiceUsed) {
-				util.ErrorResponse(w, http.StatusBadRequest, "Invalid dice index")
-				return
+				return nil, rejectGameAction(http.StatusBadRequest, "Invalid dice index")
 			}
 			if state.DiceUsed[idx] {
-				util.ErrorResponse(w, http.StatusBadRequest, "Die already used")
-				return
+				return nil, rejectGameAction(http.StatusBadRequest, "Die already used")
 			}
 		}
 		diceIndicesToMark = req.DiceIndices
 
 		// Validate move coordinates
 		if req.FromPoint < 0 || req.FromPoint > 25 || req.ToPoint < 0 || req.ToPoint > 25 {
-			util.ErrorResponse(w, http.StatusBadRequest, "Invalid point values")
-			return
+			return nil, rejectGameAction(http.StatusBadRequest, "Invalid point values")
 		}
 
 		// For combined moves, DieUsed should be the sum of the dice being used
@@ -535,28 +869,12 @@ func MoveHandler(w http.ResponseWriter, r *http.Request) {
 			expectedSum += state.DiceRoll[idx]
 		}
 		if req.DieUsed != expectedSum {
-			util.ErrorResponse(w, http.StatusBadRequest, "Die value does not match sum of dice")
-			return
-		}
-
-		// Always validate moves server-side, even for combined moves
-		err = business.ValidateMove(state.BoardState, req.FromPoint, req.ToPoint, req.DieUsed, color, barCount)
-		if err != nil {
-			util.ErrorResponse(w, http.StatusBadRequest, err.Error())
-			return
+			return nil, rejectGameAction(http.StatusBadRequest, "Die value does not match sum of dice")
 		}
 	} else {
 		// Single die move: validate die value first
 		if req.DieUsed < 1 || req.DieUsed > 6 {
-			util.ErrorResponse(w, http.StatusBadRequest, "Die value must be between 1 and 6")
-			return
-		}
-
-		// Validate the move
-		err = business.ValidateMove(state.BoardState, req.FromPoint, req.ToPoint, req.DieUsed, color, barCount)
-		if err != nil {
-			util.ErrorResponse(w, http.StatusBadRequest, err.Error())
-			return
+			return nil, rejectGameAction(http.StatusBadRequest, "Die value must be between 1 and 6")
 		}
 
 		// Find which die was used
@@ -568,142 +886,225 @@ func MoveHandler(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 		if dieIndex == -1 {
-			util.ErrorResponse(w, http.StatusBadRequest, "Die not available or already used")
-			return
+			return nil, rejectGameAction(http.StatusBadRequest, "Die not available or already used")
 		}
 		diceIndicesToMark = []int{dieIndex}
 	}
 
-	// Execute the move
-	result, err := business.ExecuteMove(state.BoardState, req.FromPoint, req.ToPoint, color)
+	// Check the move under the game's rules, rejecting moves that would leave a
+	// playable die unused, and split it into single-die steps so every hop (and
+	// hit) is recorded
+	steps, err := rules.PlayMove(positionFromState(state), color, state.DiceRoll, state.DiceUsed, business.LegalMove{
+		FromPoint:      req.FromPoint,
+		ToPoint:        req.ToPoint,
+		DieUsed:        req.DieUsed,
+		DiceIndices:    diceIndicesToMark,
+		IsCombinedMove: len(diceIndicesToMark) > 1,
+	})
 	if err != nil {
-		log.Printf("Failed to execute move: %v", err)
-		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to execute move")
-		return
+		return nil, rejectGameAction(http.StatusBadRequest, err.Error())
+	}
+
+	return applyMoveSteps(game, state, rules, userID, color, steps, diceIndicesToMark, now)
+}
+
+// Validate a whole play written in standard notation against the unused dice
+// and apply its moves
+func buildNotationStep(game *repository.Game, state *repository.GameState, rules business.RuleSet, userID int, color business.Color, notation string, now time.Time) (*repository.TurnStep, error) {
+	play, err := business.ParsePlay(rules, notation, positionFromState(state), color, state.DiceRoll, state.DiceUsed)
+	if err != nil {
+		return nil, rejectGameAction(http.StatusBadRequest, err.Error())
+	}
+
+	// Match each move with an unused die of its value
+	taken := make([]bool, len(state.DiceUsed))
+	copy(taken, state.DiceUsed)
+	diceIndices := []int{}
+	for _, step := range play.Moves {
+		for i, die := range state.DiceRoll {
+			if die == step.DieUsed && !taken[i] {
+				taken[i] = true
+				diceIndices = append(diceIndices, i)
+				break
+			}
+		}
+	}
+
+	return applyMoveSteps(game, state, rules, userID, color, play.Moves, diceIndices, now)
+}
+
+// Make single-die steps for a player, mark their dice used, and work out
+// every change that follows: one move record per step, then the turn switch
+// or win.
+func applyMoveSteps(game *repository.Game, state *repository.GameState, rules business.RuleSet, userID int, color business.Color, steps []business.MoveStep, diceIndices []int, now time.Time) (*repository.TurnStep, error) {
+	// Execute the steps
+	position := positionFromState(state)
+	moves := []repository.Move{}
+	for i := range steps {
+		var err error
+		position, err = rules.ApplyStep(position, &steps[i], color)
+		if err != nil {
+			return nil, err
+		}
+		moves = append(moves, repository.Move{
+			PlayerID:    userID,
+			FromPoint:   steps[i].FromPoint,
+			ToPoint:     steps[i].ToPoint,
+			DieUsed:     steps[i].DieUsed,
+			HitOpponent: steps[i].HitOpponent,
+		})
 	}
 
 	// Update state
-	state.BoardState = result.NewBoard
+	state.BoardState = position.Board
+	state.BarWhite = position.BarWhite
+	state.BarBlack = position.BarBlack
+	state.BornedOffWhite = position.BornedOffWhite
+	state.BornedOffBlack = position.BornedOffBlack
+	state.HandWhite = position.HandWhite
+	state.HandBlack = position.HandBlack
+	state.Pinned = position.Pinned
 
 	// Mark all used dice
-	for _, idx := range diceIndicesToMark {
+	for _, idx := range diceIndices {
 		state.DiceUsed[idx] = true
 	}
 
-	// Update bar/borne-off counts
-	if req.FromPoint == 0 {
-		// Moving from bar
-		if color == business.ColorWhite {
-			state.BarWhite--
-		} else {
-			state.BarBlack--
-		}
+	step := &repository.TurnStep{State: state, Moves: moves}
+
+	// Check for win condition, then if the turn should end (all dice used or no legal moves)
+	if rules.HasWon(position, color) {
+		step.WinnerID = userID
+		step.Result = scoreGame(rules, position, color, state.CubeValue)
+	} else if business.AllDiceUsed(state.DiceUsed) || len(rules.LegalMoves(position, color, state.DiceRoll, state.DiceUsed)) == 0 {
+		endTurn(game, state, rules, step)
 	}
 
-	if req.ToPoint == 25 {
-		// Bearing off
-		if color == business.ColorWhite {
-			state.BornedOffWhite++
-		} else {
-			state.BornedOffBlack++
-		}
+	advanceClock(game, step, now)
+	return step, nil
+}
+
+// Pass the turn when the rolled dice have no legal move
+func PassHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
 	}
 
-	if result.HitOpponent {
-		// Opponent checker sent to bar
-		if color == business.ColorWhite {
-			state.BarBlack++
-		} else {
-			state.BarWhite++
-		}
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
 	}
 
-	// Save updated state
-	err = db.UpdateGameState(r.Context(), state)
-	if err != nil {
-		log.Printf("Failed to update game state: %v", err)
-		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to update state")
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Record the move
-	moveNumber, err := db.GetLastMoveNumber(r.Context(), gameID)
+	// Parse game ID from URL path
+	gameID, err := parseGameIDFromPath(strings.TrimSuffix(r.URL.Path, "/pass"))
 	if err != nil {
-		log.Printf("Failed to get last move number: %v", err)
-	} else {
-		move := &repository.Move{
-			GameID:      gameID,
-			PlayerID:    userID,
-			MoveNumber:  moveNumber + 1,
-			FromPoint:   req.FromPoint,
-			ToPoint:     req.ToPoint,
-			DieUsed:     req.DieUsed,
-			HitOpponent: result.HitOpponent,
-		}
-		_, err = db.CreateMove(r.Context(), move)
-		if err != nil {
-			log.Printf("Failed to record move: %v", err)
-		}
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid game ID")
+		return
 	}
 
-	// Check for win condition
-	var bornedOff int
-	if color == business.ColorWhite {
-		bornedOff = state.BornedOffWhite
-	} else {
-		bornedOff = state.BornedOffBlack
+	// Parse optional request body
+	var req PassRequest
+	if err := util.ParseJSONBody(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
 	}
 
-	if business.CheckWinCondition(bornedOff) {
-		// Player won!
-		err = db.CompleteGame(r.Context(), gameID, userID)
-		if err != nil {
-			log.Printf("Failed to complete game: %v", err)
-		}
-	} else {
-		// Check if turn should end (all dice used or no legal moves)
-		if business.AllDiceUsed(state.DiceUsed) || !business.HasLegalMoves(state.BoardState, color, state.DiceRoll, state.DiceUsed, barCount) {
-			// End turn: switch to other player and clear dice
-			var nextPlayer int
-			if game.CurrentTurn == game.Player1ID {
-				nextPlayer = game.Player2ID
-			} else {
-				nextPlayer = game.Player1ID
-			}
-
-			err = db.UpdateGameTurn(r.Context(), gameID, nextPlayer)
-			if err != nil {
-				log.Printf("Failed to update turn: %v", err)
-			}
+	// Get game details
+	game, err := db.GetGameByID(r.Context(), gameID)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusNotFound, "Game not found")
+		return
+	}
 
-			err = db.ClearDice(r.Context(), gameID)
-			if err != nil {
-				log.Printf("Failed to clear dice: %v", err)
-			}
-		}
+	// Verify user is a player in this game
+	if game.Player1ID != userID && game.Player2ID != userID {
+		util.ErrorResponse(w, http.StatusForbidden, "You are not a player in this game")
+		return
 	}
 
-	// Get updated state
-	state, err = db.GetGameState(r.Context(), gameID)
+	expectedVersion, err := parseExpectedVersion(r, req.ExpectedVersion)
 	if err != nil {
-		log.Printf("Failed to get updated state: %v", err)
-		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get state")
+		util.ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Format response
-	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
-		"stateId":        state.StateID,
-		"gameId":         state.GameID,
-		"board":          state.BoardState,
-		"barWhite":       state.BarWhite,
-		"barBlack":       state.BarBlack,
-		"bornedOffWhite": state.BornedOffWhite,
-		"bornedOffBlack": state.BornedOffBlack,
-		"diceRoll":       state.DiceRoll,
-		"diceUsed":       state.DiceUsed,
-		"lastUpdated":    state.LastUpdated,
+	var step *repository.TurnStep
+	state, err := db.ApplyTurnStep(r.Context(), gameID, func(game *repository.Game, state *repository.GameState) (*repository.TurnStep, error) {
+		if err := checkExpectedVersion(state, expectedVersion); err != nil {
+			return nil, err
+		}
+		built, err := buildPassStep(game, state, userID)
+		step = built
+		return built, err
 	})
+	if err != nil {
+		writeGameActionError(w, err, "Failed to pass")
+		return
+	}
+
+	// Notify both players
+	publishTurnStep(r.Context(), game, userID, step, state.Version)
+	triggerBots(gameID)
+
+	writeGameState(w, game, state)
+}
+
+// Hand the turn to the opponent when the player's roll has no legal move
+func buildPassStep(game *repository.Game, state *repository.GameState, userID int) (*repository.TurnStep, error) {
+	if game.GameStatus != "in_progress" || game.CurrentTurn != userID {
+		return nil, rejectGameAction(http.StatusBadRequest, "Not your turn")
+	}
+	if state.DiceRoll == nil {
+		return nil, rejectGameAction(http.StatusBadRequest, "Dice not rolled yet")
+	}
+
+	if state.BonusStage == repository.BonusChoose {
+		return nil, rejectGameAction(http.StatusBadRequest, "Choose the doubles to play first")
+	}
+
+	rules := gameRules(game)
+	color := playerColor(game, userID)
+	position := positionFromState(state)
+	if len(rules.LegalMoves(position, color, state.DiceRoll, state.DiceUsed)) > 0 {
+		return nil, rejectGameAction(http.StatusBadRequest, "A legal move is available")
+	}
+
+	now := time.Now()
+	if err := checkClock(game, state, now); err != nil {
+		return nil, err
+	}
+
+	step := &repository.TurnStep{State: state}
+	endTurn(game, state, rules, step)
+	advanceClock(game, step, now)
+	return step, nil
+}
+
+// End the turn of the player on roll: switch to the other player and clear the
+// dice. In Acey-Deucey a fully played 1-2 keeps the turn for doubles of the
+// player's choice, and another roll follows those.
+func endTurn(game *repository.Game, state *repository.GameState, rules business.RuleSet, step *repository.TurnStep) {
+	if aceyDeucey, ok := rules.(business.AceyDeucey); ok {
+		switch {
+		case state.BonusStage == repository.BonusDoubles:
+			state.BonusStage = ""
+			step.RollAgain = true
+			return
+		case state.BonusStage == "" && aceyDeucey.EarnsBonus(state.DiceRoll, state.DiceUsed):
+			state.BonusStage = repository.BonusChoose
+			return
+		}
+	}
+	step.NextTurn = opponentOf(game, game.CurrentTurn)
 }
 
 // Return all legal moves for the current position
@@ -770,18 +1171,8 @@ func GetLegalMovesHandler(w http.ResponseWriter, r *http.Request) {
 		color = business.Color(game.Player2Color)
 	}
 
-	// Determine bar and borne-off counts
-	var barCount, bornedOff int
-	if color == business.ColorWhite {
-		barCount = state.BarWhite
-		bornedOff = state.BornedOffWhite
-	} else {
-		barCount = state.BarBlack
-		bornedOff = state.BornedOffBlack
-	}
-
 	// Get legal moves
-	legalMoves := business.GetLegalMoves(state.BoardState, color, state.DiceRoll, state.DiceUsed, barCount, bornedOff)
+	legalMoves := gameRules(game).LegalMoves(positionFromState(state), color, state.DiceRoll, state.DiceUsed)
 
 	// Format response
 	movesList := []map[string]interface{}{}
@@ -799,3 +1190,219 @@ func GetLegalMovesHandler(w http.ResponseWriter, r *http.Request) {
 		"moves": movesList,
 	})
 }
+
+// Return every distinct legal full play for the current dice
+func GetLegalPlaysHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Parse game ID from URL path
+	gameID, err := parseGameIDFromPath(strings.TrimSuffix(r.URL.Path, "/legal-plays"))
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid game ID")
+		return
+	}
+
+	// Get game details
+	game, err := db.GetGameByID(r.Context(), gameID)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	// Verify user is a player in this game
+	if game.Player1ID != userID && game.Player2ID != userID {
+		util.ErrorResponse(w, http.StatusForbidden, "You are not a player in this game")
+		return
+	}
+
+	// Get game state
+	state, err := db.GetGameState(r.Context(), gameID)
+	if err != nil {
+		log.Printf("Failed to get game state: %v", err)
+		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get game state")
+		return
+	}
+
+	// Check if dice have been rolled
+	if state.DiceRoll == nil {
+		util.JSONResponse(w, http.StatusOK, map[string]interface{}{
+			"plays": []interface{}{},
+		})
+		return
+	}
+
+	// Determine player color
+	var color business.Color
+	if game.Player1ID == userID {
+		color = business.Color(game.Player1Color)
+	} else {
+		color = business.Color(game.Player2Color)
+	}
+
+	// Get legal full plays
+	plays := gameRules(game).LegalPlays(positionFromState(state), color, state.DiceRoll, state.DiceUsed)
+
+	// Format response
+	playsList := []map[string]interface{}{}
+	for _, play := range plays {
+		playsList = append(playsList, map[string]interface{}{
+			"moves": play.Moves,
+			"board": play.Result.Board,
+		})
+	}
+
+	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"plays": playsList,
+	})
+}
+
+// Score a finished game under its rules from the final position and the cube
+// value
+func scoreGame(rules business.RuleSet, position business.Position, winner business.Color, cubeValue int) repository.GameResult {
+	resultType := rules.GameResult(position, winner)
+	return repository.GameResult{
+		ResultType: string(resultType),
+		PointsWon:  business.PointsWon(resultType, cubeValue),
+	}
+}
+
+// Return the game's opening roll and move history
+func MoveHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Parse game ID from URL path
+	gameID, err := parseGameIDFromPath(strings.TrimSuffix(r.URL.Path, "/moves"))
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid game ID")
+		return
+	}
+
+	// Get game details
+	game, err := db.GetGameByID(r.Context(), gameID)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	// Verify user is a player in this game
+	if game.Player1ID != userID && game.Player2ID != userID {
+		util.ErrorResponse(w, http.StatusForbidden, "You are not a player in this game")
+		return
+	}
+
+	state, err := db.GetGameState(r.Context(), gameID)
+	if err != nil {
+		log.Printf("Failed to get game state: %v", err)
+		util.ErrorResponse(w, http.StatusNotFound, "Game state not found")
+		return
+	}
+
+	moves, err := db.GetMoveHistory(r.Context(), gameID)
+	if err != nil {
+		log.Printf("Failed to get move history: %v", err)
+		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get move history")
+		return
+	}
+
+	// The opening roll is decided once both dice are in and differ
+	var openingRoll map[string]interface{}
+	if state.OpeningDie1 != nil && state.OpeningDie2 != nil {
+		startingPlayer := game.Player1ID
+		if *state.OpeningDie2 > *state.OpeningDie1 {
+			startingPlayer = game.Player2ID
+		}
+		openingRoll = map[string]interface{}{
+			"player1Die":     *state.OpeningDie1,
+			"player2Die":     *state.OpeningDie2,
+			"startingPlayer": startingPlayer,
+		}
+	}
+
+	// Format move list
+	movesList := []map[string]interface{}{}
+	for _, move := range moves {
+		movesList = append(movesList, map[string]interface{}{
+			"moveNumber":  move.MoveNumber,
+			"playerId":    move.PlayerID,
+			"fromPoint":   move.FromPoint,
+			"toPoint":     move.ToPoint,
+			"dieUsed":     move.DieUsed,
+			"hitOpponent": move.HitOpponent,
+			"timestamp":   move.Timestamp,
+		})
+	}
+
+	// Each turn's play in standard notation
+	playerIDs := map[business.Color]int{
+		business.Color(game.Player1Color): game.Player1ID,
+		business.Color(game.Player2Color): game.Player2ID,
+	}
+	rules := gameRules(game)
+	turnsList := []map[string]interface{}{}
+	for _, turn := range turnRecords(game, moves) {
+		turnsList = append(turnsList, map[string]interface{}{
+			"playerId": playerIDs[turn.Color],
+			"color":    turn.Color,
+			"dice":     turn.Dice,
+			"notation": business.FormatPlay(rules, turn.Moves, turn.Color),
+		})
+	}
+
+	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"gameId":      gameID,
+		"openingRoll": openingRoll,
+		"moves":       movesList,
+		"turns":       turnsList,
+	})
+}
+
+// Build a business position from a stored game state
+func positionFromState(state *repository.GameState) business.Position {
+	return business.Position{
+		Board:          state.BoardState,
+		BarWhite:       state.BarWhite,
+		BarBlack:       state.BarBlack,
+		BornedOffWhite: state.BornedOffWhite,
+		BornedOffBlack: state.BornedOffBlack,
+		HandWhite:      state.HandWhite,
+		HandBlack:      state.HandBlack,
+		Pinned:         state.Pinned,
+	}
+}
+
+// Return the rules a game is played under
+func gameRules(game *repository.Game) business.RuleSet {
+	return business.GameRuleSet(game.RuleSet, game.GameNumber)
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"backgammon/business"
+	"backgammon/repository"
+)
+
+// gameEventHub is the hub game events are broadcast through (nil disables events)
+var gameEventHub *Hub
+
+// SetGameEventHub sets the hub used to broadcast game events to each game's room
+func SetGameEventHub(hub *Hub) {
+	gameEventHub = hub
+}
+
+// BroadcastGameEvent sends a typed game event to every client in the game's room.
+// version is the game state version the change committed, which becomes the
+// event's sequence number; build receives the common event fields and returns
+// the payload to send.
+func (h *Hub) BroadcastGameEvent(ctx context.Context, gameID, version int, eventType string, build func(base GameEventData) interface{}) {
+	roomID, ok := h.gameRoom(ctx, gameID)
+	if !ok {
+		return
+	}
+
+	payload := build(GameEventData{GameID: gameID, Sequence: version})
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Error marshaling %s event data: %v", eventType, err)
+		return
+	}
+
+	msgBytes, err := json.Marshal(WSMessage{
+		Type: eventType,
+		Data: json.RawMessage(payloadJSON),
+	})
+	if err != nil {
+		log.Printf("Error marshaling %s event: %v", eventType, err)
+		return
+	}
+
+	h.broadcast <- &BroadcastMessage{
+		roomID: roomID,
+		data:   msgBytes,
+	}
+}
+
+// gameRoom returns the room game events share with the game's chat
+// connection, looking it up outside the hub lock the first time
+func (h *Hub) gameRoom(ctx context.Context, gameID int) (int, bool) {
+	h.gameMu.Lock()
+	roomID, ok := h.gameRooms[gameID]
+	h.gameMu.Unlock()
+	if ok {
+		return roomID, true
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		return 0, false
+	}
+	roomID, err := db.GetOrCreateGameChatRoom(ctx, gameID)
+	if err != nil {
+		log.Printf("Error getting room for game %d events: %v", gameID, err)
+		return 0, false
+	}
+
+	h.gameMu.Lock()
+	h.gameRooms[gameID] = roomID
+	h.gameMu.Unlock()
+	return roomID, true
+}
+
+// ForgetGame drops the room kept for a finished game's events
+func (h *Hub) ForgetGame(gameID int) {
+	h.gameMu.Lock()
+	delete(h.gameRooms, gameID)
+	h.gameMu.Unlock()
+}
+
+// publishDiceRolled announces a new dice roll
+func publishDiceRolled(ctx context.Context, gameID, playerID int, dice []int, version int) {
+	if gameEventHub == nil {
+		return
+	}
+
+	gameEventHub.BroadcastGameEvent(ctx, gameID, version, "dice_rolled", func(base GameEventData) interface{} {
+		return DiceRolledData{GameEventData: base, PlayerID: playerID, Dice: dice}
+	})
+}
+
+// publishTurnStep announces each checker moved by a turn step, then the turn
+// change or game completion it caused
+func publishTurnStep(ctx context.Context, game *repository.Game, playerID int, step *repository.TurnStep, version int) {
+	if gameEventHub == nil {
+		return
+	}
+
+	for i, move := range step.Moves {
+		gameEventHub.BroadcastGameEvent(ctx, game.GameID, version, "checker_moved", func(base GameEventData) interface{} {
+			data := CheckerMovedData{
+				GameEventData: base,
+				PlayerID:      playerID,
+				FromPoint:     move.FromPoint,
+				ToPoint:       move.ToPoint,
+				DieUsed:       move.DieUsed,
+				Hit:           move.HitOpponent,
+			}
+			if i == len(step.Moves)-1 {
+				stats := business.GetPositionStats(gameRules(game), positionFromState(step.State))
+				data.Stats = &stats
+			}
+			return data
+		})
+	}
+
+	if step.WinnerID != 0 {
+		gameEventHub.BroadcastGameEvent(ctx, game.GameID, version, "game_completed", func(base GameEventData) interface{} {
+			return GameCompletedData{
+				GameEventData: base,
+				WinnerID:      step.WinnerID,
+				ResultType:    step.Result.ResultType,
+				PointsWon:     step.Result.PointsWon,
+			}
+		})
+	} else if step.NextTurn != 0 {
+		gameEventHub.BroadcastGameEvent(ctx, game.GameID, version, "turn_changed", func(base GameEventData) interface{} {
+			return TurnChangedData{GameEventData: base, CurrentTurn: step.NextTurn}
+		})
+	} else if step.RollAgain {
+		// The same player rolls again, so the turn starts afresh
+		gameEventHub.BroadcastGameEvent(ctx, game.GameID, version, "turn_changed", func(base GameEventData) interface{} {
+			return TurnChangedData{GameEventData: base, CurrentTurn: playerID}
+		})
+	}
+}
+
+// publishCubeAction announces a double, take, drop, beaver or raccoon
+func publishCubeAction(ctx context.Context, gameID, playerID int, action string, state *repository.GameState) {
+	if gameEventHub == nil {
+		return
+	}
+
+	gameEventHub.BroadcastGameEvent(ctx, gameID, state.Version, "cube_action", func(base GameEventData) interface{} {
+		return CubeActionData{
+			GameEventData: base,
+			PlayerID:      playerID,
+			Action:        action,
+			CubeValue:     state.CubeValue,
+			CubeOwner:     state.CubeOwner,
+		}
+	})
+}
+
+// publishGameForfeited announces that a player forfeited
+func publishGameForfeited(ctx context.Context, gameID, forfeitedBy, winnerID int, result repository.GameResult, version int) {
+	if gameEventHub == nil {
+		return
+	}
+
+	gameEventHub.BroadcastGameEvent(ctx, gameID, version, "game_forfeited", func(base GameEventData) interface{} {
+		return GameForfeitedData{
+			GameEventData: base,
+			ForfeitedBy:   forfeitedBy,
+			WinnerID:      winnerID,
+			ResultType:    result.ResultType,
+			PointsWon:     result.PointsWon,
+		}
+	})
+}
+
+// publishMatchUpdated announces a match's new score, and the next game when one
+// was created, at the finished game's final version
+func publishMatchUpdated(ctx context.Context, gameID int, match *repository.Match, nextGameID int, version int) {
+	if gameEventHub == nil {
+		return
+	}
+
+	gameEventHub.BroadcastGameEvent(ctx, gameID, version, "match_updated", func(base GameEventData) interface{} {
+		data := MatchUpdatedData{
+			GameEventData: base,
+			MatchID:       match.MatchID,
+			Player1Score:  match.Player1Score,
+			Player2Score:  match.Player2Score,
+			Crawford:      match.Crawford,
+			MatchStatus:   match.MatchStatus,
+			WinnerID:      match.WinnerID,
+		}
+		if nextGameID != 0 {
+			data.NextGameID = &nextGameID
+		}
+		return data
+	})
+}
+
+// publishOpeningRoll announces a player's opening roll die and, once both
+// players have rolled, the tie or the starting player
+func publishOpeningRoll(ctx context.Context, gameID, playerID int, roll *repository.OpeningRoll, version int) {
+	if gameEventHub == nil {
+		return
+	}
+
+	gameEventHub.BroadcastGameEvent(ctx, gameID, version, "opening_roll", func(base GameEventData) interface{} {
+		return OpeningRollData{
+			GameEventData:  base,
+			PlayerID:       playerID,
+			Die:            roll.Die,
+			Tie:            roll.Tie,
+			StartingPlayer: roll.StartingPlayer,
+			Dice:           roll.Dice,
+		}
+	})
+}
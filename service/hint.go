@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backgammon/business"
+	"backgammon/repository"
+	"backgammon/util"
+)
+
+// Number of plays a hint lists by default, and the most it may list
+const (
+	defaultHintPlays = 5
+	maxHintPlays     = 20
+)
+
+// Suggest the best plays for the current roll, or the cube decision before
+// the roll or when answering a double
+func HintHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Parse game ID from URL path
+	gameID, err := parseGameIDFromPath(strings.TrimSuffix(r.URL.Path, "/hint"))
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid game ID")
+		return
+	}
+
+	// Parse how many plays to list
+	count := defaultHintPlays
+	if value := r.URL.Query().Get("count"); value != "" {
+		count, err = strconv.Atoi(value)
+		if err != nil || count < 1 || count > maxHintPlays {
+			util.ErrorResponse(w, http.StatusBadRequest, "count must be between 1 and 20")
+			return
+		}
+	}
+
+	// Get game details
+	game, err := db.GetGameByID(r.Context(), gameID)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	// Verify user is a player in this game
+	if game.Player1ID != userID && game.Player2ID != userID {
+		util.ErrorResponse(w, http.StatusForbidden, "You are not a player in this game")
+		return
+	}
+
+	// Verify hints are allowed in this game
+	if !game.AllowHints {
+		util.ErrorResponse(w, http.StatusForbidden, "Hints are disabled for this game")
+		return
+	}
+
+	// Verify game is in progress
+	if game.GameStatus != "in_progress" {
+		util.ErrorResponse(w, http.StatusBadRequest, "Game is not in progress")
+		return
+	}
+
+	// The evaluator only knows backgammon's board
+	if _, ok := gameRules(game).(business.Backgammon); !ok {
+		util.ErrorResponse(w, http.StatusBadRequest, "Hints are not available for this rule set")
+		return
+	}
+
+	// Get game state
+	state, err := db.GetGameState(r.Context(), gameID)
+	if err != nil {
+		log.Printf("Failed to get game state: %v", err)
+		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get game state")
+		return
+	}
+
+	position := positionFromState(state)
+	response := map[string]interface{}{
+		"gameId":   gameID,
+		"dice":     state.DiceRoll,
+		"diceUsed": state.DiceUsed,
+		"plays":    []map[string]interface{}{},
+		"cube":     nil,
+		"response": nil,
+	}
+
+	switch {
+	case state.CubeOfferedBy != nil && *state.CubeOfferedBy != userID:
+		// Answering a double: judge it from the doubler's side, who is on roll
+		doubler := *state.CubeOfferedBy
+		hint := business.CubeDecision(positionEvaluator, position, playerColor(game, doubler))
+		response["response"] = takeHintResponse(hint)
+
+	case game.CurrentTurn != userID || state.CubeOfferedBy != nil:
+		util.ErrorResponse(w, http.StatusBadRequest, "Hints are only available when you are to act")
+		return
+
+	case state.DiceRoll == nil:
+		// Before the roll the only decision is whether to double
+		err := business.CanOfferDouble(cubeFromState(state), userID, true, false, cubeInPlay(game))
+		if err == nil {
+			response["cube"] = business.CubeDecision(positionEvaluator, position, playerColor(game, userID))
+		}
+
+	default:
+		ranked := business.RankPlays(positionEvaluator, position, playerColor(game, userID), state.DiceRoll, state.DiceUsed)
+		response["plays"] = rankedPlaysResponse(ranked, count)
+	}
+
+	util.JSONResponse(w, http.StatusOK, response)
+}
+
+// Format the answer to a double, judged from the doubler's side
+func takeHintResponse(hint business.CubeHint) map[string]interface{} {
+	action := "pass"
+	if hint.ShouldTake() {
+		action = "take"
+	}
+	return map[string]interface{}{
+		"action":     action,
+		"takeEquity": -hint.DoubleTakeEquity,
+		"passEquity": -hint.DoublePassEquity,
+		"winChance":  1 - hint.Probabilities.Win,
+	}
+}
+
+// Format the best count of the ranked plays
+func rankedPlaysResponse(ranked []business.RankedPlay, count int) []map[string]interface{} {
+	plays := []map[string]interface{}{}
+	for i, play := range ranked {
+		if i == count {
+			break
+		}
+		plays = append(plays, map[string]interface{}{
+			"rank":          i + 1,
+			"moves":         play.Play.Moves,
+			"board":         play.Play.Result.Board,
+			"equity":        play.Equity,
+			"equityLoss":    play.EquityLoss,
+			"probabilities": play.Probabilities,
+		})
+	}
+	return plays
+}
+
+// Refuse analysis of arbitrary positions to a player in an unfinished game
+// without hints, who could otherwise enter that game's position
+func checkAnalysisAllowed(ctx context.Context, db *repository.Postgres, userID int) error {
+	playing, err := db.HasActiveGameWithoutHints(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if playing {
+		return rejectGameAction(http.StatusForbidden, "Position analysis is disabled while you are playing a game without hints")
+	}
+	return nil
+}
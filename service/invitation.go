@@ -1,7 +1,9 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -11,6 +13,9 @@ import (
 	"backgammon/util"
 )
 
+// Longest match that can be played from an invitation
+const maxMatchLength = 25
+
 // Route invitation requests to the appropriate handler
 func InvitationRouterHandler(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
@@ -92,6 +97,16 @@ func handleGetInvitations(w http.ResponseWriter, r *http.Request, db *repository
 			"status":    inv.Status,
 			"gameId":    inv.GameID,
 			"createdAt": inv.CreatedAt,
+			"options": map[string]interface{}{
+				"allowBeavers":   inv.AllowBeavers,
+				"allowHints":     inv.AllowHints,
+				"delaySeconds":   inv.DelaySeconds,
+				"reserveSeconds": inv.ReserveSeconds,
+				"matchLength":    inv.MatchLength,
+				"diceMode":       inv.DiceMode,
+				"variant":        inv.Variant,
+				"ruleSet":        inv.RuleSet,
+			},
 		})
 	}
 
@@ -111,6 +126,16 @@ func handleGetInvitations(w http.ResponseWriter, r *http.Request, db *repository
 			"status":    inv.Status,
 			"gameId":    inv.GameID,
 			"createdAt": inv.CreatedAt,
+			"options": map[string]interface{}{
+				"allowBeavers":   inv.AllowBeavers,
+				"allowHints":     inv.AllowHints,
+				"delaySeconds":   inv.DelaySeconds,
+				"reserveSeconds": inv.ReserveSeconds,
+				"matchLength":    inv.MatchLength,
+				"diceMode":       inv.DiceMode,
+				"variant":        inv.Variant,
+				"ruleSet":        inv.RuleSet,
+			},
 		})
 	}
 
@@ -147,20 +172,67 @@ func handleCreateInvitation(w http.ResponseWriter, r *http.Request, db *reposito
 		return
 	}
 
-	// Verify challenged user is in lobby
-	inLobby, err := db.IsUserInLobby(r.Context(), req.ChallengedID)
+	// Verify challenged user is in lobby; computer players are always available
+	isBot := challengedUser.BotLevel != nil
+	if !isBot {
+		inLobby, err := db.IsUserInLobby(r.Context(), req.ChallengedID)
+		if err != nil {
+			log.Printf("Failed to check if user in lobby: %v", err)
+			util.ErrorResponse(w, http.StatusInternalServerError, "Failed to create invitation")
+			return
+		}
+		if !inLobby {
+			util.ErrorResponse(w, http.StatusNotFound, "Challenged user not in lobby")
+			return
+		}
+	}
+
+	// Validate match length (0 plays a single game)
+	if req.MatchLength < 0 || req.MatchLength > maxMatchLength {
+		util.ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("matchLength must be between 0 and %d", maxMatchLength))
+		return
+	}
+
+	// Validate time control
+	if req.DelaySeconds < 0 || req.DelaySeconds > maxDelaySeconds {
+		util.ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("delaySeconds must be between 0 and %d", maxDelaySeconds))
+		return
+	}
+	if req.ReserveSeconds < 0 || req.ReserveSeconds > maxReserveSeconds {
+		util.ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("reserveSeconds must be between 0 and %d", maxReserveSeconds))
+		return
+	}
+
+	// Seeded dice are for practice, where knowing the rolls is the point
+	diceMode, err := parseDiceMode(req.DiceMode, false)
 	if err != nil {
-		log.Printf("Failed to check if user in lobby: %v", err)
-		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to create invitation")
+		util.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	variant, startingBoard, err := variantStartingBoard(req.Variant, req.StartingBoard)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	if !inLobby {
-		util.ErrorResponse(w, http.StatusNotFound, "Challenged user not in lobby")
+	ruleSet, startingBoard, err := ruleSetStartingBoard(req.RuleSet, variant, startingBoard, req.MatchLength)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Create invitation
-	invitationID, err := db.CreateInvitation(r.Context(), userID, req.ChallengedID)
+	// Create invitation; hints are allowed unless turned off
+	options := repository.GameOptions{
+		AllowBeavers:   req.AllowBeavers,
+		AllowHints:     req.AllowHints == nil || *req.AllowHints,
+		DelaySeconds:   req.DelaySeconds,
+		ReserveSeconds: req.ReserveSeconds,
+		DiceMode:       diceMode,
+		Variant:        string(variant),
+		StartingBoard:  startingBoard,
+		RuleSet:        ruleSet,
+	}
+	invitationID, err := db.CreateInvitation(r.Context(), userID, req.ChallengedID, req.MatchLength, options)
 	if err != nil {
 		if strings.Contains(err.Error(), "pending invitation already exists") {
 			util.ErrorResponse(w, http.StatusConflict, "Pending invitation already exists")
@@ -171,14 +243,47 @@ func handleCreateInvitation(w http.ResponseWriter, r *http.Request, db *reposito
 		return
 	}
 
+	// A computer player accepts straight away
+	if isBot {
+		acceptBotInvitation(w, r, db, invitationID)
+		return
+	}
+
 	util.JSONResponse(w, http.StatusCreated, map[string]interface{}{
 		"invitationId": invitationID,
 		"challengedId": req.ChallengedID,
 		"status":       "pending",
 		"message":      "Invitation sent successfully",
 	})
+}
 
-	_ = challengedUser // Suppress unused variable warning
+// Accept an invitation on behalf of the computer player it was sent to
+func acceptBotInvitation(w http.ResponseWriter, r *http.Request, db *repository.Postgres, invitationID int) {
+	invitation, err := db.GetInvitationByID(r.Context(), invitationID)
+	if err != nil {
+		log.Printf("Failed to get invitation: %v", err)
+		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to create invitation")
+		return
+	}
+
+	gameID, matchID, err := startInvitedGame(r.Context(), db, invitation)
+	if err != nil {
+		log.Printf("Failed to start invited game: %v", err)
+		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to create game")
+		return
+	}
+
+	// The bot rolls its opening die
+	triggerBots(gameID)
+
+	util.JSONResponse(w, http.StatusCreated, map[string]interface{}{
+		"invitationId": invitationID,
+		"challengedId": invitation.ChallengedID,
+		"status":       "accepted",
+		"gameId":       gameID,
+		"matchId":      matchID,
+		"message":      "Invitation accepted by computer player",
+	})
 }
 
 // Handle accepting an invitation
@@ -228,46 +333,54 @@ func AcceptInvitationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create game
-	gameID, err := db.CreateGame(r.Context(), invitation.ChallengerID, invitation.ChallengedID)
+	// Create the game and link it to the invitation
+	gameID, matchID, err := startInvitedGame(r.Context(), db, invitation)
 	if err != nil {
-		log.Printf("Failed to create game: %v", err)
+		log.Printf("Failed to start invited game: %v", err)
 		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to create game")
 		return
 	}
 
-	// Initialize game state (board, pieces, etc.)
-	err = db.InitializeGameState(r.Context(), gameID)
+	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"message": "Invitation accepted",
+		"gameId":  gameID,
+		"matchId": matchID,
+	})
+}
+
+// Create the game an invitation asks for, as the first game of a match when a
+// match length was chosen, and mark the invitation accepted. Both players
+// leave the lobby. Returns the game ID and the match ID, if any.
+func startInvitedGame(ctx context.Context, db *repository.Postgres, invitation *repository.InvitationWithUsers) (int, *int, error) {
+	var gameID int
+	var matchID *int
+	var err error
+	if invitation.MatchLength > 0 {
+		var newMatchID int
+		newMatchID, gameID, err = db.CreateMatch(ctx, invitation.ChallengerID, invitation.ChallengedID, invitation.MatchLength, invitation.GameOptions)
+		matchID = &newMatchID
+	} else {
+		gameID, err = db.CreateGame(ctx, invitation.ChallengerID, invitation.ChallengedID, invitation.GameOptions)
+	}
 	if err != nil {
-		log.Printf("Failed to initialize game state: %v", err)
-		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to initialize game")
-		return
+		return 0, nil, fmt.Errorf("failed to create game: %w", err)
 	}
 
-	// Accept invitation and link to game
-	err = db.AcceptInvitation(r.Context(), invitationID, gameID)
-	if err != nil {
-		log.Printf("Failed to accept invitation: %v", err)
-		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to accept invitation")
-		return
+	// Initialize game state (board, pieces, etc.)
+	if err := db.InitializeGameState(ctx, gameID); err != nil {
+		return 0, nil, fmt.Errorf("failed to initialize game: %w", err)
 	}
 
-	// Auto-start the game immediately
-	err = db.StartGame(r.Context(), gameID)
-	if err != nil {
-		log.Printf("Failed to start game: %v", err)
-		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to start game")
-		return
+	// Accept invitation and link to game
+	if err := db.AcceptInvitation(ctx, invitation.InvitationID, gameID); err != nil {
+		return 0, nil, fmt.Errorf("failed to accept invitation: %w", err)
 	}
 
 	// Remove both users from lobby (they're now in a game)
-	_ = db.LeaveLobby(r.Context(), invitation.ChallengerID)
-	_ = db.LeaveLobby(r.Context(), invitation.ChallengedID)
+	_ = db.LeaveLobby(ctx, invitation.ChallengerID)
+	_ = db.LeaveLobby(ctx, invitation.ChallengedID)
 
-	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
-		"message": "Invitation accepted",
-		"gameId":  gameID,
-	})
+	return gameID, matchID, nil
 }
 
 // Handle declining an invitation
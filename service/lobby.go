@@ -45,10 +45,27 @@ func LobbyUsersHandler(w http.ResponseWriter, r *http.Request) {
 				"username":      user.Username,
 				"joinedAt":      user.JoinedAt,
 				"lastHeartbeat": user.LastHeartbeat,
+				"isBot":         false,
 			})
 		}
 	}
 
+	// Computer players are always available to play
+	bots, err := db.GetBotUsers(r.Context())
+	if err != nil {
+		log.Printf("Failed to get bot users: %v", err)
+		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get lobby users")
+		return
+	}
+	for _, bot := range bots {
+		filteredUsers = append(filteredUsers, map[string]interface{}{
+			"userId":   bot.UserID,
+			"username": bot.Username,
+			"isBot":    true,
+			"botLevel": bot.Level,
+		})
+	}
+
 	// Handle nil slice
 	if filteredUsers == nil {
 		filteredUsers = []map[string]interface{}{}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backgammon/business"
+	"backgammon/repository"
+	"backgammon/util"
+)
+
+// Retrieve a match with its score and games
+func MatchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Parse match ID from URL path: /api/v1/matches/{id}
+	matchID, err := parseMatchIDFromPath(r.URL.Path)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid match ID")
+		return
+	}
+
+	match, err := db.GetMatchByID(r.Context(), matchID)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusNotFound, "Match not found")
+		return
+	}
+
+	// Verify user is a player in this match
+	if match.Player1ID != userID && match.Player2ID != userID {
+		util.ErrorResponse(w, http.StatusForbidden, "You are not a player in this match")
+		return
+	}
+
+	games, err := db.GetMatchGames(r.Context(), matchID)
+	if err != nil {
+		log.Printf("Failed to get match games: %v", err)
+		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get match games")
+		return
+	}
+
+	// Format game list
+	gamesList := []map[string]interface{}{}
+	for _, game := range games {
+		gamesList = append(gamesList, map[string]interface{}{
+			"gameId":     game.GameID,
+			"gameNumber": game.GameNumber,
+			"gameStatus": game.GameStatus,
+			"crawford":   game.Crawford,
+			"winnerId":   game.WinnerID,
+			"resultType": game.ResultType,
+			"pointsWon":  game.PointsWon,
+			"startedAt":  game.StartedAt,
+			"endedAt":    game.EndedAt,
+		})
+	}
+
+	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"matchId":     match.MatchID,
+		"matchLength": match.MatchLength,
+		"player1": map[string]interface{}{
+			"userId": match.Player1ID,
+			"score":  match.Player1Score,
+		},
+		"player2": map[string]interface{}{
+			"userId": match.Player2ID,
+			"score":  match.Player2Score,
+		},
+		"crawford":     match.Crawford,
+		"postCrawford": match.PostCrawford,
+		"matchStatus":  match.MatchStatus,
+		"winnerId":     match.WinnerID,
+		"createdAt":    match.CreatedAt,
+		"endedAt":      match.EndedAt,
+		"games":        gamesList,
+	})
+}
+
+// Extract the match ID from the URL path
+func parseMatchIDFromPath(path string) (int, error) {
+	trimmed := strings.TrimPrefix(path, "/api/v1/matches/")
+
+	id, err := strconv.Atoi(trimmed)
+	if err != nil {
+		return 0, err
+	}
+
+	if id <= 0 {
+		return 0, errors.New("match ID must be a positive integer")
+	}
+
+	return id, nil
+}
+
+// Update the score of the match a finished game belongs to and create its next
+// game, then notify both players and let a computer player roll for it. Games
+// outside a match are ignored. A match left behind when this fails is picked up
+// by SweepStalledMatches.
+func advanceMatch(ctx context.Context, game *repository.Game) {
+	// No more events follow once a finished game's match has moved on
+	if gameEventHub != nil {
+		defer gameEventHub.ForgetGame(game.GameID)
+	}
+
+	if game.MatchID == nil {
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		return
+	}
+
+	var updated repository.Match
+	advanced := false
+	nextGameID, err := db.AdvanceMatch(ctx, *game.MatchID, func(match *repository.Match, games []repository.Game, next *repository.GameOptions) (bool, error) {
+		startNext, err := scoreMatch(match, games)
+		updated = *match
+		advanced = startNext || match.MatchStatus != "in_progress"
+
+		// Each game of a tavli match starts from the layout of its own rules
+		if next.RuleSet == business.RuleSetTavli {
+			gameNumber := games[len(games)-1].GameNumber + 1
+			next.StartingBoard = business.GameRuleSet(next.RuleSet, gameNumber).StartingPosition().Board
+		}
+		return startNext, err
+	})
+	if err != nil {
+		log.Printf("Failed to advance match %d: %v", *game.MatchID, err)
+		return
+	}
+
+	// Another call already advanced the match past this game
+	if !advanced {
+		return
+	}
+
+	state, err := db.GetGameState(ctx, game.GameID)
+	if err != nil {
+		log.Printf("Failed to get game state: %v", err)
+	} else {
+		publishMatchUpdated(ctx, game.GameID, &updated, nextGameID, state.Version)
+	}
+	if nextGameID != 0 {
+		triggerBots(nextGameID)
+	}
+}
+
+// Advance every match whose last game finished without the match moving on,
+// as when the server stopped or advancing failed right after the game ended
+func SweepStalledMatches(ctx context.Context) {
+	db := repository.GetDB()
+	if db == nil {
+		return
+	}
+
+	gameIDs, err := db.GetStalledMatchGames(ctx)
+	if err != nil {
+		log.Printf("Failed to get stalled matches: %v", err)
+		return
+	}
+
+	for _, gameID := range gameIDs {
+		game, err := db.GetGameByID(ctx, gameID)
+		if err != nil {
+			log.Printf("Failed to get game %d of stalled match: %v", gameID, err)
+			continue
+		}
+		advanceMatch(ctx, game)
+	}
+}
+
+// Replay the finished games of a match to work out its score and Crawford
+// state. A forfeited game concedes the match. Returns whether another game
+// should be played.
+func scoreMatch(match *repository.Match, games []repository.Game) (bool, error) {
+	score := business.NewMatchScore(match.MatchLength, match.Player1ID, match.Player2ID)
+
+	for _, game := range games {
+		if game.GameStatus != "completed" && game.GameStatus != "abandoned" {
+			// The current game is still being played
+			return false, nil
+		}
+		if game.WinnerID == nil {
+			continue
+		}
+
+		points := 1
+		if game.PointsWon != nil {
+			points = *game.PointsWon
+		}
+
+		var err error
+		score, err = score.AddGame(*game.WinnerID, points)
+		if err != nil {
+			return false, err
+		}
+
+		if game.GameStatus == "abandoned" {
+			winnerID := *game.WinnerID
+			applyMatchScore(match, score)
+			match.MatchStatus = "abandoned"
+			match.WinnerID = &winnerID
+			return false, nil
+		}
+		if score.Winner() != 0 {
+			break
+		}
+	}
+
+	applyMatchScore(match, score)
+	if winnerID := score.Winner(); winnerID != 0 {
+		match.MatchStatus = "completed"
+		match.WinnerID = &winnerID
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Copy a business match score onto a stored match
+func applyMatchScore(match *repository.Match, score business.MatchScore) {
+	match.Player1Score = score.Player1Score
+	match.Player2Score = score.Player2Score
+	match.Crawford = score.Crawford
+	match.PostCrawford = score.PostCrawford
+}
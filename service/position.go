@@ -0,0 +1,194 @@
+package service
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"backgammon/business"
+	"backgammon/repository"
+	"backgammon/util"
+)
+
+// Describe a game's cube, dice and score as a GNU Backgammon Match ID records
+// them. Single games are recorded as money games.
+func gameMatchState(game *repository.Game, state *repository.GameState, match *repository.Match) business.MatchState {
+	onRoll := playerColor(game, game.CurrentTurn)
+	m := business.MatchState{
+		CubeValue:     state.CubeValue,
+		OnRoll:        onRoll,
+		Turn:          onRoll,
+		Crawford:      game.Crawford,
+		DoubleOffered: state.CubeOfferedBy != nil,
+	}
+
+	if state.CubeOwner != nil {
+		m.CubeOwner = playerColor(game, *state.CubeOwner)
+	}
+	if m.DoubleOffered {
+		m.Turn = onRoll.Opponent()
+	}
+	// Neither ID can describe a partly played roll, so dice are only given
+	// while all of them are still to play
+	if len(state.DiceRoll) >= 2 && !slices.Contains(state.DiceUsed, true) {
+		m.Dice = [2]int{state.DiceRoll[0], state.DiceRoll[1]}
+	}
+
+	switch game.GameStatus {
+	case "in_progress":
+		m.GameState = business.MatchPlaying
+	case "completed", "abandoned":
+		m.GameState = business.MatchGameOver
+	default:
+		m.GameState = business.MatchNoGame
+	}
+
+	if match != nil {
+		m.MatchLength = match.MatchLength
+		player1Color := business.Color(game.Player1Color)
+		if player1Color == business.ColorWhite {
+			m.WhiteScore, m.BlackScore = match.Player1Score, match.Player2Score
+		} else {
+			m.WhiteScore, m.BlackScore = match.Player2Score, match.Player1Score
+		}
+	}
+	return m
+}
+
+// Show an arbitrary position given as a GNU Backgammon Position ID, with an
+// optional Match ID for the cube, dice and score, and suggest the best plays
+// or cube action for the player on roll
+func PositionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Players in a game without hints could enter its position here
+	if err := checkAnalysisAllowed(r.Context(), db, userID); err != nil {
+		writeGameActionError(w, err, "Failed to analyze position")
+		return
+	}
+
+	query := r.URL.Query()
+	positionID := query.Get("positionId")
+	if positionID == "" {
+		util.ErrorResponse(w, http.StatusBadRequest, "positionId is required")
+		return
+	}
+
+	// Without a Match ID, white is on roll in a money game before rolling
+	m := business.MatchState{
+		CubeValue: 1,
+		OnRoll:    business.ColorWhite,
+		Turn:      business.ColorWhite,
+		GameState: business.MatchPlaying,
+	}
+	if matchID := query.Get("matchId"); matchID != "" {
+		var err error
+		m, err = business.DecodeMatchID(matchID)
+		if err != nil {
+			util.ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// Parse how many plays to list
+	count := defaultHintPlays
+	if value := query.Get("count"); value != "" {
+		var err error
+		count, err = strconv.Atoi(value)
+		if err != nil || count < 1 || count > maxHintPlays {
+			util.ErrorResponse(w, http.StatusBadRequest, "count must be between 1 and 20")
+			return
+		}
+	}
+
+	position, err := business.DecodePositionID(positionID, m.OnRoll)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Normalize both IDs so clients can compare them
+	matchID, err := business.EncodeMatchID(m)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var dice []int
+	var diceUsed []bool
+	if m.Dice[0] != 0 {
+		dice = business.TurnDice(m.Dice[0], m.Dice[1])
+		diceUsed = make([]bool, len(dice))
+	}
+
+	var cubeOwner interface{}
+	if m.CubeOwner != "" {
+		cubeOwner = m.CubeOwner
+	}
+	response := map[string]interface{}{
+		"positionId":     business.EncodePositionID(position, m.OnRoll),
+		"matchId":        matchID,
+		"board":          position.Board,
+		"barWhite":       position.BarWhite,
+		"barBlack":       position.BarBlack,
+		"bornedOffWhite": position.BornedOffWhite,
+		"bornedOffBlack": position.BornedOffBlack,
+		"onRoll":         m.OnRoll,
+		"turn":           m.Turn,
+		"diceRoll":       dice,
+		"stats":          business.GetPositionStats(business.Backgammon{}, position),
+		"cube": map[string]interface{}{
+			"value":   m.CubeValue,
+			"owner":   cubeOwner,
+			"offered": m.DoubleOffered,
+		},
+		"match": map[string]interface{}{
+			"length":     m.MatchLength,
+			"whiteScore": m.WhiteScore,
+			"blackScore": m.BlackScore,
+			"crawford":   m.Crawford,
+		},
+		"plays":    []map[string]interface{}{},
+		"cubeHint": nil,
+		"response": nil,
+	}
+
+	// A finished position has nothing left to decide
+	if position.BornedOffWhite == 15 || position.BornedOffBlack == 15 {
+		util.JSONResponse(w, http.StatusOK, response)
+		return
+	}
+
+	switch {
+	case m.DoubleOffered:
+		hint := business.CubeDecision(positionEvaluator, position, m.OnRoll)
+		response["response"] = takeHintResponse(hint)
+
+	case dice == nil:
+		if !m.Crawford && (m.CubeOwner == "" || m.CubeOwner == m.OnRoll) {
+			response["cubeHint"] = business.CubeDecision(positionEvaluator, position, m.OnRoll)
+		}
+
+	default:
+		ranked := business.RankPlays(positionEvaluator, position, m.OnRoll, dice, diceUsed)
+		response["plays"] = rankedPlaysResponse(ranked, count)
+	}
+
+	util.JSONResponse(w, http.StatusOK, response)
+}
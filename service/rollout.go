@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backgammon/business"
+	"backgammon/repository"
+	"backgammon/util"
+)
+
+// Rollout limits and defaults
+const (
+	defaultRolloutTrials     = 1296
+	maxRolloutTrials         = 50000
+	maxRolloutTruncation     = 200
+	defaultRolloutCandidates = 3
+	maxRolloutCandidates     = 10
+	maxActiveRollouts        = 3 // Queued or running rollouts per user
+	rolloutProgressInterval  = 2 * time.Second
+)
+
+// Route rollout requests
+func RolloutRouterHandler(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	// /api/v1/rollouts - POST
+	if path == "/api/v1/rollouts" && r.Method == http.MethodPost {
+		CreateRolloutHandler(w, r)
+		return
+	}
+
+	// /api/v1/rollouts/{id} - GET
+	if strings.HasPrefix(path, "/api/v1/rollouts/") && r.Method == http.MethodGet {
+		GetRolloutHandler(w, r)
+		return
+	}
+
+	util.ErrorResponse(w, http.StatusNotFound, "Endpoint not found")
+}
+
+// Queue a rollout of a game's current position or of a Position ID
+func CreateRolloutHandler(w http.ResponseWriter, r *http.Request) {
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req RolloutRequest
+	if err := util.ParseJSONBody(r, &req); err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rollout, err := rolloutFromRequest(r.Context(), db, userID, &req)
+	if err != nil {
+		writeGameActionError(w, err, "Failed to create rollout")
+		return
+	}
+
+	rolloutID, err := db.CreateRollout(r.Context(), rollout, func(active int) error {
+		if active >= maxActiveRollouts {
+			return rejectGameAction(http.StatusTooManyRequests, fmt.Sprintf("At most %d rollouts may be queued at once", maxActiveRollouts))
+		}
+		return nil
+	})
+	if err != nil {
+		writeGameActionError(w, err, "Failed to create rollout")
+		return
+	}
+
+	util.JSONResponse(w, http.StatusAccepted, map[string]interface{}{
+		"rolloutId": rolloutID,
+		"status":    "queued",
+		"seed":      rollout.Seed,
+	})
+}
+
+// Validate a rollout request and build the rollout to queue
+func rolloutFromRequest(ctx context.Context, db *repository.Postgres, userID int, req *RolloutRequest) (*repository.Rollout, error) {
+	rollout := &repository.Rollout{
+		UserID:            userID,
+		Trials:            defaultRolloutTrials,
+		Truncation:        req.Truncation,
+		BotLevel:          string(business.BotHard),
+		VarianceReduction: req.VarianceReduction,
+		Candidates:        defaultRolloutCandidates,
+	}
+
+	if req.Trials != 0 {
+		rollout.Trials = req.Trials
+	}
+	if rollout.Trials < 1 || rollout.Trials > maxRolloutTrials {
+		return nil, rejectGameAction(http.StatusBadRequest, fmt.Sprintf("trials must be between 1 and %d", maxRolloutTrials))
+	}
+	if rollout.Truncation < 0 || rollout.Truncation > maxRolloutTruncation {
+		return nil, rejectGameAction(http.StatusBadRequest, fmt.Sprintf("truncation must be between 0 and %d", maxRolloutTruncation))
+	}
+	if req.Candidates != 0 {
+		rollout.Candidates = req.Candidates
+	}
+	if rollout.Candidates < 1 || rollout.Candidates > maxRolloutCandidates {
+		return nil, rejectGameAction(http.StatusBadRequest, fmt.Sprintf("candidates must be between 1 and %d", maxRolloutCandidates))
+	}
+	if req.Level != "" {
+		level, err := business.ParseBotLevel(req.Level)
+		if err != nil {
+			return nil, rejectGameAction(http.StatusBadRequest, err.Error())
+		}
+		rollout.BotLevel = string(level)
+	}
+	if req.Seed != nil {
+		rollout.Seed = *req.Seed
+	} else {
+		rollout.Seed = rand.Int64()
+	}
+
+	var pos business.Position
+	var onRoll business.Color
+	switch {
+	case req.GameID != nil && req.PositionID != "":
+		return nil, rejectGameAction(http.StatusBadRequest, "Give either gameId or positionId, not both")
+
+	case req.GameID != nil:
+		game, err := db.GetGameByID(ctx, *req.GameID)
+		if err != nil {
+			return nil, rejectGameAction(http.StatusNotFound, "Game not found")
+		}
+		if game.Player1ID != userID && game.Player2ID != userID {
+			return nil, rejectGameAction(http.StatusForbidden, "You are not a player in this game")
+		}
+		if game.GameStatus != "in_progress" {
+			return nil, rejectGameAction(http.StatusBadRequest, "Game is not in progress")
+		}
+		if !game.AllowHints {
+			return nil, rejectGameAction(http.StatusForbidden, "Hints are disabled for this game")
+		}
+		if _, ok := gameRules(game).(business.Backgammon); !ok {
+			return nil, rejectGameAction(http.StatusBadRequest, "Rollouts are not available for this rule set")
+		}
+		if req.Dice != nil {
+			return nil, rejectGameAction(http.StatusBadRequest, "Dice are taken from the game")
+		}
+
+		state, err := db.GetGameState(ctx, game.GameID)
+		if err != nil {
+			return nil, err
+		}
+		pos = positionFromState(state)
+		onRoll = playerColor(game, game.CurrentTurn)
+		rollout.GameID = &game.GameID
+		rollout.DiceRoll = state.DiceRoll
+		rollout.DiceUsed = state.DiceUsed
+
+	case req.PositionID != "":
+		if err := checkAnalysisAllowed(ctx, db, userID); err != nil {
+			return nil, err
+		}
+
+		// Position IDs do not record colors, so white is on roll
+		var err error
+		onRoll = business.ColorWhite
+		pos, err = business.DecodePositionID(req.PositionID, onRoll)
+		if err != nil {
+			return nil, rejectGameAction(http.StatusBadRequest, err.Error())
+		}
+		if req.Dice != nil {
+			if len(req.Dice) != 2 || req.Dice[0] < 1 || req.Dice[0] > 6 || req.Dice[1] < 1 || req.Dice[1] > 6 {
+				return nil, rejectGameAction(http.StatusBadRequest, "dice must be two values between 1 and 6")
+			}
+			rollout.DiceRoll = business.TurnDice(req.Dice[0], req.Dice[1])
+			rollout.DiceUsed = make([]bool, len(rollout.DiceRoll))
+		}
+
+	default:
+		return nil, rejectGameAction(http.StatusBadRequest, "gameId or positionId is required")
+	}
+
+	if pos.BornedOffWhite == 15 || pos.BornedOffBlack == 15 {
+		return nil, rejectGameAction(http.StatusBadRequest, "The game is already over in this position")
+	}
+	if rollout.DiceRoll == nil {
+		rollout.Candidates = 1
+	}
+
+	rollout.BoardState = pos.Board
+	rollout.BarWhite = pos.BarWhite
+	rollout.BarBlack = pos.BarBlack
+	rollout.BornedOffWhite = pos.BornedOffWhite
+	rollout.BornedOffBlack = pos.BornedOffBlack
+	rollout.OnRoll = string(onRoll)
+	return rollout, nil
+}
+
+// Report a rollout's progress, and its result once completed
+func GetRolloutHandler(w http.ResponseWriter, r *http.Request) {
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Parse rollout ID from URL path: /api/v1/rollouts/{id}
+	rolloutID, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/v1/rollouts/"))
+	if err != nil || rolloutID <= 0 {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid rollout ID")
+		return
+	}
+
+	rollout, err := db.GetRollout(r.Context(), rolloutID)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusNotFound, "Rollout not found")
+		return
+	}
+
+	// Rollouts are private to the user who requested them
+	if rollout.UserID != userID {
+		util.ErrorResponse(w, http.StatusNotFound, "Rollout not found")
+		return
+	}
+
+	pos := positionFromRollout(rollout)
+	onRoll := business.Color(rollout.OnRoll)
+	totalTrials := rollout.Trials
+	if rollout.DiceRoll != nil {
+		plays := business.GetLegalPlays(pos, onRoll, rollout.DiceRoll, rollout.DiceUsed)
+		totalTrials *= max(min(rollout.Candidates, len(plays)), 1)
+	}
+
+	var result interface{}
+	if rollout.Result != nil {
+		result = rollout.Result
+	}
+
+	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"rolloutId":  rollout.RolloutID,
+		"status":     rollout.Status,
+		"gameId":     rollout.GameID,
+		"positionId": business.EncodePositionID(pos, onRoll),
+		"onRoll":     rollout.OnRoll,
+		"dice":       rollout.DiceRoll,
+		"diceUsed":   rollout.DiceUsed,
+		"options": map[string]interface{}{
+			"trials":            rollout.Trials,
+			"truncation":        rollout.Truncation,
+			"level":             rollout.BotLevel,
+			"varianceReduction": rollout.VarianceReduction,
+			"seed":              rollout.Seed,
+			"candidates":        rollout.Candidates,
+		},
+		"progress": map[string]interface{}{
+			"trialsDone":  rollout.TrialsDone,
+			"totalTrials": totalTrials,
+		},
+		"result":      result,
+		"error":       rollout.ErrorMessage,
+		"createdAt":   rollout.CreatedAt,
+		"startedAt":   rollout.StartedAt,
+		"completedAt": rollout.CompletedAt,
+	})
+}
+
+// Run queued rollouts one at a time until the queue is empty
+func RunQueuedRollouts(ctx context.Context) {
+	db := repository.GetDB()
+	if db == nil {
+		return
+	}
+
+	for {
+		rollout, err := db.ClaimNextRollout(ctx)
+		if err != nil {
+			log.Printf("Failed to claim rollout: %v", err)
+			return
+		}
+		if rollout == nil {
+			return
+		}
+
+		start := time.Now()
+		trials, result, err := runRollout(ctx, db, rollout)
+		if err != nil {
+			log.Printf("Rollout %d failed: %v", rollout.RolloutID, err)
+			if err := db.FailRollout(ctx, rollout.RolloutID, err.Error()); err != nil {
+				log.Printf("Failed to record rollout failure: %v", err)
+			}
+			continue
+		}
+
+		if err := db.CompleteRollout(ctx, rollout.RolloutID, trials, result); err != nil {
+			log.Printf("Failed to store rollout %d: %v", rollout.RolloutID, err)
+			continue
+		}
+		log.Printf("Rollout %d completed %d trials in %s", rollout.RolloutID, trials, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+// Play out a claimed rollout, recording progress as it goes, and return the
+// number of trials played and the encoded result
+func runRollout(ctx context.Context, db *repository.Postgres, rollout *repository.Rollout) (int, []byte, error) {
+	trialsDone := 0
+	lastReport := time.Now()
+	opts := business.RolloutOptions{
+		Trials:            rollout.Trials,
+		Truncation:        rollout.Truncation,
+		Level:             business.BotLevel(rollout.BotLevel),
+		VarianceReduction: rollout.VarianceReduction,
+		Seed:              uint64(rollout.Seed),
+		Progress: func(trials int) {
+			trialsDone = trials
+			if time.Since(lastReport) < rolloutProgressInterval {
+				return
+			}
+			lastReport = time.Now()
+			if err := db.UpdateRolloutProgress(ctx, rollout.RolloutID, trials); err != nil {
+				log.Printf("Failed to update rollout progress: %v", err)
+			}
+		},
+	}
+
+	pos := positionFromRollout(rollout)
+	onRoll := business.Color(rollout.OnRoll)
+
+	var result map[string]interface{}
+	if rollout.DiceRoll == nil {
+		position, err := business.RolloutBeforeRoll(ctx, positionEvaluator, pos, onRoll, opts)
+		if err != nil {
+			return 0, nil, err
+		}
+		result = map[string]interface{}{"position": position}
+	} else {
+		plays, err := business.RolloutPlays(ctx, positionEvaluator, pos, onRoll, rollout.DiceRoll, rollout.DiceUsed, rollout.Candidates, opts)
+		if err != nil {
+			return 0, nil, err
+		}
+		if len(plays) == 0 {
+			return 0, nil, errors.New("no legal plays for the dice")
+		}
+		result = map[string]interface{}{"plays": plays}
+	}
+	result["evaluator"] = evaluatorName()
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to encode rollout result: %w", err)
+	}
+	return trialsDone, encoded, nil
+}
+
+// Build a business position from a stored rollout
+func positionFromRollout(rollout *repository.Rollout) business.Position {
+	return business.Position{
+		Board:          rollout.BoardState,
+		BarWhite:       rollout.BarWhite,
+		BarBlack:       rollout.BarBlack,
+		BornedOffWhite: rollout.BornedOffWhite,
+		BornedOffBlack: rollout.BornedOffBlack,
+	}
+}
@@ -1,6 +1,10 @@
 package service
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"backgammon/business"
+)
 
 // ============================================================================
 // Auth & User Types
@@ -32,6 +36,53 @@ type MoveRequest struct {
 	DieUsed        int   `json:"dieUsed"`
 	DiceIndices    []int `json:"diceIndices"`    // Indices of dice being used (for combined moves)
 	IsCombinedMove bool  `json:"isCombinedMove"` // True if using multiple dice
+	// The whole play in standard notation such as "8/5 6/5", instead of one move
+	Notation string `json:"notation,omitempty"`
+	// State version the move was computed against (alternative to If-Match)
+	ExpectedVersion *int `json:"expectedVersion,omitempty"`
+}
+
+type RollRequest struct {
+	// State version the roll was requested against (alternative to If-Match)
+	ExpectedVersion *int `json:"expectedVersion,omitempty"`
+}
+
+// PassRequest gives up a roll that has no legal move
+type PassRequest struct {
+	// State version the pass was requested against (alternative to If-Match)
+	ExpectedVersion *int `json:"expectedVersion,omitempty"`
+}
+
+// ChooseDoublesRequest picks the number an Acey-Deucey 1-2 is followed by
+type ChooseDoublesRequest struct {
+	Value int `json:"value"` // 1 to 6, played four times
+	// State version the choice was made against (alternative to If-Match)
+	ExpectedVersion *int `json:"expectedVersion,omitempty"`
+}
+
+// RolloutRequest asks for a rollout of either a game's current position or a
+// GNU Backgammon Position ID. With dice, the best candidate plays are rolled
+// out; without, the position before the roll.
+type RolloutRequest struct {
+	GameID            *int   `json:"gameId"`
+	PositionID        string `json:"positionId"` // White is on roll
+	Dice              []int  `json:"dice"`       // Roll to play, for position IDs only
+	Trials            int    `json:"trials"`     // Games played out per candidate (default 1296)
+	Truncation        int    `json:"truncation"` // Plies before the evaluator judges, 0 plays to the end
+	Level             string `json:"level"`      // Bot level both sides play (default hard)
+	VarianceReduction bool   `json:"varianceReduction"`
+	Seed              *int64 `json:"seed"`       // Random when omitted
+	Candidates        int    `json:"candidates"` // Plays rolled out when dice are given (default 3)
+}
+
+// PracticeGameRequest starts a game against a computer player from an XGID
+type PracticeGameRequest struct {
+	XGID  string `json:"xgid"`
+	Level string `json:"level"` // Bot level of the opponent (default medium)
+	Color string `json:"color"` // Color to play, "white" for XGID's player X (default white)
+	// "random" (default), "seeded" to replay the dice of a seed, or "fair"
+	DiceMode string `json:"diceMode"`
+	Seed     *int64 `json:"seed"` // Seed for seeded dice, random when omitted
 }
 
 // ============================================================================
@@ -39,7 +90,20 @@ type MoveRequest struct {
 // ============================================================================
 
 type CreateInvitationRequest struct {
-	ChallengedID int `json:"challengedId"`
+	ChallengedID int   `json:"challengedId"`
+	AllowBeavers bool  `json:"allowBeavers"` // Allow beavers and raccoons on doubles
+	AllowHints   *bool `json:"allowHints"`   // Allow hints during the game (default true)
+	MatchLength  int   `json:"matchLength"`  // Points to play to; 0 plays a single game
+	// Time control: seconds per turn before the reserve runs, and reserve per player (both 0 for untimed)
+	DelaySeconds   int `json:"delaySeconds"`
+	ReserveSeconds int `json:"reserveSeconds"`
+	// "random" (default), or "fair" to commit to the dice seed up front and reveal it at the end
+	DiceMode string `json:"diceMode"`
+	// "standard" (default), "nackgammon", "hypergammon", "longgammon", or "custom" with a starting board
+	Variant       string `json:"variant"`
+	StartingBoard []int  `json:"startingBoard,omitempty"` // 24 points, positive for white, for custom games only
+	// "backgammon" (default), "portes", "plakoto", "fevga", "aceydeucey", or "tavli" for a match rotating the Tavli games
+	RuleSet string `json:"ruleSet"`
 }
 
 // ============================================================================
@@ -47,7 +111,7 @@ type CreateInvitationRequest struct {
 // ============================================================================
 
 type WSMessage struct {
-	Type string          `json:"type"` // "send_message", "chat_message", "history", "user_joined", "user_left", "error"
+	Type string          `json:"type"` // "send_message", "chat_message", "history", "user_joined", "user_left", "error", or a game event type
 	Data json.RawMessage `json:"data"`
 }
 
@@ -75,3 +139,81 @@ type UserEventData struct {
 type ErrorData struct {
 	Message string `json:"message"`
 }
+
+// ============================================================================
+// Game Event Types
+// ============================================================================
+
+// GameEventData is common to every game event. Sequence is the game state
+// version the change committed, shared by every event of one change; each
+// change raises it by one, so a jump means changes were missed and the client
+// should resync from /state.
+type GameEventData struct {
+	GameID   int `json:"gameId"`
+	Sequence int `json:"sequence"`
+}
+
+type DiceRolledData struct {
+	GameEventData
+	PlayerID int   `json:"playerId"`
+	Dice     []int `json:"dice"`
+}
+
+type CheckerMovedData struct {
+	GameEventData
+	PlayerID  int  `json:"playerId"`
+	FromPoint int  `json:"fromPoint"`
+	ToPoint   int  `json:"toPoint"`
+	DieUsed   int  `json:"dieUsed"`
+	Hit       bool `json:"hit"`
+	// Statistics of the position after the action, sent with its last move only
+	Stats *business.PositionStats `json:"stats,omitempty"`
+}
+
+type TurnChangedData struct {
+	GameEventData
+	CurrentTurn int `json:"currentTurn"`
+}
+
+type GameCompletedData struct {
+	GameEventData
+	WinnerID   int    `json:"winnerId"`
+	ResultType string `json:"resultType"`
+	PointsWon  int    `json:"pointsWon"`
+}
+
+type CubeActionData struct {
+	GameEventData
+	PlayerID  int    `json:"playerId"`
+	Action    string `json:"action"` // "double", "take", "drop", "beaver" or "raccoon"
+	CubeValue int    `json:"cubeValue"`
+	CubeOwner *int   `json:"cubeOwner"`
+}
+
+type OpeningRollData struct {
+	GameEventData
+	PlayerID       int   `json:"playerId"`
+	Die            int   `json:"die"`
+	Tie            bool  `json:"tie"`
+	StartingPlayer int   `json:"startingPlayer,omitempty"`
+	Dice           []int `json:"dice,omitempty"`
+}
+
+type MatchUpdatedData struct {
+	GameEventData
+	MatchID      int    `json:"matchId"`
+	Player1Score int    `json:"player1Score"`
+	Player2Score int    `json:"player2Score"`
+	Crawford     bool   `json:"crawford"`
+	MatchStatus  string `json:"matchStatus"`
+	WinnerID     *int   `json:"winnerId"`
+	NextGameID   *int   `json:"nextGameId"`
+}
+
+type GameForfeitedData struct {
+	GameEventData
+	ForfeitedBy int    `json:"forfeitedBy"`
+	WinnerID    int    `json:"winnerId"`
+	ResultType  string `json:"resultType"`
+	PointsWon   int    `json:"pointsWon"`
+}
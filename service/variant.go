@@ -0,0 +1,57 @@
+package service
+
+import (
+	"fmt"
+
+	"backgammon/business"
+)
+
+// Check a requested variant and return it with its starting board. Only
+// custom games take a starting board, which must then be given.
+func variantStartingBoard(name string, board []int) (business.Variant, []int, error) {
+	variant, err := business.ParseVariant(name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if variant == business.VariantCustom {
+		if board == nil {
+			return "", nil, fmt.Errorf("custom games need a startingBoard")
+		}
+		pos, err := business.CustomStartingPosition(board)
+		if err != nil {
+			return "", nil, err
+		}
+		return variant, pos.Board, nil
+	}
+
+	if board != nil {
+		return "", nil, fmt.Errorf("startingBoard is only used with custom games")
+	}
+	pos, err := business.VariantStartingPosition(variant)
+	if err != nil {
+		return "", nil, err
+	}
+	return variant, pos.Board, nil
+}
+
+// Check a requested rule set against the variant and match length, and return
+// it with the board its first game starts from. Rule sets other than backgammon
+// bring their own layouts, and a tavli match needs more than one game.
+func ruleSetStartingBoard(name string, variant business.Variant, board []int, matchLength int) (string, []int, error) {
+	ruleSet, err := business.ParseRuleSet(name)
+	if err != nil {
+		return "", nil, err
+	}
+	if ruleSet == business.RuleSetBackgammon {
+		return ruleSet, board, nil
+	}
+
+	if variant != business.VariantStandard {
+		return "", nil, fmt.Errorf("the %s rule set is only played from its own starting position", ruleSet)
+	}
+	if ruleSet == business.RuleSetTavli && matchLength == 0 {
+		return "", nil, fmt.Errorf("tavli is played as a match and needs a matchLength")
+	}
+	return ruleSet, business.GameRuleSet(ruleSet, 1).StartingPosition().Board, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"backgammon/business"
+)
+
+func TestVariantStartingBoard(t *testing.T) {
+	variant, board, err := variantStartingBoard("", nil)
+	if err != nil || variant != business.VariantStandard || !reflect.DeepEqual(board, business.StartingPosition().Board) {
+		t.Fatalf("default variant gave %s with %v, %v", variant, board, err)
+	}
+
+	hyper, _ := business.VariantStartingPosition(business.VariantHypergammon)
+	if variant, board, err := variantStartingBoard("hypergammon", nil); err != nil || variant != business.VariantHypergammon || !reflect.DeepEqual(board, hyper.Board) {
+		t.Fatalf("hypergammon gave %s with %v, %v", variant, board, err)
+	}
+
+	custom := make([]int, 24)
+	custom[5], custom[18] = 2, -2
+	if variant, board, err := variantStartingBoard("custom", custom); err != nil || variant != business.VariantCustom || !reflect.DeepEqual(board, custom) {
+		t.Fatalf("custom gave %s with %v, %v", variant, board, err)
+	}
+
+	if _, _, err := variantStartingBoard("custom", nil); err == nil {
+		t.Error("custom game accepted without a board")
+	}
+	if _, _, err := variantStartingBoard("nackgammon", custom); err == nil {
+		t.Error("starting board accepted for a fixed variant")
+	}
+	if _, _, err := variantStartingBoard("shortgammon", nil); err == nil {
+		t.Error("unknown variant accepted")
+	}
+}
+
+func TestRuleSetStartingBoard(t *testing.T) {
+	ruleSet, board, err := ruleSetStartingBoard("fevga", business.VariantStandard, nil, 0)
+	if err != nil || ruleSet != business.RuleSetFevga || !reflect.DeepEqual(board, business.Fevga{}.StartingPosition().Board) {
+		t.Fatalf("fevga gave %s with %v, %v", ruleSet, board, err)
+	}
+
+	if _, _, err := ruleSetStartingBoard("plakoto", business.VariantNackgammon, nil, 0); err == nil {
+		t.Error("plakoto accepted from a nackgammon start")
+	}
+	if _, _, err := ruleSetStartingBoard("tavli", business.VariantStandard, nil, 0); err == nil {
+		t.Error("tavli accepted without a match")
+	}
+	if ruleSet, board, err := ruleSetStartingBoard("tavli", business.VariantStandard, nil, 5); err != nil || ruleSet != business.RuleSetTavli || !reflect.DeepEqual(board, business.StartingPosition().Board) {
+		t.Fatalf("tavli match gave %s with %v, %v", ruleSet, board, err)
+	}
+}
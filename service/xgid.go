@@ -0,0 +1,268 @@
+package service
+
+import (
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+
+	"backgammon/business"
+	"backgammon/repository"
+	"backgammon/util"
+)
+
+// Describe a game's position, cube, dice and score as an eXtreme Gammon XGID
+// records them. Single games are recorded as money games.
+func gameXGID(game *repository.Game, state *repository.GameState, match *repository.Match) business.XGID {
+	m := gameMatchState(game, state, match)
+	x := business.XGID{
+		Position:      positionFromState(state),
+		CubeValue:     m.CubeValue,
+		CubeOwner:     m.CubeOwner,
+		Turn:          m.Turn,
+		Dice:          m.Dice,
+		DoubleOffered: m.DoubleOffered,
+		Beavered:      state.CubeBeavered,
+		WhiteScore:    m.WhiteScore,
+		BlackScore:    m.BlackScore,
+		MatchLength:   m.MatchLength,
+	}
+	if match != nil {
+		x.Crawford = game.Crawford
+	} else {
+		x.Beavers = game.AllowBeavers
+	}
+	return x
+}
+
+// Build the state of a game starting from an XGID, with the players of each
+// color, and return it with the player to act first
+func stateFromXGID(x business.XGID, white, black int) (*repository.GameState, int, error) {
+	players := map[business.Color]int{business.ColorWhite: white, business.ColorBlack: black}
+
+	pos := x.Position
+	if pos.BornedOffWhite == 15 || pos.BornedOffBlack == 15 {
+		return nil, 0, rejectGameAction(http.StatusBadRequest, "The position is already decided")
+	}
+	if x.Raccooned {
+		return nil, 0, rejectGameAction(http.StatusBadRequest, "Positions after a raccoon are not supported")
+	}
+
+	state := &repository.GameState{
+		BoardState:     pos.Board,
+		BarWhite:       pos.BarWhite,
+		BarBlack:       pos.BarBlack,
+		BornedOffWhite: pos.BornedOffWhite,
+		BornedOffBlack: pos.BornedOffBlack,
+		CubeValue:      x.CubeValue,
+		CubeBeavered:   x.Beavered,
+	}
+	if x.CubeOwner != "" {
+		owner := players[x.CubeOwner]
+		state.CubeOwner = &owner
+	}
+	if x.Dice[0] != 0 {
+		state.DiceRoll = business.TurnDice(x.Dice[0], x.Dice[1])
+		state.DiceUsed = make([]bool, len(state.DiceRoll))
+		state.TurnNumber = 1
+	}
+
+	// The doubler stays on turn while the opponent answers
+	currentTurn := players[x.Turn]
+	switch {
+	case x.DoubleOffered:
+		if x.CubeOwner == x.Turn {
+			return nil, 0, rejectGameAction(http.StatusBadRequest, "The doubled player cannot own the cube")
+		}
+		doubler := players[x.Turn.Opponent()]
+		state.CubeOfferedBy = &doubler
+		currentTurn = doubler
+
+	case x.Beavered:
+		if x.CubeOwner != x.Turn.Opponent() {
+			return nil, 0, rejectGameAction(http.StatusBadRequest, "The beavering player must own the cube")
+		}
+	}
+
+	return state, currentTurn, nil
+}
+
+// Export a game's current position as an XGID
+func GameXGIDHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// Parse game ID from URL path
+	gameID, err := parseGameIDFromPath(strings.TrimSuffix(r.URL.Path, "/xgid"))
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid game ID")
+		return
+	}
+
+	// Get game details to verify user is a player
+	game, err := db.GetGameByID(r.Context(), gameID)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusNotFound, "Game not found")
+		return
+	}
+
+	// Verify user is a player in this game
+	if game.Player1ID != userID && game.Player2ID != userID {
+		util.ErrorResponse(w, http.StatusForbidden, "You are not a player in this game")
+		return
+	}
+
+	// XGID only describes backgammon positions
+	if _, ok := gameRules(game).(business.Backgammon); !ok {
+		util.ErrorResponse(w, http.StatusBadRequest, "XGID is not available for this rule set")
+		return
+	}
+
+	state, err := db.GetGameState(r.Context(), gameID)
+	if err != nil {
+		log.Printf("Failed to get game state: %v", err)
+		util.ErrorResponse(w, http.StatusNotFound, "Game state not found")
+		return
+	}
+
+	// Get the match score
+	var match *repository.Match
+	if game.MatchID != nil {
+		match, err = db.GetMatchByID(r.Context(), *game.MatchID)
+		if err != nil {
+			log.Printf("Failed to get match: %v", err)
+			util.ErrorResponse(w, http.StatusInternalServerError, "Failed to get match")
+			return
+		}
+	}
+
+	util.JSONResponse(w, http.StatusOK, map[string]interface{}{
+		"gameId": gameID,
+		"xgid":   gameXGID(game, state, match).String(),
+	})
+}
+
+// Start a single game against a computer player from an XGID. The position,
+// cube, dice and Crawford rule carry over; a match score does not.
+func PracticeGameHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		util.ErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	db := repository.GetDB()
+	if db == nil {
+		util.ErrorResponse(w, http.StatusInternalServerError, "Database not initialized")
+		return
+	}
+
+	// Get current user ID from context
+	userID, ok := util.GetUserIDFromContext(r.Context())
+	if !ok {
+		util.ErrorResponse(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	var req PracticeGameRequest
+	if err := util.ParseJSONBody(r, &req); err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	x, err := business.ParseXGID(req.XGID)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	level := business.BotMedium
+	if req.Level != "" {
+		level, err = business.ParseBotLevel(req.Level)
+		if err != nil {
+			util.ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	botID, ok := botForLevel(level)
+	if !ok {
+		util.ErrorResponse(w, http.StatusServiceUnavailable, "No computer player is available at that level")
+		return
+	}
+
+	color := business.ColorWhite
+	if req.Color != "" {
+		color = business.Color(req.Color)
+		if color != business.ColorWhite && color != business.ColorBlack {
+			util.ErrorResponse(w, http.StatusBadRequest, "color must be white or black")
+			return
+		}
+	}
+
+	white, black := userID, botID
+	if color == business.ColorBlack {
+		white, black = botID, userID
+	}
+	state, currentTurn, err := stateFromXGID(x, white, black)
+	if err != nil {
+		writeGameActionError(w, err, "Failed to create game")
+		return
+	}
+
+	diceMode, err := parseDiceMode(req.DiceMode, true)
+	if err != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// The imported position is the game's starting position
+	options := repository.GameOptions{
+		AllowBeavers: x.Beavers || x.Beavered,
+		AllowHints:   true,
+		DiceMode:     diceMode,
+		Variant:      string(business.VariantCustom),
+	}
+	if diceMode == repository.DiceModeSeeded {
+		seed := rand.Int64()
+		if req.Seed != nil {
+			seed = *req.Seed
+		}
+		options.DiceSeed = repository.NewSeededDiceRoller(seed).Seed
+	} else if req.Seed != nil {
+		util.ErrorResponse(w, http.StatusBadRequest, "seed is only used with seeded dice")
+		return
+	}
+
+	gameID, err := db.CreateGameFromPosition(r.Context(), userID, botID, string(color), currentTurn, options, x.Crawford, state)
+	if err != nil {
+		log.Printf("Failed to create practice game: %v", err)
+		util.ErrorResponse(w, http.StatusInternalServerError, "Failed to create game")
+		return
+	}
+
+	// The user is now in a game
+	_ = db.LeaveLobby(r.Context(), userID)
+
+	// The bot may be the one to act first
+	triggerBots(gameID)
+
+	util.JSONResponse(w, http.StatusCreated, map[string]interface{}{
+		"gameId":     gameID,
+		"opponentId": botID,
+		"color":      color,
+		"xgid":       x.String(),
+	})
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+
+	"backgammon/business"
+	"backgammon/repository"
+)
+
+func TestGameXGIDDice(t *testing.T) {
+	game := &repository.Game{
+		Player1ID:    1,
+		Player2ID:    2,
+		Player1Color: "white",
+		Player2Color: "black",
+		CurrentTurn:  1,
+		GameStatus:   "in_progress",
+	}
+
+	tests := []struct {
+		name     string
+		dice     []int
+		diceUsed []bool
+		want     [2]int
+	}{
+		{"before rolling", nil, nil, [2]int{}},
+		{"nothing played", []int{6, 5}, []bool{false, false}, [2]int{6, 5}},
+		{"one die played", []int{6, 5}, []bool{true, false}, [2]int{}},
+		{"doubles partly played", []int{4, 4, 4, 4}, []bool{true, true, false, false}, [2]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &repository.GameState{
+				BoardState: business.StartingPosition().Board,
+				CubeValue:  1,
+				DiceRoll:   tt.dice,
+				DiceUsed:   tt.diceUsed,
+			}
+			if got := gameXGID(game, state, nil).Dice; got != tt.want {
+				t.Fatalf("got dice %v, want %v", got, tt.want)
+			}
+		})
+	}
+}